@@ -0,0 +1,531 @@
+// +build experimental
+
+package composefile
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	shlex "github.com/flynn-archive/go-shlex"
+	"gopkg.in/yaml.v2"
+)
+
+// Config stores the contents of a Compose file
+type Config struct {
+	Version  string
+	Services map[string]Service
+	Networks map[string]Network
+	Volumes  map[string]Volume
+}
+
+// Service is a service from a Compose file
+type Service struct {
+	Image           string
+	Command         ShellCommand
+	Entrypoint      ShellCommand
+	Environment     Environment
+	Labels          Labels
+	Ports           []string
+	Volumes         []string
+	Networks        ServiceNetworks
+	WorkingDir      string         `yaml:"working_dir"`
+	User            string
+	StopGracePeriod *time.Duration `yaml:"stop_grace_period"`
+	Logging         *Logging
+	Deploy          Deploy
+}
+
+// Deploy holds the swarm specific settings of a service
+type Deploy struct {
+	Mode          string
+	Replicas      *uint64
+	Labels        Labels
+	UpdateConfig  *UpdateConfig  `yaml:"update_config"`
+	Resources     Resources
+	RestartPolicy *RestartPolicy `yaml:"restart_policy"`
+	Placement     Placement
+}
+
+// UpdateConfig is the rolling update configuration of a service
+type UpdateConfig struct {
+	Parallelism   uint64
+	Delay         time.Duration
+	FailureAction string `yaml:"failure_action"`
+}
+
+// Resources holds the resource limits and reservations of a service
+type Resources struct {
+	Limits       *Resource
+	Reservations *Resource
+}
+
+// Resource is a CPU and memory amount
+type Resource struct {
+	CPUs   string `yaml:"cpus"`
+	Memory UnitBytes
+}
+
+// RestartPolicy is the restart policy of a service
+type RestartPolicy struct {
+	Condition   string
+	Delay       *time.Duration
+	MaxAttempts *uint64 `yaml:"max_attempts"`
+	Window      *time.Duration
+}
+
+// Placement holds the scheduling constraints of a service
+type Placement struct {
+	Constraints []string
+}
+
+// Logging is the log driver configuration of a service
+type Logging struct {
+	Driver  string
+	Options map[string]string
+}
+
+// ServiceNetwork holds the per-service settings of a network attachment
+type ServiceNetwork struct {
+	Aliases []string
+}
+
+// Network is a network as defined in a Compose file
+type Network struct {
+	Driver     string
+	DriverOpts map[string]string `yaml:"driver_opts"`
+	Labels     Labels
+	Internal   bool
+	External   External
+}
+
+// Volume is a named volume as defined in a Compose file
+type Volume struct {
+	Driver     string
+	DriverOpts map[string]string `yaml:"driver_opts"`
+	Labels     Labels
+	External   External
+}
+
+// External marks a network or volume as created outside of the stack
+type External struct {
+	Name     string
+	External bool
+}
+
+// UnmarshalYAML accepts either a boolean or a mapping with a name
+func (e *External) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var external bool
+	if err := unmarshal(&external); err == nil {
+		e.External = external
+		return nil
+	}
+	var named struct {
+		Name string
+	}
+	if err := unmarshal(&named); err != nil {
+		return fmt.Errorf("external must be a boolean or a mapping with a name")
+	}
+	e.External = true
+	e.Name = named.Name
+	return nil
+}
+
+// ShellCommand is a command given either as a string or as a list
+type ShellCommand []string
+
+// UnmarshalYAML splits a string command the way a shell would
+func (c *ShellCommand) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*c = list
+		return nil
+	}
+	var command string
+	if err := unmarshal(&command); err != nil {
+		return fmt.Errorf("command must be a string or a list of strings")
+	}
+	args, err := shlex.Split(command)
+	if err != nil {
+		return err
+	}
+	*c = args
+	return nil
+}
+
+// Environment is a list of KEY=value strings given either as a list or
+// as a mapping. Keys without a value are kept as is.
+type Environment []string
+
+// UnmarshalYAML accepts either a list or a mapping
+func (e *Environment) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*e = list
+		return nil
+	}
+	mapping, err := unmarshalMapping(unmarshal)
+	if err != nil {
+		return fmt.Errorf("environment must be a list or a mapping")
+	}
+	env := Environment{}
+	for _, key := range sortedKeys(mapping) {
+		if value := mapping[key]; value != nil {
+			env = append(env, fmt.Sprintf("%s=%s", key, *value))
+		} else {
+			env = append(env, key)
+		}
+	}
+	*e = env
+	return nil
+}
+
+// Labels is a set of labels given either as a list of key=value strings or
+// as a mapping
+type Labels map[string]string
+
+// UnmarshalYAML accepts either a list or a mapping
+func (l *Labels) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	labels := Labels{}
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		for _, label := range list {
+			parts := strings.SplitN(label, "=", 2)
+			if len(parts) == 1 {
+				labels[parts[0]] = ""
+			} else {
+				labels[parts[0]] = parts[1]
+			}
+		}
+		*l = labels
+		return nil
+	}
+	mapping, err := unmarshalMapping(unmarshal)
+	if err != nil {
+		return fmt.Errorf("labels must be a list or a mapping")
+	}
+	for key, value := range mapping {
+		if value != nil {
+			labels[key] = *value
+		} else {
+			labels[key] = ""
+		}
+	}
+	*l = labels
+	return nil
+}
+
+// ServiceNetworks are the networks a service is attached to, given either
+// as a list of names or as a mapping of names to settings
+type ServiceNetworks map[string]*ServiceNetwork
+
+// UnmarshalYAML accepts either a list or a mapping
+func (n *ServiceNetworks) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	networks := ServiceNetworks{}
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		for _, name := range list {
+			networks[name] = nil
+		}
+		*n = networks
+		return nil
+	}
+	var mapping map[string]*ServiceNetwork
+	if err := unmarshal(&mapping); err != nil {
+		return fmt.Errorf("networks must be a list or a mapping")
+	}
+	for name, network := range mapping {
+		networks[name] = network
+	}
+	*n = networks
+	return nil
+}
+
+// UnitBytes is an amount of memory such as "512M" or "2g"
+type UnitBytes int64
+
+// UnmarshalYAML parses a human readable amount of memory
+func (u *UnitBytes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	bytes, err := units.RAMInBytes(value)
+	if err != nil {
+		return err
+	}
+	*u = UnitBytes(bytes)
+	return nil
+}
+
+// unmarshalMapping decodes a mapping whose values may be scalars of any
+// type, or null.
+func unmarshalMapping(unmarshal func(interface{}) error) (map[string]*string, error) {
+	var raw map[string]interface{}
+	if err := unmarshal(&raw); err != nil {
+		return nil, err
+	}
+	mapping := make(map[string]*string, len(raw))
+	for key, value := range raw {
+		if value == nil {
+			mapping[key] = nil
+			continue
+		}
+		str := fmt.Sprint(value)
+		mapping[key] = &str
+	}
+	return mapping, nil
+}
+
+func sortedKeys(mapping map[string]*string) []string {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unsupportedServiceKeys are Compose options that have no equivalent in a
+// swarm service. They are reported, but do not prevent loading the file.
+var unsupportedServiceKeys = []string{
+	"build",
+	"cap_add",
+	"cap_drop",
+	"cgroup_parent",
+	"container_name",
+	"depends_on",
+	"devices",
+	"dns",
+	"dns_search",
+	"domainname",
+	"external_links",
+	"extra_hosts",
+	"hostname",
+	"ipc",
+	"links",
+	"mac_address",
+	"network_mode",
+	"privileged",
+	"read_only",
+	"restart",
+	"security_opt",
+	"shm_size",
+	"stdin_open",
+	"tmpfs",
+	"tty",
+	"ulimits",
+}
+
+// LoadFile loads a Compose file (version 3) from a reader. Variables such as
+// ${VAR} in values are substituted using lookupEnv. Options that can't be
+// expressed by a swarm service are returned as warnings.
+func LoadFile(reader io.Reader, lookupEnv func(string) (string, bool)) (*Config, []string, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("YAML syntax error: %v", err)
+	}
+	if raw == nil {
+		return nil, nil, fmt.Errorf("Compose file is empty")
+	}
+
+	if _, ok := raw["version"]; !ok {
+		return nil, nil, fmt.Errorf("Compose file has no version, version 3 is required")
+	}
+	version := fmt.Sprint(raw["version"])
+	if version != "3" && !strings.HasPrefix(version, "3.") {
+		return nil, nil, fmt.Errorf(
+			"Unsupported Compose file version: %s. The only version supported is 3 (or 3.x)",
+			version)
+	}
+
+	interpolated, err := interpolate(raw, lookupEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings := unsupportedWarnings(interpolated.(map[interface{}]interface{}))
+
+	// Round-trip through YAML so the interpolated values are decoded with
+	// the same rules as the original document.
+	data, err = yaml.Marshal(interpolated)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, nil, fmt.Errorf("Invalid Compose file: %v", err)
+	}
+
+	for name, service := range config.Services {
+		if service.Image == "" {
+			return nil, nil, fmt.Errorf("Service %s has no image", name)
+		}
+	}
+	return config, warnings, nil
+}
+
+func unsupportedWarnings(raw map[interface{}]interface{}) []string {
+	var warnings []string
+	services, _ := raw["services"].(map[interface{}]interface{})
+	for name, service := range services {
+		options, ok := service.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range unsupportedServiceKeys {
+			if _, exists := options[key]; exists {
+				warnings = append(warnings, fmt.Sprintf(
+					"Ignoring unsupported option %q of service %s", key, name))
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// interpolate substitutes variables in every string value of the document.
+func interpolate(value interface{}, lookupEnv func(string) (string, bool)) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		s, err := substitute(v, lookupEnv)
+		if err != nil || s == v {
+			return s, err
+		}
+		return resolveScalar(s), nil
+	case map[interface{}]interface{}:
+		out := make(map[interface{}]interface{}, len(v))
+		for key, elem := range v {
+			interpolated, err := interpolate(elem, lookupEnv)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = interpolated
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[interface{}]interface{}, len(v))
+		for key, elem := range v {
+			interpolated, err := interpolate(elem, lookupEnv)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = interpolated
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			interpolated, err := interpolate(elem, lookupEnv)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = interpolated
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// resolveScalar gives a substituted value the type it would have had if it
+// had been written in the file, so that "replicas: ${REPLICAS}" is decoded as
+// a number. Values which would not be written back the same, such as "1.10"
+// or "yes", are kept as strings.
+func resolveScalar(s string) interface{} {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(s), &value); err != nil {
+		return s
+	}
+	switch value.(type) {
+	case bool, int, int64, uint64, float64:
+		out, err := yaml.Marshal(value)
+		if err == nil && strings.TrimSpace(string(out)) == s {
+			return value
+		}
+	}
+	return s
+}
+
+// substitute replaces $VAR, ${VAR} and ${VAR:-default} in s. A literal
+// dollar sign is written as $$.
+func substitute(s string, lookupEnv func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			out = append(out, s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("Invalid interpolation format in %q", s)
+		}
+		switch next := s[i+1]; {
+		case next == '$':
+			out = append(out, '$')
+			i++
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("Invalid interpolation format in %q", s)
+			}
+			expr := s[i+2 : i+2+end]
+			name, defaultValue, hasDefault := expr, "", false
+			if sep := strings.Index(expr, ":-"); sep >= 0 {
+				name, defaultValue, hasDefault = expr[:sep], expr[sep+2:], true
+			}
+			if !isVariableName(name) {
+				return "", fmt.Errorf("Invalid interpolation format in %q", s)
+			}
+			value, ok := lookupEnv(name)
+			if (!ok || value == "") && hasDefault {
+				value = defaultValue
+			}
+			out = append(out, value...)
+			i += end + 2
+		default:
+			end := i + 1
+			for end < len(s) && isVariableChar(s[end], end == i+1) {
+				end++
+			}
+			if end == i+1 {
+				return "", fmt.Errorf("Invalid interpolation format in %q", s)
+			}
+			value, _ := lookupEnv(s[i+1 : end])
+			out = append(out, value...)
+			i = end - 1
+		}
+	}
+	return string(out), nil
+}
+
+func isVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isVariableChar(name[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isVariableChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+// +build experimental
+
+package composefile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func lookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestLoadFileSuccess(t *testing.T) {
+	reader := strings.NewReader(`
+version: "3"
+services:
+  web:
+    image: nginx:${TAG}
+    command: nginx -g "daemon off;"
+    environment:
+      FOO: bar
+      DEBUG:
+    labels:
+      - com.example.role=frontend
+    ports:
+      - "8080:80"
+    volumes:
+      - data:/var/lib/data:ro
+    networks:
+      front:
+        aliases: [www]
+    stop_grace_period: 20s
+    deploy:
+      replicas: 3
+      update_config:
+        parallelism: 2
+        delay: 10s
+      resources:
+        limits:
+          cpus: "0.5"
+          memory: 50M
+      restart_policy:
+        condition: on-failure
+        max_attempts: 3
+      placement:
+        constraints: [node.role == worker]
+networks:
+  front:
+    driver: overlay
+volumes:
+  data:
+    external:
+      name: shared-data
+`)
+
+	config, warnings, err := LoadFile(reader, lookupEnv(map[string]string{"TAG": "1.11"}))
+	assert.NilError(t, err)
+	assert.Equal(t, len(warnings), 0)
+	assert.Equal(t, len(config.Services), 1)
+
+	web := config.Services["web"]
+	assert.Equal(t, web.Image, "nginx:1.11")
+	assert.EqualStringSlice(t, web.Command, []string{"nginx", "-g", "daemon off;"})
+	assert.EqualStringSlice(t, web.Environment, []string{"DEBUG", "FOO=bar"})
+	assert.Equal(t, web.Labels["com.example.role"], "frontend")
+	assert.EqualStringSlice(t, web.Ports, []string{"8080:80"})
+	assert.EqualStringSlice(t, web.Networks["front"].Aliases, []string{"www"})
+	assert.Equal(t, *web.StopGracePeriod, 20*time.Second)
+	assert.Equal(t, *web.Deploy.Replicas, uint64(3))
+	assert.Equal(t, web.Deploy.UpdateConfig.Delay, 10*time.Second)
+	assert.Equal(t, web.Deploy.Resources.Limits.CPUs, "0.5")
+	assert.Equal(t, web.Deploy.Resources.Limits.Memory, UnitBytes(50*1024*1024))
+	assert.Equal(t, *web.Deploy.RestartPolicy.MaxAttempts, uint64(3))
+	assert.EqualStringSlice(t, web.Deploy.Placement.Constraints, []string{"node.role == worker"})
+
+	assert.Equal(t, config.Networks["front"].Driver, "overlay")
+	assert.Equal(t, config.Volumes["data"].External.External, true)
+	assert.Equal(t, config.Volumes["data"].External.Name, "shared-data")
+}
+
+func TestLoadFileServiceNetworksList(t *testing.T) {
+	reader := strings.NewReader(`
+version: "3.0"
+services:
+  db:
+    image: postgres
+    networks: [back]
+`)
+
+	config, _, err := LoadFile(reader, lookupEnv(nil))
+	assert.NilError(t, err)
+	networks := config.Services["db"].Networks
+	assert.Equal(t, len(networks), 1)
+	_, ok := networks["back"]
+	assert.Equal(t, ok, true)
+}
+
+func TestLoadFileInterpolateTypedValues(t *testing.T) {
+	reader := strings.NewReader(`
+version: "3"
+services:
+  web:
+    image: nginx:${TAG}
+    environment:
+      RETRIES: ${RETRIES}
+    deploy:
+      replicas: ${REPLICAS}
+      update_config:
+        parallelism: ${PARALLELISM:-1}
+networks:
+  front:
+    internal: ${INTERNAL}
+`)
+
+	env := map[string]string{
+		"TAG":      "1.10",
+		"RETRIES":  "5",
+		"REPLICAS": "4",
+		"INTERNAL": "true",
+	}
+	config, _, err := LoadFile(reader, lookupEnv(env))
+	assert.NilError(t, err)
+
+	web := config.Services["web"]
+	assert.Equal(t, web.Image, "nginx:1.10")
+	assert.EqualStringSlice(t, web.Environment, []string{"RETRIES=5"})
+	assert.Equal(t, *web.Deploy.Replicas, uint64(4))
+	assert.Equal(t, web.Deploy.UpdateConfig.Parallelism, uint64(1))
+	assert.Equal(t, config.Networks["front"].Internal, true)
+}
+
+func TestLoadFileInterpolateInvalidType(t *testing.T) {
+	reader := strings.NewReader(`
+version: "3"
+services:
+  web:
+    image: nginx
+    deploy:
+      replicas: ${REPLICAS}
+`)
+
+	_, _, err := LoadFile(reader, lookupEnv(map[string]string{"REPLICAS": "many"}))
+	assert.Error(t, err, "Invalid Compose file")
+}
+
+func TestResolveScalar(t *testing.T) {
+	for input, expected := range map[string]interface{}{
+		"3":      3,
+		"true":   true,
+		"0.5":    0.5,
+		"1.10":   "1.10",
+		"yes":    "yes",
+		"010":    "010",
+		"a: b":   "a: b",
+		"nginx":  "nginx",
+		"[1, 2]": "[1, 2]",
+	} {
+		assert.Equal(t, resolveScalar(input), expected)
+	}
+}
+
+func TestLoadFileUnsupportedVersion(t *testing.T) {
+	reader := strings.NewReader(`
+version: "2"
+services:
+  web:
+    image: nginx
+`)
+
+	_, _, err := LoadFile(reader, lookupEnv(nil))
+	assert.Error(t, err, "Unsupported Compose file version: 2")
+}
+
+func TestLoadFileMissingImage(t *testing.T) {
+	reader := strings.NewReader(`
+version: "3"
+services:
+  web:
+    command: echo
+`)
+
+	_, _, err := LoadFile(reader, lookupEnv(nil))
+	assert.Error(t, err, "Service web has no image")
+}
+
+func TestLoadFileUnsupportedOptions(t *testing.T) {
+	reader := strings.NewReader(`
+version: "3"
+services:
+  web:
+    image: nginx
+    build: .
+    links: [db]
+`)
+
+	_, warnings, err := LoadFile(reader, lookupEnv(nil))
+	assert.NilError(t, err)
+	assert.EqualStringSlice(t, warnings, []string{
+		`Ignoring unsupported option "build" of service web`,
+		`Ignoring unsupported option "links" of service web`,
+	})
+}
+
+func TestSubstitute(t *testing.T) {
+	env := lookupEnv(map[string]string{"FOO": "foo", "EMPTY": ""})
+
+	for input, expected := range map[string]string{
+		"plain":                  "plain",
+		"$FOO":                   "foo",
+		"${FOO}bar":              "foobar",
+		"${MISSING:-default}":    "default",
+		"${EMPTY:-default}":      "default",
+		"$$FOO":                  "$FOO",
+		"a ${FOO} and $MISSING.": "a foo and .",
+	} {
+		actual, err := substitute(input, env)
+		assert.NilError(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	_, err := substitute("${FOO", env)
+	assert.Error(t, err, "Invalid interpolation format")
+}
@@ -23,6 +23,7 @@ type commitOptions struct {
 	author  string
 	changes dockeropts.ListOpts
 	config  string
+	squash  bool
 }
 
 // NewCommitCommand creats a new cobra.Command for `docker commit`
@@ -48,6 +49,7 @@ func NewCommitCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags.BoolVarP(&opts.pause, "pause", "p", true, "Pause container during commit")
 	flags.StringVarP(&opts.comment, "message", "m", "", "Commit message")
 	flags.StringVarP(&opts.author, "author", "a", "", "Author (e.g., \"John Hannibal Smith <hannibal@a-team.com>\")")
+	flags.BoolVar(&opts.squash, "squash", false, "Squash all the layers of the image into a single layer")
 
 	opts.changes = dockeropts.NewListOpts(nil)
 	flags.VarP(&opts.changes, "change", "c", "Apply Dockerfile instruction to the created image")
@@ -80,6 +82,7 @@ func runCommit(dockerCli *client.DockerCli, opts *commitOptions) error {
 		Changes:   opts.changes.GetAll(),
 		Pause:     opts.pause,
 		Config:    config,
+		Squash:    opts.squash,
 	}
 
 	response, err := dockerCli.Client().ContainerCommit(ctx, name, options)
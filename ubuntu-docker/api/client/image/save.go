@@ -8,12 +8,14 @@ import (
 
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
 	"github.com/spf13/cobra"
 )
 
 type saveOptions struct {
 	images []string
 	output string
+	format string
 }
 
 // NewSaveCommand creates a new `docker save` command
@@ -33,6 +35,7 @@ func NewSaveCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVar(&opts.format, "format", "docker", "Format of the archive (docker or oci)")
 
 	return cmd
 }
@@ -42,7 +45,7 @@ func runSave(dockerCli *client.DockerCli, opts saveOptions) error {
 		return errors.New("Cowardly refusing to save to a terminal. Use the -o flag or redirect.")
 	}
 
-	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images)
+	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images, types.ImageSaveOptions{Format: opts.format})
 	if err != nil {
 		return err
 	}
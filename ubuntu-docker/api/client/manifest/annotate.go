@@ -0,0 +1,81 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/spf13/cobra"
+)
+
+type annotateOptions struct {
+	list       string
+	manifest   string
+	os         string
+	arch       string
+	variant    string
+	osFeatures []string
+	features   []string
+}
+
+func newAnnotateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts annotateOptions
+
+	cmd := &cobra.Command{
+		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
+		Short: "Set the platform of a manifest of a local manifest list",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			opts.manifest = args[1]
+			return runAnnotate(dockerCli, cmd, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.os, "os", "", "Set the operating system")
+	flags.StringVar(&opts.arch, "arch", "", "Set the architecture")
+	flags.StringVar(&opts.variant, "variant", "", "Set the architecture variant")
+	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set the required operating system features")
+	flags.StringSliceVar(&opts.features, "features", []string{}, "Set the required CPU features")
+
+	return cmd
+}
+
+func runAnnotate(dockerCli *client.DockerCli, cmd *cobra.Command, opts annotateOptions) error {
+	list, err := parseListRef(opts.list)
+	if err != nil {
+		return err
+	}
+	ref, err := reference.ParseNamed(opts.manifest)
+	if err != nil {
+		return err
+	}
+	m, err := loadManifest(list, reference.WithDefaultTag(ref))
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("os") {
+		m.Platform.OS = opts.os
+	}
+	if flags.Changed("arch") {
+		m.Platform.Architecture = opts.arch
+	}
+	if flags.Changed("variant") {
+		m.Platform.Variant = opts.variant
+	}
+	if flags.Changed("os-features") {
+		m.Platform.OSFeatures = opts.osFeatures
+	}
+	if flags.Changed("features") {
+		m.Platform.Features = opts.features
+	}
+	if m.Platform.OS == "" || m.Platform.Architecture == "" {
+		return fmt.Errorf("manifest %s must have an operating system and an architecture", m.Ref)
+	}
+
+	return saveManifest(list, m)
+}
@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+)
+
+// NewManifestCommand returns a cobra command for `manifest` subcommands
+func NewManifestCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest COMMAND",
+		Short: "Manage Docker image manifest lists",
+		Long:  manifestDescription,
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(dockerCli.Err(), "\n"+cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(
+		newAnnotateCommand(dockerCli),
+		newCreateCommand(dockerCli),
+		newInspectCommand(dockerCli),
+		newPushCommand(dockerCli),
+	)
+	return cmd
+}
+
+var manifestDescription = `
+The **docker manifest** command has subcommands for publishing images built
+for several platforms under a single name, as a manifest list.
+
+A manifest list is first created locally from image manifests already pushed to
+a registry, the platforms of its manifests can then be annotated, and the list
+is finally pushed to the registry of its manifests.
+
+To see help for a subcommand, use:
+
+    docker manifest CMD help
+
+For full details on using docker manifest visit Docker's online documentation.
+
+`
@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/distribution"
+	"github.com/docker/docker/reference"
+	"github.com/spf13/cobra"
+)
+
+type createOptions struct {
+	list      string
+	manifests []string
+	amend     bool
+	insecure  bool
+}
+
+func newCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts createOptions
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] MANIFEST_LIST MANIFEST [MANIFEST...]",
+		Short: "Create a local manifest list to annotate and push",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			opts.manifests = args[1:]
+			return runCreate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.amend, "amend", "a", false, "Add the manifests to an existing manifest list")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+func runCreate(dockerCli *client.DockerCli, opts createOptions) error {
+	ctx := context.Background()
+
+	list, err := parseListRef(opts.list)
+	if err != nil {
+		return err
+	}
+	if listExists(list) && !opts.amend {
+		return fmt.Errorf("manifest list %s already exists, use --amend to add manifests to it", list.String())
+	}
+
+	// All the manifests are fetched before the list is stored, so that a
+	// missing manifest doesn't leave a partial list behind.
+	var manifests []storedManifest
+	for _, name := range opts.manifests {
+		ref, err := reference.ParseNamed(name)
+		if err != nil {
+			return err
+		}
+		ref = reference.WithDefaultTag(ref)
+
+		config, err := manifestListConfig(ctx, dockerCli, ref, opts.insecure)
+		if err != nil {
+			return err
+		}
+		entry, err := distribution.GetManifestListEntry(ctx, ref, config)
+		if err != nil {
+			return fmt.Errorf("failed to get the manifest of %s: %v", ref.String(), err)
+		}
+		if entry.Platform.OS == "" {
+			fmt.Fprintf(dockerCli.Err(), "Warning: the operating system of %s is unknown, set it with docker manifest annotate --os\n", ref.String())
+		}
+		manifests = append(manifests, storedManifest{Ref: ref.String(), ManifestListEntry: entry})
+	}
+
+	for _, m := range manifests {
+		if err := saveManifest(list, m); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(dockerCli.Out(), "Created manifest list %s\n", list.String())
+	return nil
+}
@@ -0,0 +1,77 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	list     string
+	manifest string
+}
+
+func newInspectCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect MANIFEST_LIST [MANIFEST]",
+		Short: "Display a local manifest list or one of its manifests",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			if len(args) > 1 {
+				opts.manifest = args[1]
+			}
+			return runInspect(dockerCli, opts)
+		},
+	}
+
+	return cmd
+}
+
+func runInspect(dockerCli *client.DockerCli, opts inspectOptions) error {
+	list, err := parseListRef(opts.list)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	if opts.manifest != "" {
+		ref, err := reference.ParseNamed(opts.manifest)
+		if err != nil {
+			return err
+		}
+		m, err := loadManifest(list, reference.WithDefaultTag(ref))
+		if err != nil {
+			return err
+		}
+		if content, err = json.MarshalIndent(m, "", "    "); err != nil {
+			return err
+		}
+	} else {
+		manifests, err := loadManifests(list)
+		if err != nil {
+			return err
+		}
+		var descriptors []manifestlist.ManifestDescriptor
+		for _, m := range manifests {
+			descriptors = append(descriptors, m.ManifestDescriptor)
+		}
+		deserialized, err := manifestlist.FromDescriptors(descriptors)
+		if err != nil {
+			return err
+		}
+		if _, content, err = deserialized.Payload(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(dockerCli.Out(), string(content))
+	return nil
+}
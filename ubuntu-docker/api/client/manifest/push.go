@@ -0,0 +1,74 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/distribution"
+	"github.com/spf13/cobra"
+)
+
+type pushOptions struct {
+	list     string
+	insecure bool
+	purge    bool
+}
+
+func newPushCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts pushOptions
+
+	cmd := &cobra.Command{
+		Use:   "push [OPTIONS] MANIFEST_LIST",
+		Short: "Push a manifest list to a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			return runPush(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	flags.BoolVarP(&opts.purge, "purge", "p", false, "Remove the local manifest list after push")
+
+	return cmd
+}
+
+func runPush(dockerCli *client.DockerCli, opts pushOptions) error {
+	ctx := context.Background()
+
+	list, err := parseListRef(opts.list)
+	if err != nil {
+		return err
+	}
+	manifests, err := loadManifests(list)
+	if err != nil {
+		return err
+	}
+
+	var entries []distribution.ManifestListEntry
+	for _, m := range manifests {
+		if m.Platform.OS == "" || m.Platform.Architecture == "" {
+			return fmt.Errorf("manifest %s has no platform, set it with docker manifest annotate", m.Ref)
+		}
+		entries = append(entries, m.ManifestListEntry)
+	}
+
+	config, err := manifestListConfig(ctx, dockerCli, list, opts.insecure)
+	if err != nil {
+		return err
+	}
+	dgst, err := distribution.PushManifestList(ctx, list, entries, config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "%s: digest: %s\n", list.String(), dgst)
+
+	if opts.purge {
+		return removeList(list)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/distribution"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+)
+
+// parseListRef parses the name of a manifest list, which is tagged "latest"
+// when it has no tag.
+func parseListRef(name string) (reference.NamedTagged, error) {
+	ref, err := reference.ParseNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, isCanonical := ref.(reference.Canonical); isCanonical {
+		return nil, fmt.Errorf("manifest list %s must be referenced by tag, not by digest", name)
+	}
+	tagged, ok := reference.WithDefaultTag(ref).(reference.NamedTagged)
+	if !ok {
+		return nil, fmt.Errorf("invalid manifest list name %s", name)
+	}
+	return tagged, nil
+}
+
+// manifestListConfig returns the configuration to talk to the registry of
+// ref, with the credentials stored by docker login. The registry is only
+// accessed over plain HTTP or with an unverified certificate if insecure is
+// set, the client doesn't know the insecure registries of the daemon.
+func manifestListConfig(ctx context.Context, dockerCli *client.DockerCli, ref reference.Named, insecure bool) (*distribution.ManifestListConfig, error) {
+	var options registry.ServiceOptions
+	if insecure {
+		options.InsecureRegistries = []string{ref.Hostname()}
+	}
+	registryService := registry.NewService(options)
+
+	repoInfo, err := registryService.ResolveRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+	authConfig := dockerCli.ResolveAuthConfig(ctx, repoInfo.Index)
+
+	return &distribution.ManifestListConfig{
+		AuthConfig:      &authConfig,
+		RegistryService: registryService,
+	}, nil
+}
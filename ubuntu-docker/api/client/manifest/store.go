@@ -0,0 +1,95 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/docker/distribution"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/reference"
+)
+
+// storedManifest is a manifest of a manifest list being assembled.
+type storedManifest struct {
+	// Ref is the reference the manifest was added as.
+	Ref string `json:"ref"`
+	distribution.ManifestListEntry
+}
+
+// listDir returns the directory the manifests of the list are stored in,
+// until it is pushed.
+func listDir(list reference.Named) string {
+	return filepath.Join(cliconfig.ConfigDir(), "manifests", url.QueryEscape(list.String()))
+}
+
+func listExists(list reference.Named) bool {
+	_, err := os.Stat(listDir(list))
+	return err == nil
+}
+
+func loadManifests(list reference.Named) ([]storedManifest, error) {
+	files, err := ioutil.ReadDir(listDir(list))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no manifest list %s, create it with docker manifest create", list.String())
+		}
+		return nil, err
+	}
+
+	var manifests []storedManifest
+	for _, f := range files {
+		content, err := ioutil.ReadFile(filepath.Join(listDir(list), f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var m storedManifest
+		if err := json.Unmarshal(content, &m); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s of %s: %v", f.Name(), list.String(), err)
+		}
+		manifests = append(manifests, m)
+	}
+	sort.Sort(byRef(manifests))
+	return manifests, nil
+}
+
+func loadManifest(list, ref reference.Named) (storedManifest, error) {
+	content, err := ioutil.ReadFile(filepath.Join(listDir(list), url.QueryEscape(ref.String())))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return storedManifest{}, fmt.Errorf("manifest list %s has no manifest %s", list.String(), ref.String())
+		}
+		return storedManifest{}, err
+	}
+	var m storedManifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return storedManifest{}, err
+	}
+	return m, nil
+}
+
+func saveManifest(list reference.Named, m storedManifest) error {
+	if err := os.MkdirAll(listDir(list), 0700); err != nil {
+		return err
+	}
+	content, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(listDir(list), url.QueryEscape(m.Ref)), content, 0600)
+}
+
+func removeList(list reference.Named) error {
+	return os.RemoveAll(listDir(list))
+}
+
+type byRef []storedManifest
+
+func (r byRef) Len() int           { return len(r) }
+func (r byRef) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byRef) Less(i, j int) bool { return r[i].Ref < r[j].Ref }
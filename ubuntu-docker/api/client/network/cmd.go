@@ -25,6 +25,7 @@ func NewNetworkCommand(dockerCli *client.DockerCli) *cobra.Command {
 		newDisconnectCommand(dockerCli),
 		newInspectCommand(dockerCli),
 		newListCommand(dockerCli),
+		newPolicyCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 	)
 	return cmd
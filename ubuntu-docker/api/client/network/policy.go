@@ -0,0 +1,39 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+func newPolicyCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy COMMAND",
+		Short: "Manage network policies",
+		Long:  policyDescription,
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(dockerCli.Err(), "\n"+cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(
+		newPolicyCreateCommand(dockerCli),
+		newPolicyListCommand(dockerCli),
+		newPolicyRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+var policyDescription = `
+The **docker network policy** command has subcommands for managing the
+policies of a network. A policy allows the traffic from the containers whose
+labels match its **--from** selector to the containers whose labels match its
+**--to** selector, on a network which disables inter container communication.
+
+Policies are supported by the **bridge** driver, on networks created with
+**-o com.docker.network.bridge.enable_icc=false**, or on the default bridge
+network of a daemon started with **--icc=false**.
+
+`
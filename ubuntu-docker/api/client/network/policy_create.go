@@ -0,0 +1,109 @@
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type policyCreateOptions struct {
+	network string
+	name    string
+	from    opts.ListOpts
+	to      opts.ListOpts
+	port    string
+}
+
+func newPolicyCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	opts := policyCreateOptions{
+		from: opts.NewListOpts(opts.ValidateLabel),
+		to:   opts.NewListOpts(opts.ValidateLabel),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] NETWORK POLICY",
+		Short: "Allow traffic between the containers of a network",
+		Long:  policyCreateDescription,
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.network = args[0]
+			opts.name = args[1]
+			return runPolicyCreate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Var(&opts.from, "from", "Label of the source containers (key=value)")
+	flags.Var(&opts.to, "to", "Label of the destination containers (key=value)")
+	flags.StringVar(&opts.port, "port", "", "Destination port or protocol (PORT[/PROTOCOL] or PROTOCOL)")
+
+	return cmd
+}
+
+func runPolicyCreate(dockerCli *client.DockerCli, opts policyCreateOptions) error {
+	proto, port, err := parsePolicyPort(opts.port)
+	if err != nil {
+		return err
+	}
+
+	policy := types.NetworkPolicy{
+		Name:     opts.name,
+		From:     runconfigopts.ConvertKVStringsToMap(opts.from.GetAll()),
+		To:       runconfigopts.ConvertKVStringsToMap(opts.to.GetAll()),
+		Protocol: proto,
+		Port:     port,
+	}
+	if err := dockerCli.Client().NetworkPolicyCreate(context.Background(), opts.network, policy); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s\n", opts.name)
+	return nil
+}
+
+// parsePolicyPort parses the --port flag of a policy, which is either a
+// port with an optional protocol defaulting to tcp, or a protocol alone.
+func parsePolicyPort(value string) (string, uint16, error) {
+	if value == "" {
+		return "", 0, nil
+	}
+
+	parts := strings.SplitN(value, "/", 2)
+	p, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); len(parts) == 2 || ok && numErr.Err == strconv.ErrRange {
+			return "", 0, fmt.Errorf("invalid port %s", value)
+		}
+		// a protocol alone
+		return strings.ToLower(value), 0, nil
+	}
+	if p == 0 {
+		return "", 0, fmt.Errorf("invalid port %s", value)
+	}
+
+	proto := "tcp"
+	if len(parts) == 2 {
+		proto = strings.ToLower(parts[1])
+	}
+	return proto, uint16(p), nil
+}
+
+var policyCreateDescription = `
+Adds a policy allowing the traffic from the containers matching all the
+**--from** labels to the containers matching all the **--to** labels. A
+selector without labels matches all the containers of the network. The
+traffic can be restricted to a destination port, or to a protocol, with
+**--port**.
+
+The policy applies to the containers already connected to the network, and
+is re-evaluated each time a container connects or disconnects.
+`
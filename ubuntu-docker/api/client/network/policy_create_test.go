@@ -0,0 +1,33 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func TestParsePolicyPort(t *testing.T) {
+	for _, c := range []struct {
+		value string
+		proto string
+		port  uint16
+	}{
+		{"", "", 0},
+		{"5432", "tcp", 5432},
+		{"5432/tcp", "tcp", 5432},
+		{"53/UDP", "udp", 53},
+		{"icmp", "icmp", 0},
+	} {
+		proto, port, err := parsePolicyPort(c.value)
+		assert.NilError(t, err)
+		assert.Equal(t, proto, c.proto)
+		assert.Equal(t, port, c.port)
+	}
+}
+
+func TestParsePolicyPortInvalid(t *testing.T) {
+	for _, value := range []string{"0", "70000", "70000/tcp", "http/tcp"} {
+		_, _, err := parsePolicyPort(value)
+		assert.Error(t, err, "invalid port")
+	}
+}
@@ -0,0 +1,95 @@
+package network
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type byPolicyName []types.NetworkPolicy
+
+func (r byPolicyName) Len() int      { return len(r) }
+func (r byPolicyName) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byPolicyName) Less(i, j int) bool {
+	return r[i].Name < r[j].Name
+}
+
+type policyListOptions struct {
+	network string
+	quiet   bool
+}
+
+func newPolicyListCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts policyListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS] NETWORK",
+		Aliases: []string{"list"},
+		Short:   "List the policies of a network",
+		Args:    cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.network = args[0]
+			return runPolicyList(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display policy names")
+
+	return cmd
+}
+
+func runPolicyList(dockerCli *client.DockerCli, opts policyListOptions) error {
+	policies, err := dockerCli.Client().NetworkPolicyList(context.Background(), opts.network)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	if !opts.quiet {
+		fmt.Fprintf(w, "POLICY NAME\tFROM\tTO\tPORT\n")
+	}
+
+	sort.Sort(byPolicyName(policies))
+	for _, p := range policies {
+		if opts.quiet {
+			fmt.Fprintln(w, p.Name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, policySelector(p.From), policySelector(p.To), policyPort(p))
+	}
+	w.Flush()
+	return nil
+}
+
+// policySelector formats the labels of a selector, sorted by key.
+func policySelector(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "*"
+	}
+	var pairs []string
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func policyPort(p types.NetworkPolicy) string {
+	switch {
+	case p.Port != 0:
+		return strconv.Itoa(int(p.Port)) + "/" + p.Protocol
+	case p.Protocol != "":
+		return p.Protocol
+	}
+	return "*"
+}
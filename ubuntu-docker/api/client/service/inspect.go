@@ -94,14 +94,28 @@ func printService(out io.Writer, service swarm.Service) {
 		}
 	}
 
-	if service.Spec.Mode.Global != nil {
+	switch mode := service.Spec.Mode; {
+	case mode.Global != nil:
 		fmt.Fprintln(out, "Mode:\t\tGlobal")
-	} else {
+	case mode.GlobalJob != nil:
+		fmt.Fprintln(out, "Mode:\t\tGlobal job")
+	case mode.ReplicatedJob != nil:
+		fmt.Fprintln(out, "Mode:\t\tReplicated job")
+		if mode.ReplicatedJob.MaxConcurrent != nil {
+			fmt.Fprintf(out, " Max concurrent:\t%d\n", *mode.ReplicatedJob.MaxConcurrent)
+		}
+		if mode.ReplicatedJob.TotalCompletions != nil {
+			fmt.Fprintf(out, " Total completions:\t%d\n", *mode.ReplicatedJob.TotalCompletions)
+		}
+	default:
 		fmt.Fprintln(out, "Mode:\t\tReplicated")
-		if service.Spec.Mode.Replicated.Replicas != nil {
-			fmt.Fprintf(out, " Replicas:\t%d\n", *service.Spec.Mode.Replicated.Replicas)
+		if mode.Replicated.Replicas != nil {
+			fmt.Fprintf(out, " Replicas:\t%d\n", *mode.Replicated.Replicas)
 		}
 	}
+	if status := service.ServiceStatus; status != nil && (service.Spec.Mode.GlobalJob != nil || service.Spec.Mode.ReplicatedJob != nil) {
+		fmt.Fprintf(out, " Completed:\t%d/%d\n", status.CompletedTasks, status.DesiredTasks)
+	}
 
 	if service.UpdateStatus.State != "" {
 		fmt.Fprintln(out, "Update status:")
@@ -133,7 +147,7 @@ func printService(out io.Writer, service swarm.Service) {
 	if resources != nil {
 		fmt.Fprintln(out, "Resources:")
 		printResources := func(out io.Writer, requirement string, r *swarm.Resources) {
-			if r == nil || (r.MemoryBytes == 0 && r.NanoCPUs == 0) {
+			if r == nil || (r.MemoryBytes == 0 && r.NanoCPUs == 0 && r.NetworkIngressRate == 0 && r.NetworkEgressRate == 0) {
 				return
 			}
 			fmt.Fprintf(out, " %s:\n", requirement)
@@ -143,6 +157,12 @@ func printService(out io.Writer, service swarm.Service) {
 			if r.MemoryBytes != 0 {
 				fmt.Fprintf(out, "  Memory:\t%s\n", units.BytesSize(float64(r.MemoryBytes)))
 			}
+			if r.NetworkIngressRate != 0 {
+				fmt.Fprintf(out, "  Network Ingress:\t%s/s\n", units.BytesSize(float64(r.NetworkIngressRate)))
+			}
+			if r.NetworkEgressRate != 0 {
+				fmt.Fprintf(out, "  Network Egress:\t%s/s\n", units.BytesSize(float64(r.NetworkEgressRate)))
+			}
 		}
 		printResources(out, "Reservations", resources.Reservations)
 		printResources(out, "Limits", resources.Limits)
@@ -105,6 +105,11 @@ func printTable(out io.Writer, services []swarm.Service, running map[string]int)
 			replicas = fmt.Sprintf("%d/%d", running[service.ID], *service.Spec.Mode.Replicated.Replicas)
 		} else if service.Spec.Mode.Global != nil {
 			replicas = "global"
+		} else if service.Spec.Mode.ReplicatedJob != nil || service.Spec.Mode.GlobalJob != nil {
+			replicas = "job"
+			if status := service.ServiceStatus; status != nil {
+				replicas = fmt.Sprintf("%d/%d completed", status.CompletedTasks, status.DesiredTasks)
+			}
 		}
 		fmt.Fprintf(
 			writer,
@@ -221,6 +221,8 @@ func (m *MountOpt) Set(value string) error {
 				volumeOptions().DriverConfig.Options = make(map[string]string)
 			}
 			setValueOnMap(volumeOptions().DriverConfig.Options, value)
+		case "volume-subpath":
+			volumeOptions().Subpath = value
 		default:
 			return fmt.Errorf("unexpected key '%s' in '%s'", key, field)
 		}
@@ -276,17 +278,21 @@ type updateOptions struct {
 }
 
 type resourceOptions struct {
-	limitCPU      nanoCPUs
-	limitMemBytes memBytes
-	resCPU        nanoCPUs
-	resMemBytes   memBytes
+	limitCPU            nanoCPUs
+	limitMemBytes       memBytes
+	limitNetworkIngress memBytes
+	limitNetworkEgress  memBytes
+	resCPU              nanoCPUs
+	resMemBytes         memBytes
 }
 
 func (r *resourceOptions) ToResourceRequirements() *swarm.ResourceRequirements {
 	return &swarm.ResourceRequirements{
 		Limits: &swarm.Resources{
-			NanoCPUs:    r.limitCPU.Value(),
-			MemoryBytes: r.limitMemBytes.Value(),
+			NanoCPUs:           r.limitCPU.Value(),
+			MemoryBytes:        r.limitMemBytes.Value(),
+			NetworkIngressRate: r.limitNetworkIngress.Value(),
+			NetworkEgressRate:  r.limitNetworkEgress.Value(),
 		},
 		Reservations: &swarm.Resources{
 			NanoCPUs:    r.resCPU.Value(),
@@ -406,6 +412,9 @@ type serviceOptions struct {
 	replicas Uint64Opt
 	mode     string
 
+	maxConcurrent    Uint64Opt
+	totalCompletions Uint64Opt
+
 	restartPolicy restartPolicyOptions
 	constraints   []string
 	update        updateOptions
@@ -465,13 +474,27 @@ func (opts *serviceOptions) ToService() (swarm.ServiceSpec, error) {
 		EndpointSpec: opts.endpoint.ToEndpointSpec(),
 	}
 
+	if opts.mode != "replicated-job" && (opts.maxConcurrent.Value() != nil || opts.totalCompletions.Value() != nil) {
+		return service, fmt.Errorf("max-concurrent and total-completions can only be used with replicated-job mode")
+	}
+
 	switch opts.mode {
-	case "global":
+	case "global", "global-job", "replicated-job":
 		if opts.replicas.Value() != nil {
 			return service, fmt.Errorf("replicas can only be used with replicated mode")
 		}
 
-		service.Mode.Global = &swarm.GlobalService{}
+		switch opts.mode {
+		case "global":
+			service.Mode.Global = &swarm.GlobalService{}
+		case "global-job":
+			service.Mode.GlobalJob = &swarm.GlobalJob{}
+		case "replicated-job":
+			service.Mode.ReplicatedJob = &swarm.ReplicatedJob{
+				MaxConcurrent:    opts.maxConcurrent.Value(),
+				TotalCompletions: opts.totalCompletions.Value(),
+			}
+		}
 	case "replicated":
 		service.Mode.Replicated = &swarm.ReplicatedService{
 			Replicas: opts.replicas.Value(),
@@ -493,11 +516,15 @@ func addServiceFlags(cmd *cobra.Command, opts *serviceOptions) {
 
 	flags.Var(&opts.resources.limitCPU, flagLimitCPU, "Limit CPUs")
 	flags.Var(&opts.resources.limitMemBytes, flagLimitMemory, "Limit Memory")
+	flags.Var(&opts.resources.limitNetworkIngress, flagLimitNetworkIngress, "Limit the network ingress rate (bytes per second)")
+	flags.Var(&opts.resources.limitNetworkEgress, flagLimitNetworkEgress, "Limit the network egress rate (bytes per second)")
 	flags.Var(&opts.resources.resCPU, flagReserveCPU, "Reserve CPUs")
 	flags.Var(&opts.resources.resMemBytes, flagReserveMemory, "Reserve Memory")
 	flags.Var(&opts.stopGrace, flagStopGracePeriod, "Time to wait before force killing a container")
 
 	flags.Var(&opts.replicas, flagReplicas, "Number of tasks")
+	flags.Var(&opts.maxConcurrent, flagMaxConcurrent, "Number of job tasks to run at the same time")
+	flags.Var(&opts.totalCompletions, flagTotalCompletions, "Number of job tasks that must complete successfully")
 
 	flags.StringVar(&opts.restartPolicy.condition, flagRestartCondition, "", "Restart when condition is met (none, on-failure, or any)")
 	flags.Var(&opts.restartPolicy.delay, flagRestartDelay, "Delay between restart attempts")
@@ -532,6 +559,9 @@ const (
 	flagLabelAdd             = "label-add"
 	flagLimitCPU             = "limit-cpu"
 	flagLimitMemory          = "limit-memory"
+	flagLimitNetworkIngress  = "limit-network-ingress"
+	flagLimitNetworkEgress   = "limit-network-egress"
+	flagMaxConcurrent        = "max-concurrent"
 	flagMode                 = "mode"
 	flagMount                = "mount"
 	flagMountRemove          = "mount-rm"
@@ -549,6 +579,7 @@ const (
 	flagRestartMaxAttempts   = "restart-max-attempts"
 	flagRestartWindow        = "restart-window"
 	flagStopGracePeriod      = "stop-grace-period"
+	flagTotalCompletions     = "total-completions"
 	flagUpdateDelay          = "update-delay"
 	flagUpdateFailureAction  = "update-failure-action"
 	flagUpdateParallelism    = "update-parallelism"
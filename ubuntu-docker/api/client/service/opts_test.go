@@ -169,8 +169,35 @@ func TestMountOptVolumeNoCopy(t *testing.T) {
 	assert.Equal(t, m.values[0].VolumeOptions.NoCopy, true)
 }
 
+func TestMountOptVolumeSubpath(t *testing.T) {
+	var m MountOpt
+	assert.NilError(t, m.Set("type=volume,src=data,dst=/etc/app,volume-subpath=config,readonly"))
+	assert.Equal(t, m.values[0].ReadOnly, true)
+	assert.Equal(t, m.values[0].VolumeOptions.Subpath, "config")
+}
+
 func TestMountOptTypeConflict(t *testing.T) {
 	var m MountOpt
 	assert.Error(t, m.Set("type=bind,target=/foo,source=/foo,volume-nocopy=true"), "cannot mix")
 	assert.Error(t, m.Set("type=volume,target=/foo,source=/foo,bind-propagation=rprivate"), "cannot mix")
 }
+
+func TestToServiceJobModes(t *testing.T) {
+	opts := newServiceOptions()
+	opts.mode = "replicated-job"
+	assert.NilError(t, opts.maxConcurrent.Set("3"))
+	service, err := opts.ToService()
+	assert.NilError(t, err)
+	assert.Equal(t, *service.Mode.ReplicatedJob.MaxConcurrent, uint64(3))
+	assert.Equal(t, service.Mode.ReplicatedJob.TotalCompletions == nil, true)
+
+	opts = newServiceOptions()
+	opts.mode = "global-job"
+	service, err = opts.ToService()
+	assert.NilError(t, err)
+	assert.Equal(t, service.Mode.GlobalJob != nil, true)
+
+	assert.NilError(t, opts.totalCompletions.Set("3"))
+	_, err = opts.ToService()
+	assert.Error(t, err, "can only be used with replicated-job mode")
+}
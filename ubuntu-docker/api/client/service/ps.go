@@ -1,6 +1,8 @@
 package service
 
 import (
+	"fmt"
+
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/client"
@@ -66,5 +68,13 @@ func runPS(dockerCli *client.DockerCli, opts psOptions) error {
 		return err
 	}
 
-	return task.Print(dockerCli, ctx, tasks, idresolver.New(client, opts.noResolve))
+	if err := task.Print(dockerCli, ctx, tasks, idresolver.New(client, opts.noResolve)); err != nil {
+		return err
+	}
+
+	if mode := service.Spec.Mode; (mode.ReplicatedJob != nil || mode.GlobalJob != nil) && service.ServiceStatus != nil {
+		status := service.ServiceStatus
+		fmt.Fprintf(dockerCli.Out(), "\nJob progress: %d/%d tasks completed, %d running\n", status.CompletedTasks, status.DesiredTasks, status.RunningTasks)
+	}
+	return nil
 }
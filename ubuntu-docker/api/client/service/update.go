@@ -155,10 +155,13 @@ func updateService(flags *pflag.FlagSet, spec *swarm.ServiceSpec) error {
 	updateString(flagUser, &cspec.User)
 	updateMounts(flags, &cspec.Mounts)
 
-	if flags.Changed(flagLimitCPU) || flags.Changed(flagLimitMemory) {
+	if flags.Changed(flagLimitCPU) || flags.Changed(flagLimitMemory) ||
+		flags.Changed(flagLimitNetworkIngress) || flags.Changed(flagLimitNetworkEgress) {
 		taskResources().Limits = &swarm.Resources{}
 		updateInt64Value(flagLimitCPU, &task.Resources.Limits.NanoCPUs)
 		updateInt64Value(flagLimitMemory, &task.Resources.Limits.MemoryBytes)
+		updateInt64Value(flagLimitNetworkIngress, &task.Resources.Limits.NetworkIngressRate)
+		updateInt64Value(flagLimitNetworkEgress, &task.Resources.Limits.NetworkEgressRate)
 	}
 	if flags.Changed(flagReserveCPU) || flags.Changed(flagReserveMemory) {
 		taskResources().Reservations = &swarm.Resources{}
@@ -193,6 +196,10 @@ func updateService(flags *pflag.FlagSet, spec *swarm.ServiceSpec) error {
 		return err
 	}
 
+	if err := updateReplicatedJob(flags, &spec.Mode); err != nil {
+		return err
+	}
+
 	if anyChanged(flags, flagUpdateParallelism, flagUpdateDelay, flagUpdateFailureAction) {
 		if spec.UpdateConfig == nil {
 			spec.UpdateConfig = &swarm.UpdateConfig{}
@@ -448,6 +455,23 @@ func updateReplicas(flags *pflag.FlagSet, serviceMode *swarm.ServiceMode) error
 	return nil
 }
 
+func updateReplicatedJob(flags *pflag.FlagSet, serviceMode *swarm.ServiceMode) error {
+	if !anyChanged(flags, flagMaxConcurrent, flagTotalCompletions) {
+		return nil
+	}
+
+	if serviceMode == nil || serviceMode.ReplicatedJob == nil {
+		return fmt.Errorf("max-concurrent and total-completions can only be used with replicated-job mode")
+	}
+	if flags.Changed(flagMaxConcurrent) {
+		serviceMode.ReplicatedJob.MaxConcurrent = flags.Lookup(flagMaxConcurrent).Value.(*Uint64Opt).Value()
+	}
+	if flags.Changed(flagTotalCompletions) {
+		serviceMode.ReplicatedJob.TotalCompletions = flags.Lookup(flagTotalCompletions).Value.(*Uint64Opt).Value()
+	}
+	return nil
+}
+
 // updateLogDriver updates the log driver only if the log driver flag is set.
 // All options will be replaced with those provided on the command line.
 func updateLogDriver(flags *pflag.FlagSet, taskTemplate *swarm.TaskSpec) error {
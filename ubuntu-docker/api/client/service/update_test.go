@@ -178,3 +178,17 @@ func TestUpdatePortsConflictingFlags(t *testing.T) {
 	err := updatePorts(flags, &portConfigs)
 	assert.Error(t, err, "conflicting port mapping")
 }
+
+func TestUpdateReplicatedJob(t *testing.T) {
+	flags := newUpdateCommand(nil).Flags()
+	flags.Set("total-completions", "10")
+
+	maxConcurrent := uint64(2)
+	mode := &swarm.ServiceMode{ReplicatedJob: &swarm.ReplicatedJob{MaxConcurrent: &maxConcurrent}}
+	assert.NilError(t, updateReplicatedJob(flags, mode))
+	assert.Equal(t, *mode.ReplicatedJob.MaxConcurrent, uint64(2))
+	assert.Equal(t, *mode.ReplicatedJob.TotalCompletions, uint64(10))
+
+	mode = &swarm.ServiceMode{Replicated: &swarm.ReplicatedService{}}
+	assert.Error(t, updateReplicatedJob(flags, mode), "can only be used with replicated-job mode")
+}
@@ -0,0 +1,502 @@
+// +build experimental
+
+package stack
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/api/client/composefile"
+	"github.com/docker/docker/pkg/homedir"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/network"
+	"github.com/docker/engine-api/types/swarm"
+	"github.com/docker/go-connections/nat"
+)
+
+const defaultNetwork = "default"
+
+func deployCompose(dockerCli *client.DockerCli, opts deployOptions) error {
+	config, err := loadComposefile(dockerCli.Err(), opts.composefile)
+	if err != nil {
+		return err
+	}
+	path, err := filepath.Abs(opts.composefile)
+	if err != nil {
+		return err
+	}
+	workingDir := filepath.Dir(path)
+	namespace := opts.namespace
+	ctx := context.Background()
+
+	networks, externalNetworks, err := convertComposeNetworks(config, namespace)
+	if err != nil {
+		return err
+	}
+	if err := validateExternalNetworks(ctx, dockerCli, externalNetworks); err != nil {
+		return err
+	}
+	if err := updateNetworks(ctx, dockerCli, networks, namespace); err != nil {
+		return err
+	}
+
+	services, err := convertComposeServices(config, namespace, workingDir)
+	if err != nil {
+		return err
+	}
+	return deployServices(ctx, dockerCli, services, namespace, opts.sendRegistryAuth)
+}
+
+// serviceNetworks returns the networks a service is attached to. Services
+// that don't list any network are attached to the default network.
+func serviceNetworks(service composefile.Service) composefile.ServiceNetworks {
+	if len(service.Networks) == 0 {
+		return composefile.ServiceNetworks{defaultNetwork: nil}
+	}
+	return service.Networks
+}
+
+// convertComposeNetworks returns the options used to create the networks of
+// the stack, keyed by their name in the Compose file, and the names of the
+// external networks the stack uses.
+func convertComposeNetworks(
+	config *composefile.Config,
+	namespace string,
+) (map[string]types.NetworkCreate, []string, error) {
+	networks := make(map[string]types.NetworkCreate)
+	externalNetworks := []string{}
+
+	for serviceName, service := range config.Services {
+		for internalName := range serviceNetworks(service) {
+			nw, declared := config.Networks[internalName]
+			if !declared {
+				if internalName != defaultNetwork {
+					return nil, nil, fmt.Errorf(
+						"Service %s references undefined network %s",
+						serviceName, internalName)
+				}
+				networks[internalName] = defaultNetworkCreate(namespace)
+				continue
+			}
+
+			if nw.External.External {
+				externalNetworks = append(externalNetworks, externalName(internalName, nw.External))
+				continue
+			}
+
+			createOpts := types.NetworkCreate{
+				Labels:   getStackLabels(namespace, nw.Labels),
+				Driver:   nw.Driver,
+				Options:  nw.DriverOpts,
+				Internal: nw.Internal,
+				// TODO: remove when engine-api uses omitempty for IPAM
+				IPAM: network.IPAM{Driver: "default"},
+			}
+			if createOpts.Driver == "" {
+				createOpts.Driver = defaultNetworkDriver
+			}
+			networks[internalName] = createOpts
+		}
+	}
+	return networks, externalNetworks, nil
+}
+
+func validateExternalNetworks(
+	ctx context.Context,
+	dockerCli *client.DockerCli,
+	externalNetworks []string,
+) error {
+	client := dockerCli.Client()
+
+	for _, networkName := range externalNetworks {
+		resource, err := client.NetworkInspect(ctx, networkName)
+		if err != nil {
+			return fmt.Errorf(
+				"Network %s is declared as external, but could not be found. "+
+					"You need to create the network before the stack is deployed",
+				networkName)
+		}
+		if resource.Scope != "swarm" {
+			return fmt.Errorf(
+				"Network %s is declared as external, but it is not in the right scope: %q instead of %q",
+				networkName, resource.Scope, "swarm")
+		}
+	}
+	return nil
+}
+
+func externalName(name string, external composefile.External) string {
+	if external.Name != "" {
+		return external.Name
+	}
+	return name
+}
+
+func convertComposeServices(
+	config *composefile.Config,
+	namespace string,
+	workingDir string,
+) (map[string]swarm.ServiceSpec, error) {
+	specs := make(map[string]swarm.ServiceSpec)
+
+	for internalName, service := range config.Services {
+		spec, err := convertComposeService(config, namespace, workingDir, internalName, service)
+		if err != nil {
+			return nil, fmt.Errorf("Service %s: %v", internalName, err)
+		}
+		specs[spec.Name] = spec
+	}
+	return specs, nil
+}
+
+func convertComposeService(
+	config *composefile.Config,
+	namespace string,
+	workingDir string,
+	internalName string,
+	service composefile.Service,
+) (swarm.ServiceSpec, error) {
+	mode, err := convertDeployMode(service.Deploy.Mode, service.Deploy.Replicas)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	mounts, err := convertVolumes(service.Volumes, config.Volumes, namespace, workingDir)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	ports, err := convertPorts(service.Ports)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	resources, err := convertResources(service.Deploy.Resources)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	env := []string{}
+	for _, value := range service.Environment {
+		value, err := runconfigopts.ValidateEnv(value)
+		if err != nil {
+			return swarm.ServiceSpec{}, err
+		}
+		env = append(env, value)
+	}
+
+	serviceSpec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{
+			Name:   fmt.Sprintf("%s_%s", namespace, internalName),
+			Labels: getStackLabels(namespace, service.Deploy.Labels),
+		},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: swarm.ContainerSpec{
+				Image:           service.Image,
+				Labels:          service.Labels,
+				Command:         service.Entrypoint,
+				Args:            service.Command,
+				Env:             env,
+				Dir:             service.WorkingDir,
+				User:            service.User,
+				Mounts:          mounts,
+				StopGracePeriod: service.StopGracePeriod,
+			},
+			Resources:     resources,
+			RestartPolicy: convertRestartPolicy(service.Deploy.RestartPolicy),
+			Placement: &swarm.Placement{
+				Constraints: service.Deploy.Placement.Constraints,
+			},
+			LogDriver: convertLogging(service.Logging),
+		},
+		Mode:         mode,
+		UpdateConfig: convertUpdateConfig(service.Deploy.UpdateConfig),
+		Networks:     convertServiceNetworks(serviceNetworks(service), config.Networks, namespace, internalName),
+		EndpointSpec: &swarm.EndpointSpec{
+			Ports: ports,
+		},
+	}
+	return serviceSpec, nil
+}
+
+func convertDeployMode(mode string, replicas *uint64) (swarm.ServiceMode, error) {
+	serviceMode := swarm.ServiceMode{}
+
+	switch mode {
+	case "global":
+		if replicas != nil {
+			return serviceMode, fmt.Errorf("replicas can only be used with replicated mode")
+		}
+		serviceMode.Global = &swarm.GlobalService{}
+	case "replicated", "":
+		serviceMode.Replicated = &swarm.ReplicatedService{Replicas: replicas}
+	default:
+		return serviceMode, fmt.Errorf("Unknown mode: %s", mode)
+	}
+	return serviceMode, nil
+}
+
+func convertServiceNetworks(
+	networks composefile.ServiceNetworks,
+	networkConfigs map[string]composefile.Network,
+	namespace string,
+	name string,
+) []swarm.NetworkAttachmentConfig {
+	nets := []swarm.NetworkAttachmentConfig{}
+	for networkName, attachment := range networks {
+		aliases := []string{name}
+		if attachment != nil {
+			aliases = append(aliases, attachment.Aliases...)
+		}
+
+		target := namespace + "_" + networkName
+		if nw, declared := networkConfigs[networkName]; declared && nw.External.External {
+			target = externalName(networkName, nw.External)
+		}
+
+		nets = append(nets, swarm.NetworkAttachmentConfig{
+			Target:  target,
+			Aliases: aliases,
+		})
+	}
+	return nets
+}
+
+// convertVolumes converts the short volume syntax of a Compose file
+// ([SOURCE:]TARGET[:MODE]) to mounts. Named volumes must be declared in the
+// top level volumes section, relative bind mounts are resolved from the
+// directory of the Compose file.
+func convertVolumes(
+	volumes []string,
+	volumeConfigs map[string]composefile.Volume,
+	namespace string,
+	workingDir string,
+) ([]swarm.Mount, error) {
+	mounts := []swarm.Mount{}
+
+	for _, volumeSpec := range volumes {
+		mount, err := convertVolume(volumeSpec, volumeConfigs, namespace, workingDir)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts, nil
+}
+
+func convertVolume(
+	volumeSpec string,
+	volumeConfigs map[string]composefile.Volume,
+	namespace string,
+	workingDir string,
+) (swarm.Mount, error) {
+	var source, target, mode string
+
+	parts := strings.Split(volumeSpec, ":")
+	switch len(parts) {
+	case 1:
+		target = parts[0]
+	case 2:
+		source, target = parts[0], parts[1]
+	case 3:
+		source, target, mode = parts[0], parts[1], parts[2]
+	default:
+		return swarm.Mount{}, fmt.Errorf("invalid volume: %s", volumeSpec)
+	}
+
+	mount := swarm.Mount{
+		Type:   swarm.MountTypeVolume,
+		Target: target,
+	}
+
+	var noCopy bool
+	if mode != "" {
+		for _, option := range strings.Split(mode, ",") {
+			switch option {
+			case "ro":
+				mount.ReadOnly = true
+			case "rw":
+			case "nocopy":
+				noCopy = true
+			default:
+				return swarm.Mount{}, fmt.Errorf("invalid mode %q for volume: %s", option, volumeSpec)
+			}
+		}
+	}
+
+	// Anonymous volume
+	if source == "" {
+		return mount, nil
+	}
+
+	if isFilePath(source) {
+		if noCopy {
+			return swarm.Mount{}, fmt.Errorf("nocopy can only be used with named volumes: %s", volumeSpec)
+		}
+		if strings.HasPrefix(source, "~") {
+			expanded, err := expandUser(source)
+			if err != nil {
+				return swarm.Mount{}, fmt.Errorf("%v: %s", err, volumeSpec)
+			}
+			source = expanded
+		}
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(workingDir, source)
+		}
+		mount.Type = swarm.MountTypeBind
+		mount.Source = filepath.Clean(source)
+		return mount, nil
+	}
+
+	volumeConfig, declared := volumeConfigs[source]
+	if !declared {
+		return swarm.Mount{}, fmt.Errorf("undefined volume: %s", source)
+	}
+
+	if volumeConfig.External.External {
+		mount.Source = externalName(source, volumeConfig.External)
+		if noCopy {
+			mount.VolumeOptions = &swarm.VolumeOptions{NoCopy: true}
+		}
+		return mount, nil
+	}
+
+	mount.Source = namespace + "_" + source
+	mount.VolumeOptions = &swarm.VolumeOptions{
+		NoCopy: noCopy,
+		Labels: getStackLabels(namespace, volumeConfig.Labels),
+	}
+	if volumeConfig.Driver != "" {
+		mount.VolumeOptions.DriverConfig = &swarm.Driver{
+			Name:    volumeConfig.Driver,
+			Options: volumeConfig.DriverOpts,
+		}
+	}
+	return mount, nil
+}
+
+func isFilePath(source string) bool {
+	return strings.HasPrefix(source, "/") ||
+		strings.HasPrefix(source, ".") ||
+		strings.HasPrefix(source, "~")
+}
+
+// expandUser replaces a leading ~ in path with the home directory of the
+// current user. The home directories of other users (~user) are not
+// supported.
+func expandUser(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return "", fmt.Errorf("the home directory of another user is not supported")
+	}
+	home := homedir.Get()
+	if home == "" {
+		return "", fmt.Errorf("the home directory of the current user is unknown")
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+func convertPorts(portSpecs []string) ([]swarm.PortConfig, error) {
+	ports, portBindings, err := nat.ParsePortSpecs(portSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	portConfigs := []swarm.PortConfig{}
+	for port := range ports {
+		for _, binding := range portBindings[port] {
+			if binding.HostIP != "" {
+				return nil, fmt.Errorf("HostIP is not supported by a service.")
+			}
+
+			var publishedPort uint64
+			if binding.HostPort != "" {
+				publishedPort, err = strconv.ParseUint(binding.HostPort, 10, 16)
+				if err != nil {
+					return nil, err
+				}
+			}
+			portConfigs = append(portConfigs, swarm.PortConfig{
+				Protocol:      swarm.PortConfigProtocol(strings.ToLower(port.Proto())),
+				TargetPort:    uint32(port.Int()),
+				PublishedPort: uint32(publishedPort),
+			})
+		}
+	}
+	return portConfigs, nil
+}
+
+func convertResources(source composefile.Resources) (*swarm.ResourceRequirements, error) {
+	resources := &swarm.ResourceRequirements{}
+	var err error
+
+	if source.Limits != nil {
+		resources.Limits, err = convertResource(source.Limits)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if source.Reservations != nil {
+		resources.Reservations, err = convertResource(source.Reservations)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+func convertResource(source *composefile.Resource) (*swarm.Resources, error) {
+	resource := &swarm.Resources{MemoryBytes: int64(source.Memory)}
+
+	if source.CPUs != "" {
+		cpu, ok := new(big.Rat).SetString(source.CPUs)
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse %v as a rational number", source.CPUs)
+		}
+		nano := cpu.Mul(cpu, big.NewRat(1e9, 1))
+		if !nano.IsInt() {
+			return nil, fmt.Errorf("value is too precise")
+		}
+		resource.NanoCPUs = nano.Num().Int64()
+	}
+	return resource, nil
+}
+
+func convertRestartPolicy(source *composefile.RestartPolicy) *swarm.RestartPolicy {
+	if source == nil {
+		return nil
+	}
+	return &swarm.RestartPolicy{
+		Condition:   swarm.RestartPolicyCondition(source.Condition),
+		Delay:       source.Delay,
+		MaxAttempts: source.MaxAttempts,
+		Window:      source.Window,
+	}
+}
+
+func convertUpdateConfig(source *composefile.UpdateConfig) *swarm.UpdateConfig {
+	if source == nil {
+		return nil
+	}
+	return &swarm.UpdateConfig{
+		Parallelism:   source.Parallelism,
+		Delay:         source.Delay,
+		FailureAction: source.FailureAction,
+	}
+}
+
+func convertLogging(source *composefile.Logging) *swarm.Driver {
+	if source == nil || source.Driver == "" {
+		return nil
+	}
+	return &swarm.Driver{
+		Name:    source.Driver,
+		Options: source.Options,
+	}
+}
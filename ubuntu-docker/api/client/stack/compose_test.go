@@ -0,0 +1,253 @@
+// +build experimental
+
+package stack
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/client/composefile"
+	"github.com/docker/docker/pkg/testutil/assert"
+	"github.com/docker/engine-api/types/swarm"
+)
+
+func TestConvertVolumeAnonymous(t *testing.T) {
+	mount, err := convertVolume("/data", nil, "stack", "/src")
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Type, swarm.MountTypeVolume)
+	assert.Equal(t, mount.Source, "")
+	assert.Equal(t, mount.Target, "/data")
+}
+
+func TestConvertVolumeNamed(t *testing.T) {
+	volumes := map[string]composefile.Volume{
+		"data": {
+			Driver:     "local",
+			DriverOpts: map[string]string{"type": "tmpfs"},
+			Labels:     composefile.Labels{"role": "db"},
+		},
+	}
+	mount, err := convertVolume("data:/var/lib/data:ro,nocopy", volumes, "stack", "/src")
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Type, swarm.MountTypeVolume)
+	assert.Equal(t, mount.Source, "stack_data")
+	assert.Equal(t, mount.Target, "/var/lib/data")
+	assert.Equal(t, mount.ReadOnly, true)
+	assert.Equal(t, mount.VolumeOptions.NoCopy, true)
+	assert.Equal(t, mount.VolumeOptions.Labels["role"], "db")
+	assert.Equal(t, mount.VolumeOptions.Labels[labelNamespace], "stack")
+	assert.Equal(t, mount.VolumeOptions.DriverConfig.Name, "local")
+	assert.Equal(t, mount.VolumeOptions.DriverConfig.Options["type"], "tmpfs")
+}
+
+func TestConvertVolumeExternal(t *testing.T) {
+	volumes := map[string]composefile.Volume{
+		"data": {External: composefile.External{External: true, Name: "shared"}},
+	}
+	mount, err := convertVolume("data:/data", volumes, "stack", "/src")
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Source, "shared")
+	if mount.VolumeOptions != nil {
+		t.Fatalf("expected no volume options for an external volume, got %+v", mount.VolumeOptions)
+	}
+}
+
+func TestConvertVolumeUndefined(t *testing.T) {
+	_, err := convertVolume("data:/data", nil, "stack", "/src")
+	assert.Error(t, err, "undefined volume: data")
+}
+
+func TestConvertVolumeBind(t *testing.T) {
+	for spec, source := range map[string]string{
+		"/host/path:/data":    "/host/path",
+		"./path:/data":        "/src/path",
+		"../other/path:/data": "/other/path",
+	} {
+		mount, err := convertVolume(spec, nil, "stack", "/src")
+		assert.NilError(t, err)
+		assert.Equal(t, mount.Type, swarm.MountTypeBind)
+		assert.Equal(t, mount.Source, source)
+		assert.Equal(t, mount.Target, "/data")
+	}
+}
+
+func TestConvertVolumeBindHome(t *testing.T) {
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", "/home/user")
+
+	mount, err := convertVolume("~/data:/data", nil, "stack", "/src")
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Type, swarm.MountTypeBind)
+	assert.Equal(t, mount.Source, "/home/user/data")
+
+	mount, err = convertVolume("~:/data", nil, "stack", "/src")
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Source, "/home/user")
+
+	_, err = convertVolume("~other/data:/data", nil, "stack", "/src")
+	assert.Error(t, err, "the home directory of another user is not supported")
+}
+
+func TestConvertVolumeInvalid(t *testing.T) {
+	_, err := convertVolume("/a:/b:ro:extra", nil, "stack", "/src")
+	assert.Error(t, err, "invalid volume")
+
+	_, err = convertVolume("/a:/b:rx", nil, "stack", "/src")
+	assert.Error(t, err, `invalid mode "rx"`)
+
+	_, err = convertVolume("/a:/b:nocopy", nil, "stack", "/src")
+	assert.Error(t, err, "nocopy can only be used with named volumes")
+}
+
+func TestConvertPorts(t *testing.T) {
+	ports, err := convertPorts([]string{"8080:80", "53/udp"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(ports), 2)
+
+	byTarget := make(map[uint32]swarm.PortConfig)
+	for _, port := range ports {
+		byTarget[port.TargetPort] = port
+	}
+	assert.Equal(t, byTarget[80].Protocol, swarm.PortConfigProtocolTCP)
+	assert.Equal(t, byTarget[80].PublishedPort, uint32(8080))
+	assert.Equal(t, byTarget[53].Protocol, swarm.PortConfigProtocolUDP)
+	assert.Equal(t, byTarget[53].PublishedPort, uint32(0))
+
+	_, err = convertPorts([]string{"127.0.0.1:8080:80"})
+	assert.Error(t, err, "HostIP is not supported")
+}
+
+func TestConvertComposeNetworks(t *testing.T) {
+	config := &composefile.Config{
+		Services: map[string]composefile.Service{
+			"web": {Networks: composefile.ServiceNetworks{"front": nil, "outside": nil}},
+			"db":  {},
+		},
+		Networks: map[string]composefile.Network{
+			"front": {
+				Driver:     "overlay",
+				DriverOpts: map[string]string{"encrypted": ""},
+				Labels:     composefile.Labels{"role": "front"},
+				Internal:   true,
+			},
+			"outside": {External: composefile.External{External: true, Name: "public"}},
+		},
+	}
+
+	networks, external, err := convertComposeNetworks(config, "stack")
+	assert.NilError(t, err)
+	assert.Equal(t, len(networks), 2)
+	assert.EqualStringSlice(t, external, []string{"public"})
+
+	front := networks["front"]
+	assert.Equal(t, front.Driver, "overlay")
+	assert.Equal(t, front.Internal, true)
+	assert.Equal(t, front.Labels["role"], "front")
+	assert.Equal(t, front.Labels[labelNamespace], "stack")
+	_, ok := front.Options["encrypted"]
+	assert.Equal(t, ok, true)
+
+	// db doesn't list any network, so it uses the default one.
+	assert.Equal(t, networks[defaultNetwork].Driver, defaultNetworkDriver)
+}
+
+func TestConvertComposeNetworksUndefined(t *testing.T) {
+	config := &composefile.Config{
+		Services: map[string]composefile.Service{
+			"web": {Networks: composefile.ServiceNetworks{"missing": nil}},
+		},
+	}
+	_, _, err := convertComposeNetworks(config, "stack")
+	assert.Error(t, err, "Service web references undefined network missing")
+}
+
+func TestConvertServiceNetworks(t *testing.T) {
+	networks := composefile.ServiceNetworks{
+		"front":   {Aliases: []string{"www"}},
+		"outside": nil,
+	}
+	configs := map[string]composefile.Network{
+		"outside": {External: composefile.External{External: true}},
+	}
+
+	attachments := convertServiceNetworks(networks, configs, "stack", "web")
+	assert.Equal(t, len(attachments), 2)
+	byTarget := make(map[string]swarm.NetworkAttachmentConfig)
+	for _, attachment := range attachments {
+		byTarget[attachment.Target] = attachment
+	}
+	assert.EqualStringSlice(t, byTarget["stack_front"].Aliases, []string{"web", "www"})
+	assert.EqualStringSlice(t, byTarget["outside"].Aliases, []string{"web"})
+}
+
+func TestConvertDeployMode(t *testing.T) {
+	replicas := uint64(2)
+	mode, err := convertDeployMode("", &replicas)
+	assert.NilError(t, err)
+	assert.Equal(t, *mode.Replicated.Replicas, uint64(2))
+
+	mode, err = convertDeployMode("global", nil)
+	assert.NilError(t, err)
+	if mode.Global == nil {
+		t.Fatal("expected a global service mode")
+	}
+
+	_, err = convertDeployMode("global", &replicas)
+	assert.Error(t, err, "replicas can only be used with replicated mode")
+
+	_, err = convertDeployMode("batch", nil)
+	assert.Error(t, err, "Unknown mode: batch")
+}
+
+func TestConvertResource(t *testing.T) {
+	resource, err := convertResource(&composefile.Resource{CPUs: "0.5", Memory: 1024})
+	assert.NilError(t, err)
+	assert.Equal(t, resource.NanoCPUs, int64(5e8))
+	assert.Equal(t, resource.MemoryBytes, int64(1024))
+
+	_, err = convertResource(&composefile.Resource{CPUs: "half"})
+	assert.Error(t, err, "Failed to parse half")
+}
+
+func TestConvertComposeServicesInterpolated(t *testing.T) {
+	reader := strings.NewReader(`
+version: "3"
+services:
+  web:
+    image: nginx:${TAG}
+    environment:
+      - MODE=${MODE:-production}
+    ports:
+      - "${PORT}:80"
+    volumes:
+      - data:/data
+    deploy:
+      replicas: ${REPLICAS}
+volumes:
+  data: {}
+`)
+	env := map[string]string{"TAG": "1.11", "PORT": "8080", "REPLICAS": "3"}
+	config, _, err := composefile.LoadFile(reader, func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	})
+	assert.NilError(t, err)
+
+	specs, err := convertComposeServices(config, "stack", "/src")
+	assert.NilError(t, err)
+	spec, ok := specs["stack_web"]
+	assert.Equal(t, ok, true)
+
+	container := spec.TaskTemplate.ContainerSpec
+	assert.Equal(t, container.Image, "nginx:1.11")
+	assert.EqualStringSlice(t, container.Env, []string{"MODE=production"})
+	assert.Equal(t, len(container.Mounts), 1)
+	assert.Equal(t, container.Mounts[0].Source, "stack_data")
+	assert.Equal(t, *spec.Mode.Replicated.Replicas, uint64(3))
+	assert.Equal(t, len(spec.EndpointSpec.Ports), 1)
+	assert.Equal(t, spec.EndpointSpec.Ports[0].PublishedPort, uint32(8080))
+	assert.Equal(t, spec.Labels[labelNamespace], "stack")
+	assert.Equal(t, len(spec.Networks), 1)
+	assert.Equal(t, spec.Networks[0].Target, "stack_default")
+}
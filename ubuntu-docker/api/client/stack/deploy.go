@@ -22,6 +22,7 @@ const (
 
 type deployOptions struct {
 	bundlefile       string
+	composefile      string
 	namespace        string
 	sendRegistryAuth bool
 }
@@ -32,7 +33,7 @@ func newDeployCommand(dockerCli *client.DockerCli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "deploy [OPTIONS] STACK",
 		Aliases: []string{"up"},
-		Short:   "Create and update a stack from a Distributed Application Bundle (DAB)",
+		Short:   "Create and update a stack from a Compose file or a Distributed Application Bundle (DAB)",
 		Args:    cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.namespace = args[0]
@@ -42,23 +43,38 @@ func newDeployCommand(dockerCli *client.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	addBundlefileFlag(&opts.bundlefile, flags)
+	addComposefileFlag(&opts.composefile, flags)
 	addRegistryAuthFlag(&opts.sendRegistryAuth, flags)
 	return cmd
 }
 
 func runDeploy(dockerCli *client.DockerCli, opts deployOptions) error {
+	if opts.bundlefile != "" && opts.composefile != "" {
+		return fmt.Errorf("You cannot specify both a bundle file and a Compose file.")
+	}
+	if opts.composefile != "" {
+		return deployCompose(dockerCli, opts)
+	}
+	return deployBundle(dockerCli, opts)
+}
+
+func deployBundle(dockerCli *client.DockerCli, opts deployOptions) error {
 	bundle, err := loadBundlefile(dockerCli.Err(), opts.namespace, opts.bundlefile)
 	if err != nil {
 		return err
 	}
 
-	networks := getUniqueNetworkNames(bundle.Services)
+	networks := make(map[string]types.NetworkCreate)
+	for _, name := range getUniqueNetworkNames(bundle.Services) {
+		networks[name] = defaultNetworkCreate(opts.namespace)
+	}
 	ctx := context.Background()
 
 	if err := updateNetworks(ctx, dockerCli, networks, opts.namespace); err != nil {
 		return err
 	}
-	return deployServices(ctx, dockerCli, bundle.Services, opts.namespace, opts.sendRegistryAuth)
+	services := convertBundleServices(bundle.Services, opts.namespace)
+	return deployServices(ctx, dockerCli, services, opts.namespace, opts.sendRegistryAuth)
 }
 
 func getUniqueNetworkNames(services map[string]bundlefile.Service) []string {
@@ -79,7 +95,7 @@ func getUniqueNetworkNames(services map[string]bundlefile.Service) []string {
 func updateNetworks(
 	ctx context.Context,
 	dockerCli *client.DockerCli,
-	networks []string,
+	networks map[string]types.NetworkCreate,
 	namespace string,
 ) error {
 	client := dockerCli.Client()
@@ -94,14 +110,7 @@ func updateNetworks(
 		existingNetworkMap[network.Name] = network
 	}
 
-	createOpts := types.NetworkCreate{
-		Labels: getStackLabels(namespace, nil),
-		Driver: defaultNetworkDriver,
-		// TODO: remove when engine-api uses omitempty for IPAM
-		IPAM: network.IPAM{Driver: "default"},
-	}
-
-	for _, internalName := range networks {
+	for internalName, createOpts := range networks {
 		name := fmt.Sprintf("%s_%s", namespace, internalName)
 
 		if _, exists := existingNetworkMap[name]; exists {
@@ -115,6 +124,15 @@ func updateNetworks(
 	return nil
 }
 
+func defaultNetworkCreate(namespace string) types.NetworkCreate {
+	return types.NetworkCreate{
+		Labels: getStackLabels(namespace, nil),
+		Driver: defaultNetworkDriver,
+		// TODO: remove when engine-api uses omitempty for IPAM
+		IPAM: network.IPAM{Driver: "default"},
+	}
+}
+
 func convertNetworks(networks []string, namespace string, name string) []swarm.NetworkAttachmentConfig {
 	nets := []swarm.NetworkAttachmentConfig{}
 	for _, network := range networks {
@@ -126,25 +144,11 @@ func convertNetworks(networks []string, namespace string, name string) []swarm.N
 	return nets
 }
 
-func deployServices(
-	ctx context.Context,
-	dockerCli *client.DockerCli,
+func convertBundleServices(
 	services map[string]bundlefile.Service,
 	namespace string,
-	sendAuth bool,
-) error {
-	apiClient := dockerCli.Client()
-	out := dockerCli.Out()
-
-	existingServices, err := getServices(ctx, apiClient, namespace)
-	if err != nil {
-		return err
-	}
-
-	existingServiceMap := make(map[string]swarm.Service)
-	for _, service := range existingServices {
-		existingServiceMap[service.Spec.Name] = service
-	}
+) map[string]swarm.ServiceSpec {
+	specs := make(map[string]swarm.ServiceSpec)
 
 	for internalName, service := range services {
 		name := fmt.Sprintf("%s_%s", namespace, internalName)
@@ -184,6 +188,34 @@ func deployServices(
 			cspec.User = *service.User
 		}
 
+		specs[name] = serviceSpec
+	}
+	return specs
+}
+
+// deployServices creates or updates the services of the stack. The services
+// are keyed by their full name, including the namespace.
+func deployServices(
+	ctx context.Context,
+	dockerCli *client.DockerCli,
+	services map[string]swarm.ServiceSpec,
+	namespace string,
+	sendAuth bool,
+) error {
+	apiClient := dockerCli.Client()
+	out := dockerCli.Out()
+
+	existingServices, err := getServices(ctx, apiClient, namespace)
+	if err != nil {
+		return err
+	}
+
+	existingServiceMap := make(map[string]swarm.Service)
+	for _, service := range existingServices {
+		existingServiceMap[service.Spec.Name] = service
+	}
+
+	for name, serviceSpec := range services {
 		encodedAuth := ""
 		if sendAuth {
 			// Retrieve encoded auth token from the image reference
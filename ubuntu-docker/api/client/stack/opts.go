@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/docker/docker/api/client/bundlefile"
+	"github.com/docker/docker/api/client/composefile"
 	"github.com/spf13/pflag"
 )
 
@@ -18,6 +19,10 @@ func addBundlefileFlag(opt *string, flags *pflag.FlagSet) {
 		"Path to a Distributed Application Bundle file (Default: STACK.dab)")
 }
 
+func addComposefileFlag(opt *string, flags *pflag.FlagSet) {
+	flags.StringVarP(opt, "compose-file", "c", "", "Path to a Compose file")
+}
+
 func addRegistryAuthFlag(opt *bool, flags *pflag.FlagSet) {
 	flags.BoolVar(opt, "with-registry-auth", false, "Send registry authentication details to Swarm agents")
 }
@@ -47,3 +52,21 @@ func loadBundlefile(stderr io.Writer, namespace string, path string) (*bundlefil
 	}
 	return bundle, err
 }
+
+func loadComposefile(stderr io.Writer, path string) (*composefile.Config, error) {
+	fmt.Fprintf(stderr, "Loading Compose file from %s\n", path)
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	config, warnings, err := composefile.LoadFile(reader, os.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s: %v\n", path, err)
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(stderr, warning)
+	}
+	return config, nil
+}
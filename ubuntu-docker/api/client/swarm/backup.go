@@ -0,0 +1,63 @@
+package swarm
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+func newBackupCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup FILE",
+		Short: "Back up the swarm state of this manager to a file",
+		Long:  "Back up the raft store, certificates and node state of this manager to a file, or to STDOUT if FILE is \"-\". The node is briefly stopped while the backup is taken.",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackup(dockerCli, args[0])
+		},
+	}
+	return cmd
+}
+
+func runBackup(dockerCli *client.DockerCli, path string) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	if path == "-" && dockerCli.IsTerminalOut() {
+		return fmt.Errorf("Cowardly refusing to write the backup to a terminal. Use a file name or redirect STDOUT.")
+	}
+
+	responseBody, err := client.SwarmBackup(ctx)
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+
+	if path == "-" {
+		_, err := io.Copy(dockerCli.Out(), responseBody)
+		return err
+	}
+
+	// The backup holds the private key of the swarm CA, keep it private.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, responseBody); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Swarm state backed up to %s\n", path)
+	return nil
+}
@@ -25,6 +25,10 @@ func NewSwarmCommand(dockerCli *client.DockerCli) *cobra.Command {
 		newJoinTokenCommand(dockerCli),
 		newUpdateCommand(dockerCli),
 		newLeaveCommand(dockerCli),
+		newBackupCommand(dockerCli),
+		newRestoreCommand(dockerCli),
+		newUnlockCommand(dockerCli),
+		newUnlockKeyCommand(dockerCli),
 	)
 	return cmd
 }
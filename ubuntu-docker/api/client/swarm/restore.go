@@ -0,0 +1,82 @@
+package swarm
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types/swarm"
+	"github.com/spf13/cobra"
+)
+
+type restoreOptions struct {
+	listenAddr NodeAddrOption
+	// Not a NodeAddrOption because it has no default port.
+	advertiseAddr string
+	unlockKey     string
+}
+
+func newRestoreCommand(dockerCli *client.DockerCli) *cobra.Command {
+	opts := restoreOptions{
+		listenAddr: NewListenAddrOption(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS] FILE",
+		Short: "Initialize a swarm from a backup",
+		Long:  "Initialize a new single manager swarm from a backup created with \"docker swarm backup\". The backup is read from STDIN if FILE is \"-\".",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestore(dockerCli, args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Var(&opts.listenAddr, flagListenAddr, "Listen address (format: <ip|interface>[:port])")
+	flags.StringVar(&opts.advertiseAddr, flagAdvertiseAddr, "", "Advertised address (format: <ip|interface>[:port])")
+	flags.StringVar(&opts.unlockKey, "unlock-key", "", "Unlock key of a backup taken from a locked swarm")
+	return cmd
+}
+
+func runRestore(dockerCli *client.DockerCli, path string, opts restoreOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	var input io.Reader = dockerCli.In()
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	req := swarm.RestoreRequest{
+		ListenAddr:    opts.listenAddr.String(),
+		AdvertiseAddr: opts.advertiseAddr,
+		UnlockKey:     opts.unlockKey,
+	}
+	if err := client.SwarmRestore(ctx, input, req); err != nil {
+		return err
+	}
+
+	info, err := client.Info(ctx)
+	if err != nil {
+		return err
+	}
+	nodeID := info.Swarm.NodeID
+
+	fmt.Fprintf(dockerCli.Out(), "Swarm restored: current node (%s) is now the only manager.\n\n", nodeID)
+
+	if err := printJoinCommand(ctx, dockerCli, nodeID, true, false); err != nil {
+		return err
+	}
+
+	fmt.Fprint(dockerCli.Out(), "To add a manager to this swarm, run 'docker swarm join-token manager' and follow the instructions.\n\n")
+	return nil
+}
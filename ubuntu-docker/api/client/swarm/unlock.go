@@ -0,0 +1,75 @@
+package swarm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/pkg/term"
+	"github.com/docker/engine-api/types/swarm"
+)
+
+func newUnlockCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Unlock swarm",
+		Long:  "Provide the unlock key to start a manager that was locked with autolock. The key is read from STDIN.",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUnlock(dockerCli)
+		},
+	}
+
+	return cmd
+}
+
+func runUnlock(dockerCli *client.DockerCli) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	key, err := readKey(dockerCli, "Please enter unlock key: ")
+	if err != nil {
+		return err
+	}
+
+	req := swarm.UnlockRequest{
+		UnlockKey: key,
+	}
+	return client.SwarmUnlock(ctx, req)
+}
+
+// readKey reads a key from STDIN. If STDIN is a terminal, the prompt is
+// printed and the key isn't echoed back.
+func readKey(dockerCli *client.DockerCli, prompt string) (string, error) {
+	in := dockerCli.In()
+	fd, isTerminal := term.GetFdInfo(in)
+	if isTerminal {
+		fmt.Fprint(dockerCli.Out(), prompt)
+		oldState, err := term.SaveState(fd)
+		if err != nil {
+			return "", err
+		}
+		term.DisableEcho(fd, oldState)
+		defer func() {
+			fmt.Fprint(dockerCli.Out(), "\n")
+			term.RestoreTerminal(fd, oldState)
+		}()
+	}
+
+	key, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", errors.New("unlock key is required")
+	}
+	return key, nil
+}
@@ -316,7 +316,7 @@ func notaryError(repoName string, err error) error {
 }
 
 // TrustedPull handles content trust pulling of an image
-func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.RepositoryInfo, ref registry.Reference, authConfig types.AuthConfig, requestPrivilege types.RequestPrivilegeFunc) error {
+func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.RepositoryInfo, ref registry.Reference, authConfig types.AuthConfig, requestPrivilege types.RequestPrivilegeFunc, platform string) error {
 	var refs []target
 
 	notaryRepo, err := cli.getNotaryRepository(repoInfo, authConfig, "pull")
@@ -378,7 +378,7 @@ func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.Reposi
 		if err != nil {
 			return err
 		}
-		if err := cli.ImagePullPrivileged(ctx, authConfig, ref.String(), requestPrivilege, false); err != nil {
+		if err := cli.ImagePullPrivileged(ctx, authConfig, ref.String(), requestPrivilege, false, platform); err != nil {
 			return err
 		}
 
@@ -569,7 +569,7 @@ func (cli *DockerCli) addTargetToAllSignableRoles(repo *client.NotaryRepository,
 }
 
 // ImagePullPrivileged pulls the image and displays it to the output
-func (cli *DockerCli) ImagePullPrivileged(ctx context.Context, authConfig types.AuthConfig, ref string, requestPrivilege types.RequestPrivilegeFunc, all bool) error {
+func (cli *DockerCli) ImagePullPrivileged(ctx context.Context, authConfig types.AuthConfig, ref string, requestPrivilege types.RequestPrivilegeFunc, all bool, platform string) error {
 
 	encodedAuth, err := EncodeAuthToBase64(authConfig)
 	if err != nil {
@@ -579,6 +579,7 @@ func (cli *DockerCli) ImagePullPrivileged(ctx context.Context, authConfig types.
 		RegistryAuth:  encodedAuth,
 		PrivilegeFunc: requestPrivilege,
 		All:           all,
+		Platform:      platform,
 	}
 
 	responseBody, err := cli.client.ImagePull(ctx, ref, options)
@@ -28,6 +28,8 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 	flMemoryReservation := cmd.String([]string{"-memory-reservation"}, "", "Memory soft limit")
 	flMemorySwap := cmd.String([]string{"-memory-swap"}, "", "Swap limit equal to memory plus swap: '-1' to enable unlimited swap")
 	flKernelMemory := cmd.String([]string{"-kernel-memory"}, "", "Kernel memory limit")
+	flNetworkIngress := cmd.String([]string{"-network-ingress-rate"}, "", "Limit the rate (bytes per second) of the network traffic received by the container: '-1' to remove the limit")
+	flNetworkEgress := cmd.String([]string{"-network-egress-rate"}, "", "Limit the rate (bytes per second) of the network traffic sent by the container: '-1' to remove the limit")
 	flRestartPolicy := cmd.String([]string{"-restart"}, "", "Restart policy to apply when a container exits")
 
 	cmd.Require(flag.Min, 1)
@@ -73,6 +75,16 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 		}
 	}
 
+	networkIngressRate, err := parseNetworkRate(*flNetworkIngress)
+	if err != nil {
+		return err
+	}
+
+	networkEgressRate, err := parseNetworkRate(*flNetworkEgress)
+	if err != nil {
+		return err
+	}
+
 	var restartPolicy container.RestartPolicy
 	if *flRestartPolicy != "" {
 		restartPolicy, err = opts.ParseRestartPolicy(*flRestartPolicy)
@@ -82,16 +94,18 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 	}
 
 	resources := container.Resources{
-		BlkioWeight:       *flBlkioWeight,
-		CpusetCpus:        *flCpusetCpus,
-		CpusetMems:        *flCpusetMems,
-		CPUShares:         *flCPUShares,
-		Memory:            flMemory,
-		MemoryReservation: memoryReservation,
-		MemorySwap:        memorySwap,
-		KernelMemory:      kernelMemory,
-		CPUPeriod:         *flCPUPeriod,
-		CPUQuota:          *flCPUQuota,
+		BlkioWeight:        *flBlkioWeight,
+		CpusetCpus:         *flCpusetCpus,
+		CpusetMems:         *flCpusetMems,
+		CPUShares:          *flCPUShares,
+		Memory:             flMemory,
+		MemoryReservation:  memoryReservation,
+		MemorySwap:         memorySwap,
+		KernelMemory:       kernelMemory,
+		NetworkIngressRate: networkIngressRate,
+		NetworkEgressRate:  networkEgressRate,
+		CPUPeriod:          *flCPUPeriod,
+		CPUQuota:           *flCPUQuota,
 	}
 
 	updateConfig := container.UpdateConfig{
@@ -118,3 +132,15 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 
 	return nil
 }
+
+// parseNetworkRate parses the value of a network rate flag, where -1
+// removes the rate of the container.
+func parseNetworkRate(value string) (int64, error) {
+	switch value {
+	case "":
+		return 0, nil
+	case "-1":
+		return -1, nil
+	}
+	return units.RAMInBytes(value)
+}
@@ -0,0 +1,64 @@
+package volume
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type cloneOptions struct {
+	volume     string
+	name       string
+	driver     string
+	driverOpts opts.MapOpts
+	labels     []string
+}
+
+func newCloneCommand(dockerCli *client.DockerCli) *cobra.Command {
+	opts := cloneOptions{
+		driverOpts: *opts.NewMapOpts(nil, nil),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "clone [OPTIONS] VOLUME NEW_VOLUME",
+		Short: "Create a volume with a copy of the contents of another volume",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.volume = args[0]
+			opts.name = args[1]
+			return runClone(dockerCli, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.driver, "driver", "d", "", "Specify volume driver name, instead of the driver of VOLUME")
+	flags.VarP(&opts.driverOpts, "opt", "o", "Set driver specific options")
+	flags.StringSliceVar(&opts.labels, "label", []string{}, "Set metadata for the volume, instead of the labels of VOLUME")
+
+	return cmd
+}
+
+func runClone(dockerCli *client.DockerCli, opts cloneOptions) error {
+	volReq := types.VolumeCreateRequest{
+		Name:       opts.name,
+		Driver:     opts.driver,
+		DriverOpts: opts.driverOpts.GetAll(),
+	}
+	if len(opts.labels) > 0 {
+		volReq.Labels = runconfigopts.ConvertKVStringsToMap(opts.labels)
+	}
+
+	vol, err := dockerCli.Client().VolumeClone(context.Background(), opts.volume, volReq)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s\n", vol.Name)
+	return nil
+}
@@ -99,4 +99,10 @@ Another example:
 
     $ docker volume create --driver local --opt type=btrfs --opt device=/dev/sda2
 
+The **size** option of the built-in **local** driver on Linux limits the space
+used by the volume, with a project quota when the filesystem supports it, or a
+filesystem image mounted through a loopback device:
+
+    $ docker volume create --driver local --opt size=10g
+
 `
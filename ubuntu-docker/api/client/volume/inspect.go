@@ -11,6 +11,7 @@ import (
 
 type inspectOptions struct {
 	format string
+	size   bool
 	names  []string
 }
 
@@ -28,7 +29,9 @@ func newInspectCommand(dockerCli *client.DockerCli) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.format, "format", "f", "", "Format the output using the given go template")
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", "Format the output using the given go template")
+	flags.BoolVarP(&opts.size, "size", "s", false, "Display the disk usage of the local volumes")
 
 	return cmd
 }
@@ -39,8 +42,7 @@ func runInspect(dockerCli *client.DockerCli, opts inspectOptions) error {
 	ctx := context.Background()
 
 	getVolFunc := func(name string) (interface{}, []byte, error) {
-		i, err := client.VolumeInspect(ctx, name)
-		return i, nil, err
+		return client.VolumeInspectWithRaw(ctx, name, opts.size)
 	}
 
 	return inspect.Inspect(dockerCli.Out(), opts.names, opts.format, getVolFunc)
@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/cli"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
+	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +25,7 @@ func (r byVolumeName) Less(i, j int) bool {
 
 type listOptions struct {
 	quiet  bool
+	size   bool
 	filter []string
 }
 
@@ -43,6 +45,7 @@ func newListCommand(dockerCli *client.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display volume names")
+	flags.BoolVarP(&opts.size, "size", "s", false, "Display the disk usage of the local volumes")
 	flags.StringSliceVarP(&opts.filter, "filter", "f", []string{}, "Provide filter values (i.e. 'dangling=true')")
 
 	return cmd
@@ -60,7 +63,11 @@ func runList(dockerCli *client.DockerCli, opts listOptions) error {
 		}
 	}
 
-	volumes, err := client.VolumeList(context.Background(), volFilterArgs)
+	options := types.VolumeListOptions{
+		Filter: volFilterArgs,
+		Size:   opts.size && !opts.quiet,
+	}
+	volumes, err := client.VolumeList(context.Background(), options)
 	if err != nil {
 		return err
 	}
@@ -71,6 +78,9 @@ func runList(dockerCli *client.DockerCli, opts listOptions) error {
 			fmt.Fprintln(dockerCli.Err(), warn)
 		}
 		fmt.Fprintf(w, "DRIVER \tVOLUME NAME")
+		if options.Size {
+			fmt.Fprintf(w, "\tSIZE")
+		}
 		fmt.Fprintf(w, "\n")
 	}
 
@@ -80,12 +90,24 @@ func runList(dockerCli *client.DockerCli, opts listOptions) error {
 			fmt.Fprintln(w, vol.Name)
 			continue
 		}
+		if options.Size {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", vol.Driver, vol.Name, volumeSize(vol))
+			continue
+		}
 		fmt.Fprintf(w, "%s\t%s\n", vol.Driver, vol.Name)
 	}
 	w.Flush()
 	return nil
 }
 
+// volumeSize returns the disk usage of a volume in a human readable form.
+func volumeSize(vol *types.Volume) string {
+	if vol.UsageData == nil || vol.UsageData.Size < 0 {
+		return "N/A"
+	}
+	return units.HumanSize(float64(vol.UsageData.Size))
+}
+
 var listDescription = `
 
 Lists all the volumes Docker knows about. You can filter using the **-f** or
@@ -93,7 +115,17 @@ Lists all the volumes Docker knows about. You can filter using the **-f** or
 more than one filter,  pass multiple flags (for example,
 **--filter "foo=bar" --filter "bif=baz"**)
 
-There is a single supported filter **dangling=value** which takes a boolean of
-**true** or **false**.
+The currently supported filters are:
+
+* **dangling** (boolean - **true** or **false**) - volumes not referenced by any container
+* **driver=<driver>** - volumes of the driver
+* **name=<name>** - volumes with a name containing the value
+* **since=<timestamp>** - volumes created after the timestamp, which can be a
+  duration relative to now like **24h**
+* **unused-for=<duration>** - volumes not mounted by any container for the
+  duration, like **72h**
+
+The **-s** or **--size** flag displays the disk usage of the volumes of the
+**local** driver, computed by the daemon and cached for a minute.
 
 `
@@ -0,0 +1,38 @@
+package volume
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot COMMAND",
+		Short: "Manage volume snapshots",
+		Long:  snapshotDescription,
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(dockerCli.Err(), "\n"+cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(
+		newSnapshotCreateCommand(dockerCli),
+		newSnapshotListCommand(dockerCli),
+		newSnapshotRemoveCommand(dockerCli),
+		newSnapshotRestoreCommand(dockerCli),
+	)
+	return cmd
+}
+
+var snapshotDescription = `
+The **docker volume snapshot** command has subcommands for managing the
+snapshots of a volume. A snapshot is a read-only copy of the data of the volume
+at the time it was taken, which the volume can be restored to.
+
+Snapshots are supported by the **local** driver when the Docker root is on a
+btrfs or zfs filesystem, and by the volume plugins which advertise them.
+
+`
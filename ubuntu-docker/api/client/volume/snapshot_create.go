@@ -0,0 +1,43 @@
+package volume
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "create VOLUME [SNAPSHOT]",
+		Short: "Take a snapshot of a volume",
+		Long:  snapshotCreateDescription,
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) == 2 {
+				name = args[1]
+			}
+			return runSnapshotCreate(dockerCli, args[0], name)
+		},
+	}
+}
+
+func runSnapshotCreate(dockerCli *client.DockerCli, volume, name string) error {
+	snapshot, err := dockerCli.Client().VolumeSnapshotCreate(context.Background(), volume, types.VolumeSnapshotCreateRequest{Name: name})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s\n", snapshot.Name)
+	return nil
+}
+
+var snapshotCreateDescription = `
+Takes a snapshot of the data of a volume. The snapshot is named after the
+current time if no name is given.
+`
@@ -0,0 +1,81 @@
+package volume
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+type bySnapshotName []types.VolumeSnapshot
+
+func (r bySnapshotName) Len() int      { return len(r) }
+func (r bySnapshotName) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r bySnapshotName) Less(i, j int) bool {
+	return r[i].Name < r[j].Name
+}
+
+type snapshotListOptions struct {
+	volume string
+	quiet  bool
+}
+
+func newSnapshotListCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts snapshotListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS] VOLUME",
+		Aliases: []string{"list"},
+		Short:   "List the snapshots of a volume",
+		Args:    cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.volume = args[0]
+			return runSnapshotList(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display snapshot names")
+
+	return cmd
+}
+
+func runSnapshotList(dockerCli *client.DockerCli, opts snapshotListOptions) error {
+	snapshots, err := dockerCli.Client().VolumeSnapshotList(context.Background(), opts.volume)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	if !opts.quiet {
+		fmt.Fprintf(w, "SNAPSHOT NAME\tCREATED\n")
+	}
+
+	sort.Sort(bySnapshotName(snapshots))
+	for _, s := range snapshots {
+		if opts.quiet {
+			fmt.Fprintln(w, s.Name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", s.Name, snapshotCreated(s))
+	}
+	w.Flush()
+	return nil
+}
+
+// snapshotCreated returns how long ago a snapshot was taken.
+func snapshotCreated(s types.VolumeSnapshot) string {
+	createdAt, err := time.Parse(time.RFC3339Nano, s.CreatedAt)
+	if err != nil {
+		return "N/A"
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(createdAt)) + " ago"
+}
@@ -0,0 +1,27 @@
+package volume
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotRestoreCommand(dockerCli *client.DockerCli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore VOLUME SNAPSHOT",
+		Short: "Restore a volume to a snapshot",
+		Long:  snapshotRestoreDescription,
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dockerCli.Client().VolumeSnapshotRestore(context.Background(), args[0], args[1])
+		},
+	}
+}
+
+var snapshotRestoreDescription = `
+Replaces the data of a volume with a snapshot. You cannot restore a volume that
+is mounted by a running container. On zfs, restoring a volume of the **local**
+driver removes the snapshots taken after the restored one.
+`
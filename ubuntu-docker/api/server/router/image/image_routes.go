@@ -54,6 +54,7 @@ func (s *imageRouter) postCommit(ctx context.Context, w http.ResponseWriter, r *
 			Comment:      r.Form.Get("comment"),
 			Config:       c,
 			MergeConfigs: true,
+			Squash:       httputils.BoolValue(r, "squash"),
 		},
 		Changes: r.Form["changes"],
 	}
@@ -105,7 +106,7 @@ func (s *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrite
 			}
 		}
 
-		err = s.backend.PullImage(ctx, image, tag, metaHeaders, authConfig, output)
+		err = s.backend.PullImage(ctx, image, tag, r.Form.Get("platform"), metaHeaders, authConfig, output)
 	} else { //import
 		src := r.Form.Get("fromSrc")
 		// 'err' MUST NOT be defined within this block, we need any error
@@ -185,7 +186,7 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	if err := s.backend.ExportImage(names, r.Form.Get("format"), output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
@@ -17,4 +17,8 @@ type Backend interface {
 	ConnectContainerToNetwork(containerName, networkName string, endpointConfig *network.EndpointSettings) error
 	DisconnectContainerFromNetwork(containerName string, network libnetwork.Network, force bool) error
 	DeleteNetwork(name string) error
+	CreateNetworkPolicy(idName string, policy types.NetworkPolicy) error
+	NetworkPolicies(idName string) ([]types.NetworkPolicy, error)
+	DeleteNetworkPolicy(idName, name string) error
+	NetworkStats(idName string) (*types.NetworkTrafficStats, error)
 }
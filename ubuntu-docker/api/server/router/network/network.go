@@ -31,12 +31,16 @@ func (r *networkRouter) initRoutes() {
 	r.routes = []router.Route{
 		// GET
 		router.NewGetRoute("/networks", r.getNetworksList),
+		router.NewGetRoute("/networks/{id:.*}/policies", r.getNetworkPolicies),
+		router.NewGetRoute("/networks/{id:.*}/stats", r.getNetworkStats),
 		router.NewGetRoute("/networks/{id:.*}", r.getNetwork),
 		// POST
 		router.NewPostRoute("/networks/create", r.postNetworkCreate),
+		router.NewPostRoute("/networks/{id:.*}/policies", r.postNetworkPolicyCreate),
 		router.NewPostRoute("/networks/{id:.*}/connect", r.postNetworkConnect),
 		router.NewPostRoute("/networks/{id:.*}/disconnect", r.postNetworkDisconnect),
 		// DELETE
+		router.NewDeleteRoute("/networks/{id:.*}/policies/{name:.*}", r.deleteNetworkPolicy),
 		router.NewDeleteRoute("/networks/{id:.*}", r.deleteNetwork),
 	}
 }
@@ -174,6 +174,51 @@ func (n *networkRouter) deleteNetwork(ctx context.Context, w http.ResponseWriter
 	return nil
 }
 
+func (n *networkRouter) getNetworkPolicies(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	policies, err := n.backend.NetworkPolicies(vars["id"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, policies)
+}
+
+func (n *networkRouter) getNetworkStats(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	stats, err := n.backend.NetworkStats(vars["id"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, stats)
+}
+
+func (n *networkRouter) postNetworkPolicyCreate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var policy types.NetworkPolicy
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		return err
+	}
+
+	if err := n.backend.CreateNetworkPolicy(vars["id"], policy); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+func (n *networkRouter) deleteNetworkPolicy(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := n.backend.DeleteNetworkPolicy(vars["id"], vars["name"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
 func (n *networkRouter) buildNetworkResource(nw libnetwork.Network) *types.NetworkResource {
 	r := &types.NetworkResource{}
 	if nw == nil {
@@ -1,6 +1,8 @@
 package swarm
 
 import (
+	"io"
+
 	basictypes "github.com/docker/engine-api/types"
 	types "github.com/docker/engine-api/types/swarm"
 )
@@ -12,6 +14,10 @@ type Backend interface {
 	Leave(force bool) error
 	Inspect() (types.Swarm, error)
 	Update(uint64, types.Spec, types.UpdateFlags) error
+	Backup(out io.Writer) error
+	Restore(in io.Reader, req types.RestoreRequest) error
+	UnlockSwarm(req types.UnlockRequest) error
+	GetUnlockKey() (string, error)
 	GetServices(basictypes.ServiceListOptions) ([]types.Service, error)
 	GetService(string) (types.Service, error)
 	CreateService(types.ServiceSpec, string) (string, error)
@@ -1,6 +1,8 @@
 package volume
 
 import (
+	"io"
+
 	// TODO return types need to be refactored into pkg
 	"github.com/docker/engine-api/types"
 )
@@ -8,8 +10,15 @@ import (
 // Backend is the methods that need to be implemented to provide
 // volume specific functionality
 type Backend interface {
-	Volumes(filter string) ([]*types.Volume, []string, error)
-	VolumeInspect(name string) (*types.Volume, error)
+	Volumes(filter string, size bool) ([]*types.Volume, []string, error)
+	VolumeInspect(name string, size bool) (*types.Volume, error)
 	VolumeCreate(name, driverName string, opts, labels map[string]string) (*types.Volume, error)
 	VolumeRm(name string) error
+	VolumeExport(name string, out io.Writer) error
+	VolumeImport(name string, content io.Reader) error
+	VolumeClone(name, newName, driverName string, opts, labels map[string]string) (*types.Volume, error)
+	VolumeSnapshotCreate(name, snapshot string) (*types.VolumeSnapshot, error)
+	VolumeSnapshotList(name string) ([]types.VolumeSnapshot, error)
+	VolumeSnapshotRestore(name, snapshot string) error
+	VolumeSnapshotRemove(name, snapshot string) error
 }
@@ -121,6 +121,9 @@ type Backend interface {
 	ContainerRm(name string, config *types.ContainerRmConfig) error
 	// Commit creates a new Docker image from an existing Docker container.
 	Commit(string, *backend.ContainerCommitConfig) (string, error)
+	// SquashImage merges the layers of the image id added on top of the
+	// image parent into a single layer, and returns the ID of the new image.
+	SquashImage(id, parent string) (string, error)
 	// ContainerKill stops the container execution abruptly.
 	ContainerKill(containerID string, sig uint64) error
 	// ContainerStart starts a new container
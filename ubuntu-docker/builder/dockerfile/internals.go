@@ -386,6 +386,7 @@ func containsWildcards(name string) bool {
 }
 
 func (b *Builder) processImageFrom(img builder.Image) error {
+	b.from = img
 	if img != nil {
 		b.image = img.ImageID()
 
@@ -4,6 +4,7 @@ import (
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/api/client/container"
 	"github.com/docker/docker/api/client/image"
+	"github.com/docker/docker/api/client/manifest"
 	"github.com/docker/docker/api/client/network"
 	"github.com/docker/docker/api/client/node"
 	"github.com/docker/docker/api/client/plugin"
@@ -79,6 +80,7 @@ func NewCobraAdaptor(clientFlags *cliflags.ClientFlags) CobraAdaptor {
 		image.NewSearchCommand(dockerCli),
 		image.NewImportCommand(dockerCli),
 		image.NewTagCommand(dockerCli),
+		manifest.NewManifestCommand(dockerCli),
 		network.NewNetworkCommand(dockerCli),
 		system.NewEventsCommand(dockerCli),
 		registry.NewLoginCommand(dockerCli),
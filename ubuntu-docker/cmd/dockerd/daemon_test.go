@@ -292,3 +292,65 @@ func TestLoadDaemonConfigWithRegistryOptions(t *testing.T) {
 		t.Fatal("expected disable-legacy-registry to be true, got false")
 	}
 }
+
+func TestLoadDaemonConfigWithRegistryMirrorsMap(t *testing.T) {
+	c := &daemon.Config{}
+	common := &cliflags.CommonFlags{}
+	flags := mflag.NewFlagSet("test", mflag.ContinueOnError)
+	c.ServiceOptions.InstallCliFlags(flags, absentFromHelp)
+
+	f, err := ioutil.TempFile("", "docker-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := f.Name()
+	defer os.Remove(configFile)
+
+	f.Write([]byte(`{"registry-mirrors-map": {"registry.corp:5000": ["https://mirror.corp", "https://mirror2.corp"]}}`))
+	f.Close()
+
+	loadedConfig, err := loadDaemonCliConfig(c, flags, common, configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedConfig == nil {
+		t.Fatal("expected configuration, got nil")
+	}
+
+	m := loadedConfig.MirrorsMap["registry.corp:5000"]
+	if len(m) != 2 {
+		t.Fatalf("expected 2 mirrors for registry.corp:5000, got %d", len(m))
+	}
+}
+
+func TestLoadDaemonConfigWithCredentialHelpers(t *testing.T) {
+	c := &daemon.Config{}
+	common := &cliflags.CommonFlags{}
+	flags := mflag.NewFlagSet("test", mflag.ContinueOnError)
+	c.ServiceOptions.InstallCliFlags(flags, absentFromHelp)
+
+	f, err := ioutil.TempFile("", "docker-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := f.Name()
+	defer os.Remove(configFile)
+
+	f.Write([]byte(`{"credential-helpers": {"123456789012.dkr.ecr.us-east-1.amazonaws.com": "ecr-login", "gcr.io": "gcr"}}`))
+	f.Close()
+
+	loadedConfig, err := loadDaemonCliConfig(c, flags, common, configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedConfig == nil {
+		t.Fatal("expected configuration, got nil")
+	}
+
+	if h := loadedConfig.CredentialHelpers["gcr.io"]; h != "gcr" {
+		t.Fatalf("expected gcr credential helper for gcr.io, got %q", h)
+	}
+	if h := loadedConfig.CredentialHelpers["123456789012.dkr.ecr.us-east-1.amazonaws.com"]; h != "ecr-login" {
+		t.Fatalf("expected ecr-login credential helper, got %q", h)
+	}
+}
@@ -290,6 +290,12 @@ func (container *Container) GetRootResourcePath(path string) (string, error) {
 	return symlink.FollowSymlinkInScope(filepath.Join(container.Root, cleanPath), container.Root)
 }
 
+// VolumeSubpathDir returns the directory the subpaths of the volumes of the
+// container are mounted in, before they are mounted in the container.
+func (container *Container) VolumeSubpathDir() (string, error) {
+	return container.GetRootResourcePath("subpaths")
+}
+
 // ExitOnNext signals to the monitor that it should not restart the container
 // after we send the kill signal.
 func (container *Container) ExitOnNext() {
@@ -852,6 +858,13 @@ func (container *Container) BuildCreateEndpointOptions(n libnetwork.Network, epC
 		}
 	}
 
+	// the network policies select the endpoints by the labels of their container
+	if len(container.Config.Labels) > 0 {
+		createOptions = append(createOptions, libnetwork.EndpointOptionGeneric(options.Generic{
+			netlabel.EndpointLabels: container.Config.Labels,
+		}))
+	}
+
 	// Port-mapping rules belong to the container & applicable only to non-internal networks
 	portmaps := GetSandboxPortMapInfo(sb)
 	if n.Info().Internal() || len(portmaps) > 0 {
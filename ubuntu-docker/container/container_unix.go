@@ -297,6 +297,19 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	if resources.KernelMemory != 0 {
 		cResources.KernelMemory = resources.KernelMemory
 	}
+	// a rate of -1 removes the network rate of the container
+	if resources.NetworkIngressRate != 0 {
+		cResources.NetworkIngressRate = resources.NetworkIngressRate
+		if resources.NetworkIngressRate == -1 {
+			cResources.NetworkIngressRate = 0
+		}
+	}
+	if resources.NetworkEgressRate != 0 {
+		cResources.NetworkEgressRate = resources.NetworkEgressRate
+		if resources.NetworkEgressRate == -1 {
+			cResources.NetworkEgressRate = 0
+		}
+	}
 
 	// update HostConfig of container
 	if hostConfig.RestartPolicy.Name != "" {
@@ -322,13 +335,18 @@ func (container *Container) UnmountVolumes(forceSyscall bool, volumeEventLog fun
 		err          error
 	)
 
+	subpathDir, err := container.VolumeSubpathDir()
+	if err != nil {
+		return err
+	}
+
 	for _, mntPoint := range container.MountPoints {
 		dest, err := container.GetResourcePath(mntPoint.Destination)
 		if err != nil {
 			return err
 		}
 
-		volumeMounts = append(volumeMounts, volume.MountPoint{Destination: dest, Volume: mntPoint.Volume, ID: mntPoint.ID})
+		volumeMounts = append(volumeMounts, volume.MountPoint{Destination: dest, Volume: mntPoint.Volume, ID: mntPoint.ID, Subpath: mntPoint.Subpath})
 	}
 
 	// Append any network mounts to the list (this is a no-op on Windows)
@@ -344,6 +362,9 @@ func (container *Container) UnmountVolumes(forceSyscall bool, volumeEventLog fun
 		}
 
 		if volumeMount.Volume != nil {
+			if err := volumeMount.UnmountSubpath(subpathDir); err != nil {
+				return err
+			}
 			if err := volumeMount.Volume.Unmount(volumeMount.ID); err != nil {
 				return err
 			}
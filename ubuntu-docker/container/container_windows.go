@@ -67,7 +67,8 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 		resources.CPUPeriod != 0 || resources.CPUQuota != 0 ||
 		resources.CpusetCpus != "" || resources.CpusetMems != "" ||
 		resources.Memory != 0 || resources.MemorySwap != 0 ||
-		resources.MemoryReservation != 0 || resources.KernelMemory != 0 {
+		resources.MemoryReservation != 0 || resources.KernelMemory != 0 ||
+		resources.NetworkIngressRate != 0 || resources.NetworkEgressRate != 0 {
 		return fmt.Errorf("Resource updating isn't supported on Windows")
 	}
 	// update HostConfig of container
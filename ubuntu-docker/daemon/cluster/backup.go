@@ -0,0 +1,211 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/archive"
+	types "github.com/docker/engine-api/types/swarm"
+	swarmca "github.com/docker/swarmkit/ca"
+	"github.com/docker/swarmkit/manager/encryption"
+)
+
+// backupPaths are the parts of the swarm directory that make up the state of
+// a manager: the docker and swarmkit node state, the certificates and the
+// raft store. The worker task database and the control socket belong to the
+// running node and are left out.
+var backupPaths = []string{stateFile, "state.json", "certificates", "raft"}
+
+// Backup writes a gzip compressed tar archive of the manager state to out.
+// The raft store is only consistent while it is not written to, so the node
+// is stopped while the archive is created and started again afterwards.
+func (c *Cluster) Backup(out io.Writer) error {
+	c.Lock()
+	node := c.node
+	if node == nil {
+		c.Unlock()
+		return ErrNoSwarm
+	}
+	if node.Manager() == nil {
+		c.Unlock()
+		return fmt.Errorf("This node is not a swarm manager. Only a manager holds the swarm state that can be backed up.")
+	}
+
+	// The archive is written to a temporary file first so that the node
+	// doesn't stay down while the backup is streamed to the client.
+	tmp, err := ioutil.TempFile(c.config.Root, "swarm-backup")
+	if err != nil {
+		c.Unlock()
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := c.stopNode(); err != nil {
+		c.Unlock()
+		return err
+	}
+	backupErr := writeBackupArchive(c.root, tmp)
+
+	n, err := c.startNewNode(false, c.localAddr, c.remoteAddr, c.listenAddr, c.advertiseAddr, "", "")
+	if err != nil {
+		c.err = err
+		c.Unlock()
+		return fmt.Errorf("swarm component could not be restarted after backup: %v", err)
+	}
+	c.Unlock()
+
+	select {
+	case <-time.After(swarmConnectTimeout):
+		logrus.Errorf("swarm component could not be restarted before timeout was reached")
+	case <-n.Ready():
+	case <-n.done:
+		c.RLock()
+		defer c.RUnlock()
+		return fmt.Errorf("swarm component could not be restarted after backup: %v", c.err)
+	}
+	go c.reconnectOnFailure(n)
+
+	if backupErr != nil {
+		return backupErr
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		return err
+	}
+	_, err = io.Copy(out, tmp)
+	return err
+}
+
+// writeBackupArchive writes the backupPaths of the swarm directory root to
+// out as a gzip compressed tar archive.
+func writeBackupArchive(root string, out io.Writer) error {
+	rdr, err := archive.TarWithOptions(root, &archive.TarOptions{
+		Compression:  archive.Gzip,
+		IncludeFiles: backupPaths,
+	})
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+
+	_, err = io.Copy(out, rdr)
+	return err
+}
+
+// backupRequiredPaths must be found in a backup for a manager to start from
+// it.
+var backupRequiredPaths = []string{"certificates/swarm-node.crt", "certificates/swarm-node.key", "raft"}
+
+// extractBackupArchive extracts an archive written by writeBackupArchive to
+// the empty swarm directory root, and checks that it holds the state of a
+// manager and nothing else.
+func extractBackupArchive(in io.Reader, root string) error {
+	if err := archive.Untar(in, root, &archive.TarOptions{NoLchown: true}); err != nil {
+		return fmt.Errorf("could not extract swarm backup: %v", err)
+	}
+
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		known := false
+		for _, p := range backupPaths {
+			if entry.Name() == p {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("invalid swarm backup: unexpected %s", entry.Name())
+		}
+	}
+	for _, p := range backupRequiredPaths {
+		if _, err := os.Stat(filepath.Join(root, p)); err != nil {
+			return fmt.Errorf("invalid swarm backup: %s is missing", p)
+		}
+	}
+	return nil
+}
+
+// Restore initializes a new single manager cluster from an archive created
+// by Backup. Services, networks and nodes are kept; the other managers of
+// the old cluster are removed from the raft membership, as with
+// --force-new-cluster.
+func (c *Cluster) Restore(in io.Reader, req types.RestoreRequest) error {
+	c.Lock()
+	if c.locked {
+		c.Unlock()
+		return ErrSwarmLocked
+	}
+	if c.node != nil {
+		c.Unlock()
+		return ErrSwarmExists
+	}
+
+	var unlockKey []byte
+	if req.UnlockKey != "" {
+		key, err := encryption.ParseHumanReadableKey(req.UnlockKey)
+		if err != nil {
+			c.Unlock()
+			return fmt.Errorf("Invalid unlock key: %v", err)
+		}
+		unlockKey = key
+	}
+
+	if req.ListenAddr == "" {
+		req.ListenAddr = defaultAddr
+	}
+	listenAddr, err := validateAddr(req.ListenAddr)
+	if err != nil {
+		c.Unlock()
+		return fmt.Errorf("invalid ListenAddr %q: %v", req.ListenAddr, err)
+	}
+
+	localAddr, listenAddr, advertiseAddr, err := c.resolveInitAddrs(listenAddr, req.AdvertiseAddr)
+	if err != nil {
+		c.Unlock()
+		return err
+	}
+
+	if err := c.clearState(); err != nil {
+		c.Unlock()
+		return err
+	}
+	if err := extractBackupArchive(in, c.root); err != nil {
+		c.clearState()
+		c.Unlock()
+		return err
+	}
+
+	c.unlockKey = unlockKey
+	n, err := c.startNewNode(true, localAddr, "", listenAddr, advertiseAddr, "", "")
+	if err != nil {
+		c.unlockKey = nil
+		c.clearState()
+		c.Unlock()
+		if err == swarmca.ErrInvalidKEK {
+			return fmt.Errorf("The swarm backup is locked. Please provide its unlock key.")
+		}
+		return err
+	}
+	c.Unlock()
+
+	select {
+	case <-n.Ready():
+		go c.reconnectOnFailure(n)
+		return nil
+	case <-n.done:
+		c.RLock()
+		defer c.RUnlock()
+		if err := c.clearState(); err != nil {
+			return err
+		}
+		return c.err
+	}
+}
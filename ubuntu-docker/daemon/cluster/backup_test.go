@@ -0,0 +1,134 @@
+package cluster
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/archive"
+)
+
+// writeSwarmDir creates the files in dir, keyed by their path.
+func writeSwarmDir(t *testing.T, dir string, files map[string]string) {
+	for p, content := range files {
+		path := filepath.Join(dir, p)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func managerFiles() map[string]string {
+	return map[string]string{
+		stateFile:                        `{"LocalAddr":"10.0.0.1"}`,
+		"state.json":                     `[{"node_id":"abc"}]`,
+		"certificates/swarm-node.crt":    "node certificate",
+		"certificates/swarm-node.key":    "node key",
+		"certificates/swarm-root-ca.crt": "root certificate",
+		"raft/wal-v3-encrypted/0.wal":    "wal",
+		"raft/snap-v3-encrypted/0.snap":  "snapshot",
+		"worker/tasks.db":                "tasks",
+		"control.sock":                   "",
+	}
+}
+
+func TestBackupArchiveRoundTrip(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "swarm-backup-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	src, dst := filepath.Join(tmp, "src"), filepath.Join(tmp, "dst")
+	files := managerFiles()
+	writeSwarmDir(t, src, files)
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeBackupArchive(src, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractBackupArchive(&buf, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	for p, content := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dst, p))
+		if p == "worker/tasks.db" || p == "control.sock" {
+			if !os.IsNotExist(err) {
+				t.Fatalf("expected %s to be left out of the backup, got %v", p, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("expected %s in the restored backup: %v", p, err)
+		}
+		if string(data) != content {
+			t.Fatalf("expected %s to contain %q, got %q", p, content, data)
+		}
+	}
+}
+
+// tarFiles returns a gzip compressed tar archive of the files.
+func tarFiles(t *testing.T, files map[string]string) []byte {
+	dir, err := ioutil.TempDir("", "swarm-backup-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeSwarmDir(t, dir, files)
+
+	rdr, err := archive.Tar(dir, archive.Gzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Close()
+	data, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestExtractBackupArchiveInvalid(t *testing.T) {
+	files := managerFiles()
+	delete(files, "worker/tasks.db")
+	delete(files, "control.sock")
+	valid := tarFiles(t, files)
+
+	noRaft := managerFiles()
+	for p := range noRaft {
+		if !strings.HasPrefix(p, "certificates/swarm-") && p != stateFile {
+			delete(noRaft, p)
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"truncated", valid[:len(valid)/2], "could not extract swarm backup"},
+		{"not an archive", []byte("this is not a swarm backup"), "could not extract swarm backup"},
+		{"missing raft", tarFiles(t, noRaft), "invalid swarm backup: raft is missing"},
+		{"other archive", tarFiles(t, map[string]string{"etc/hostname": "host"}), "invalid swarm backup: unexpected etc"},
+	} {
+		dst, err := ioutil.TempDir("", "swarm-backup-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = extractBackupArchive(bytes.NewReader(tc.data), dst)
+		os.RemoveAll(dst)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.expected, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"bytes"
 	"encoding/json"
 	stdliberrors "errors"
 	"fmt"
@@ -27,6 +28,8 @@ import (
 	types "github.com/docker/engine-api/types/swarm"
 	swarmagent "github.com/docker/swarmkit/agent"
 	swarmapi "github.com/docker/swarmkit/api"
+	swarmca "github.com/docker/swarmkit/ca"
+	"github.com/docker/swarmkit/manager/encryption"
 	"golang.org/x/net/context"
 )
 
@@ -51,6 +54,9 @@ var ErrSwarmExists = fmt.Errorf("This node is already part of a swarm. Use \"doc
 // ErrPendingSwarmExists is returned on initialize or join request for a cluster that is already processing a similar request but has not succeeded yet.
 var ErrPendingSwarmExists = fmt.Errorf("This node is processing an existing join request that has not succeeded yet. Use \"docker swarm leave\" to cancel the current request.")
 
+// ErrSwarmLocked is returned if the swarm is encrypted and needs a key to unlock it.
+var ErrSwarmLocked = fmt.Errorf("Swarm is encrypted and needs to be unlocked before it can be used. Please use \"docker swarm unlock\" to unlock it.")
+
 // ErrSwarmJoinTimeoutReached is returned when cluster join could not complete before timeout was reached.
 var ErrSwarmJoinTimeoutReached = fmt.Errorf("Timeout was reached before node was joined. The attempt to join the swarm will continue in the background. Use the \"docker info\" command to see the current swarm status of your node.")
 
@@ -122,6 +128,8 @@ type Cluster struct {
 	stop            bool
 	err             error
 	cancelDelay     func()
+	locked          bool   // the local manager can't start until it is unlocked
+	unlockKey       []byte // key to unlock the node with on restart, not persisted
 }
 
 type node struct {
@@ -154,6 +162,17 @@ func New(config Config) (*Cluster, error) {
 	}
 
 	n, err := c.startNewNode(false, st.LocalAddr, st.RemoteAddr, st.ListenAddr, st.AdvertiseAddr, "", "")
+	if err == swarmca.ErrInvalidKEK {
+		// The manager state is encrypted. Keep the addresses around so
+		// that the node can be started once it is unlocked.
+		c.locked = true
+		c.localAddr = st.LocalAddr
+		c.remoteAddr = st.RemoteAddr
+		c.listenAddr = st.ListenAddr
+		c.advertiseAddr = st.AdvertiseAddr
+		logrus.Warn("swarm is locked, use \"docker swarm unlock\" to start it")
+		return c, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -285,6 +304,7 @@ func (c *Cluster) startNewNode(forceNewCluster bool, localAddr, remoteAddr, list
 		Executor:           container.NewExecutor(c.config.Backend),
 		HeartbeatTick:      1,
 		ElectionTick:       3,
+		UnlockKey:          c.unlockKey,
 	})
 	if err != nil {
 		return nil, err
@@ -315,6 +335,8 @@ func (c *Cluster) startNewNode(forceNewCluster bool, localAddr, remoteAddr, list
 		c.Lock()
 		c.node = nil
 		c.err = err
+		// The key may have been rotated while the node was running.
+		c.unlockKey = n.UnlockKey()
 		c.Unlock()
 		close(node.done)
 	}()
@@ -332,13 +354,23 @@ func (c *Cluster) startNewNode(forceNewCluster bool, localAddr, remoteAddr, list
 	}()
 
 	go func() {
+		// The control socket is only available while the node is a
+		// manager, which is also when the swarm store can be watched
+		// for events.
+		cancelEvents := func() {}
+		defer func() { cancelEvents() }()
 		for conn := range n.ListenControlSocket(ctx) {
 			c.Lock()
 			if node.conn != conn {
+				cancelEvents()
+				cancelEvents = func() {}
 				if conn == nil {
 					node.client = nil
 				} else {
 					node.client = swarmapi.NewControlClient(conn)
+					var eventsCtx context.Context
+					eventsCtx, cancelEvents = context.WithCancel(ctx)
+					go c.watchEvents(eventsCtx, n)
 				}
 			}
 			node.conn = conn
@@ -353,6 +385,10 @@ func (c *Cluster) startNewNode(forceNewCluster bool, localAddr, remoteAddr, list
 // Init initializes new cluster from user provided request.
 func (c *Cluster) Init(req types.InitRequest) (string, error) {
 	c.Lock()
+	if c.locked {
+		c.Unlock()
+		return "", ErrSwarmLocked
+	}
 	if node := c.node; node != nil {
 		if !req.ForceNewCluster {
 			c.Unlock()
@@ -369,17 +405,51 @@ func (c *Cluster) Init(req types.InitRequest) (string, error) {
 		return "", err
 	}
 
-	listenHost, listenPort, err := resolveListenAddr(req.ListenAddr)
+	localAddr, listenAddr, advertiseAddr, err := c.resolveInitAddrs(req.ListenAddr, req.AdvertiseAddr)
 	if err != nil {
 		c.Unlock()
 		return "", err
 	}
 
-	advertiseHost, advertisePort, err := c.resolveAdvertiseAddr(req.AdvertiseAddr, listenPort)
+	// todo: check current state existing
+	n, err := c.startNewNode(req.ForceNewCluster, localAddr, "", listenAddr, advertiseAddr, "", "")
 	if err != nil {
 		c.Unlock()
 		return "", err
 	}
+	c.Unlock()
+
+	select {
+	case <-n.Ready():
+		if err := initClusterSpec(n, req.Spec); err != nil {
+			return "", err
+		}
+		go c.reconnectOnFailure(n)
+		return n.NodeID(), nil
+	case <-n.done:
+		c.RLock()
+		defer c.RUnlock()
+		if !req.ForceNewCluster { // if failure on first attempt don't keep state
+			if err := c.clearState(); err != nil {
+				return "", err
+			}
+		}
+		return "", c.err
+	}
+}
+
+// resolveInitAddrs resolves the local, listen and advertise addresses of a
+// node that starts a new cluster.
+func (c *Cluster) resolveInitAddrs(listenAddr, advertiseAddr string) (string, string, string, error) {
+	listenHost, listenPort, err := resolveListenAddr(listenAddr)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	advertiseHost, advertisePort, err := c.resolveAdvertiseAddr(advertiseAddr, listenPort)
+	if err != nil {
+		return "", "", "", err
+	}
 
 	localAddr := listenHost
 
@@ -390,8 +460,7 @@ func (c *Cluster) Init(req types.InitRequest) (string, error) {
 		advertiseIP := net.ParseIP(advertiseHost)
 		if advertiseIP == nil {
 			// not an IP
-			c.Unlock()
-			return "", errMustSpecifyListenAddr
+			return "", "", "", errMustSpecifyListenAddr
 		}
 
 		systemIPs := listSystemIPs()
@@ -404,42 +473,21 @@ func (c *Cluster) Init(req types.InitRequest) (string, error) {
 			}
 		}
 		if !found {
-			c.Unlock()
-			return "", errMustSpecifyListenAddr
+			return "", "", "", errMustSpecifyListenAddr
 		}
 		localAddr = advertiseIP.String()
 	}
 
-	// todo: check current state existing
-	n, err := c.startNewNode(req.ForceNewCluster, localAddr, "", net.JoinHostPort(listenHost, listenPort), net.JoinHostPort(advertiseHost, advertisePort), "", "")
-	if err != nil {
-		c.Unlock()
-		return "", err
-	}
-	c.Unlock()
-
-	select {
-	case <-n.Ready():
-		if err := initClusterSpec(n, req.Spec); err != nil {
-			return "", err
-		}
-		go c.reconnectOnFailure(n)
-		return n.NodeID(), nil
-	case <-n.done:
-		c.RLock()
-		defer c.RUnlock()
-		if !req.ForceNewCluster { // if failure on first attempt don't keep state
-			if err := c.clearState(); err != nil {
-				return "", err
-			}
-		}
-		return "", c.err
-	}
+	return localAddr, net.JoinHostPort(listenHost, listenPort), net.JoinHostPort(advertiseHost, advertisePort), nil
 }
 
 // Join makes current Cluster part of an existing swarm cluster.
 func (c *Cluster) Join(req types.JoinRequest) error {
 	c.Lock()
+	if c.locked {
+		c.Unlock()
+		return ErrSwarmLocked
+	}
 	if node := c.node; node != nil {
 		c.Unlock()
 		return ErrSwarmExists
@@ -516,6 +564,15 @@ func (c *Cluster) stopNode() error {
 func (c *Cluster) Leave(force bool) error {
 	c.Lock()
 	node := c.node
+	if node == nil && c.locked {
+		defer c.Unlock()
+		if !force {
+			return fmt.Errorf("Swarm is locked. Leaving it erases the encrypted state of this node, use `--force` to ignore this message or \"docker swarm unlock\" to unlock it first.")
+		}
+		c.locked = false
+		c.unlockKey = nil
+		return c.clearState()
+	}
 	if node == nil {
 		c.Unlock()
 		return ErrNoSwarm
@@ -547,6 +604,7 @@ func (c *Cluster) Leave(force bool) error {
 		c.Unlock()
 		return err
 	}
+	c.unlockKey = nil
 	c.Unlock()
 	if nodeID := node.NodeID(); nodeID != "" {
 		for _, id := range c.config.Backend.ListContainersForNode(nodeID) {
@@ -625,6 +683,8 @@ func (c *Cluster) Update(version uint64, spec types.Spec, flags types.UpdateFlag
 		return err
 	}
 
+	prevUnlockKey := c.node.UnlockKey()
+
 	_, err = c.client.UpdateCluster(
 		ctx,
 		&swarmapi.UpdateClusterRequest{
@@ -634,12 +694,90 @@ func (c *Cluster) Update(version uint64, spec types.Spec, flags types.UpdateFlag
 				Index: version,
 			},
 			Rotation: swarmapi.JoinTokenRotation{
-				RotateWorkerToken:  flags.RotateWorkerToken,
-				RotateManagerToken: flags.RotateManagerToken,
+				RotateWorkerToken:      flags.RotateWorkerToken,
+				RotateManagerToken:     flags.RotateManagerToken,
+				RotateManagerUnlockKey: flags.RotateManagerUnlockKey,
 			},
 		},
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Wait for the new unlock key to be applied to the local node, so that
+	// GetUnlockKey returns it right away.
+	autoLock := spec.EncryptionConfig.AutoLockManagers
+	if autoLock && (prevUnlockKey == nil || flags.RotateManagerUnlockKey) || !autoLock && prevUnlockKey != nil {
+		waitForUnlockKeyChange(ctx, c.node, prevUnlockKey)
+	}
+	return nil
+}
+
+// GetUnlockKey returns the unlock key of the local manager.
+func (c *Cluster) GetUnlockKey() (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if !c.isActiveManager() {
+		return "", c.errNoManager()
+	}
+
+	ctx, cancel := c.getRequestContext()
+	defer cancel()
+
+	swarm, err := getSwarm(ctx, c.client)
+	if err != nil {
+		return "", err
+	}
+	if !swarm.Spec.EncryptionConfig.AutoLockManagers {
+		return "", fmt.Errorf("Autolock is not enabled for this swarm. Use \"docker swarm update --autolock\" to enable it.")
+	}
+
+	unlockKey := c.node.UnlockKey()
+	if unlockKey == nil {
+		return "", fmt.Errorf("The unlock key has not been applied to this node yet. Please try again.")
+	}
+	return encryption.HumanReadableKey(unlockKey), nil
+}
+
+// UnlockSwarm provides a key to start a locked manager.
+func (c *Cluster) UnlockSwarm(req types.UnlockRequest) error {
+	c.Lock()
+	if !c.locked {
+		c.Unlock()
+		return fmt.Errorf("This node is not locked.")
+	}
+
+	unlockKey, err := encryption.ParseHumanReadableKey(req.UnlockKey)
+	if err != nil {
+		c.Unlock()
+		return fmt.Errorf("Invalid unlock key: %v", err)
+	}
+
+	c.unlockKey = unlockKey
+	n, err := c.startNewNode(false, c.localAddr, c.remoteAddr, c.listenAddr, c.advertiseAddr, "", "")
+	if err != nil {
+		c.unlockKey = nil
+		c.Unlock()
+		if err == swarmca.ErrInvalidKEK {
+			return fmt.Errorf("Invalid unlock key: the swarm could not be decrypted with it.")
+		}
+		return err
+	}
+	c.locked = false
+	c.Unlock()
+
+	select {
+	case <-time.After(swarmConnectTimeout):
+		logrus.Errorf("swarm component could not be started before timeout was reached")
+	case <-n.Ready():
+	case <-n.done:
+		c.RLock()
+		defer c.RUnlock()
+		return fmt.Errorf("swarm component could not be started: %v", c.err)
+	}
+	go c.reconnectOnFailure(n)
+	return nil
 }
 
 // IsManager returns true if Cluster is participating as a manager.
@@ -719,7 +857,9 @@ func (c *Cluster) Info() types.Info {
 	c.RLock()
 	defer c.RUnlock()
 
-	if c.node == nil {
+	if c.locked {
+		info.LocalNodeState = types.LocalNodeStateLocked
+	} else if c.node == nil {
 		info.LocalNodeState = types.LocalNodeStateInactive
 		if c.cancelDelay != nil {
 			info.LocalNodeState = types.LocalNodeStateError
@@ -775,6 +915,9 @@ func (c *Cluster) isActiveManager() bool {
 // errNoManager returns error describing why manager commands can't be used.
 // Call with read lock.
 func (c *Cluster) errNoManager() error {
+	if c.locked {
+		return ErrSwarmLocked
+	}
 	if c.node == nil {
 		return fmt.Errorf("This node is not a swarm manager. Use \"docker swarm init\" or \"docker swarm join\" to connect this node to swarm and try again.")
 	}
@@ -807,10 +950,17 @@ func (c *Cluster) GetServices(options apitypes.ServiceListOptions) ([]types.Serv
 		return nil, err
 	}
 
+	statuses, err := getServiceStatuses(ctx, c.client, r.Services)
+	if err != nil {
+		return nil, err
+	}
+
 	services := []types.Service{}
 
 	for _, service := range r.Services {
-		services = append(services, convert.ServiceFromGRPC(*service))
+		svc := convert.ServiceFromGRPC(*service)
+		svc.ServiceStatus = statuses[service.ID]
+		services = append(services, svc)
 	}
 
 	return services, nil
@@ -870,7 +1020,13 @@ func (c *Cluster) GetService(input string) (types.Service, error) {
 	if err != nil {
 		return types.Service{}, err
 	}
-	return convert.ServiceFromGRPC(*service), nil
+	statuses, err := getServiceStatuses(ctx, c.client, []*swarmapi.Service{service})
+	if err != nil {
+		return types.Service{}, err
+	}
+	svc := convert.ServiceFromGRPC(*service)
+	svc.ServiceStatus = statuses[service.ID]
+	return svc, nil
 }
 
 // UpdateService updates existing service to match new properties.
@@ -1347,6 +1503,7 @@ func validateAndSanitizeInitRequest(req *types.InitRequest) error {
 	if spec.Orchestration.TaskHistoryRetentionLimit == 0 {
 		spec.Orchestration.TaskHistoryRetentionLimit = defaultSpec.Orchestration.TaskHistoryRetentionLimit
 	}
+	spec.EncryptionConfig.AutoLockManagers = req.AutoLockManagers
 	return nil
 }
 
@@ -1415,8 +1572,23 @@ func initClusterSpec(node *node, spec types.Spec) error {
 			if err != nil {
 				return fmt.Errorf("error updating cluster settings: %v", err)
 			}
+			if spec.EncryptionConfig.AutoLockManagers {
+				waitForUnlockKeyChange(ctx, node, nil)
+			}
 			return nil
 		}
 	}
 	return ctx.Err()
 }
+
+// waitForUnlockKeyChange waits until the unlock key of the local node is no
+// longer prevKey, or ctx is done.
+func waitForUnlockKeyChange(ctx context.Context, node *node, prevKey []byte) {
+	for bytes.Equal(node.UnlockKey(), prevKey) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -70,6 +70,13 @@ func ServiceFromGRPC(s swarmapi.Service) types.Service {
 		service.Spec.Mode.Replicated = &types.ReplicatedService{
 			Replicas: &t.Replicated.Replicas,
 		}
+	case *swarmapi.ServiceSpec_GlobalJob:
+		service.Spec.Mode.GlobalJob = &types.GlobalJob{}
+	case *swarmapi.ServiceSpec_ReplicatedJob:
+		service.Spec.Mode.ReplicatedJob = &types.ReplicatedJob{
+			MaxConcurrent:    &t.ReplicatedJob.MaxConcurrent,
+			TotalCompletions: &t.ReplicatedJob.TotalCompletions,
+		}
 	}
 
 	// UpdateStatus
@@ -177,6 +184,20 @@ func ServiceSpecToGRPC(s types.ServiceSpec) (swarmapi.ServiceSpec, error) {
 		spec.Mode = &swarmapi.ServiceSpec_Global{
 			Global: &swarmapi.GlobalService{},
 		}
+	} else if s.Mode.GlobalJob != nil {
+		spec.Mode = &swarmapi.ServiceSpec_GlobalJob{
+			GlobalJob: &swarmapi.GlobalJob{},
+		}
+	} else if s.Mode.ReplicatedJob != nil {
+		job := &swarmapi.ReplicatedJob{MaxConcurrent: 1}
+		if s.Mode.ReplicatedJob.MaxConcurrent != nil {
+			job.MaxConcurrent = *s.Mode.ReplicatedJob.MaxConcurrent
+		}
+		job.TotalCompletions = job.MaxConcurrent
+		if s.Mode.ReplicatedJob.TotalCompletions != nil {
+			job.TotalCompletions = *s.Mode.ReplicatedJob.TotalCompletions
+		}
+		spec.Mode = &swarmapi.ServiceSpec_ReplicatedJob{ReplicatedJob: job}
 	} else if s.Mode.Replicated != nil && s.Mode.Replicated.Replicas != nil {
 		spec.Mode = &swarmapi.ServiceSpec_Replicated{
 			Replicated: &swarmapi.ReplicatedService{Replicas: *s.Mode.Replicated.Replicas},
@@ -196,8 +217,10 @@ func resourcesFromGRPC(res *swarmapi.ResourceRequirements) *types.ResourceRequir
 		resources = &types.ResourceRequirements{}
 		if res.Limits != nil {
 			resources.Limits = &types.Resources{
-				NanoCPUs:    res.Limits.NanoCPUs,
-				MemoryBytes: res.Limits.MemoryBytes,
+				NanoCPUs:           res.Limits.NanoCPUs,
+				MemoryBytes:        res.Limits.MemoryBytes,
+				NetworkIngressRate: res.Limits.NetworkIngressRate,
+				NetworkEgressRate:  res.Limits.NetworkEgressRate,
 			}
 		}
 		if res.Reservations != nil {
@@ -217,8 +240,10 @@ func resourcesToGRPC(res *types.ResourceRequirements) *swarmapi.ResourceRequirem
 		reqs = &swarmapi.ResourceRequirements{}
 		if res.Limits != nil {
 			reqs.Limits = &swarmapi.Resources{
-				NanoCPUs:    res.Limits.NanoCPUs,
-				MemoryBytes: res.Limits.MemoryBytes,
+				NanoCPUs:           res.Limits.NanoCPUs,
+				MemoryBytes:        res.Limits.MemoryBytes,
+				NetworkIngressRate: res.Limits.NetworkIngressRate,
+				NetworkEgressRate:  res.Limits.NetworkEgressRate,
 			}
 		}
 		if res.Reservations != nil {
@@ -0,0 +1,201 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	"github.com/docker/engine-api/types/events"
+	swarmagent "github.com/docker/swarmkit/agent"
+	swarmapi "github.com/docker/swarmkit/api"
+	swarmstate "github.com/docker/swarmkit/manager/state"
+	"github.com/docker/swarmkit/manager/state/store"
+	"golang.org/x/net/context"
+)
+
+// eventWatcher translates the changes made to the swarm store of a manager
+// into daemon events. Store updates only carry the new version of an object,
+// so the last observed task state and node availability are kept to report
+// the transition.
+type eventWatcher struct {
+	backend executorpkg.Backend
+	tasks   map[string]swarmapi.TaskState
+	nodes   map[string]nodeEventState
+}
+
+type nodeEventState struct {
+	availability string
+	state        string
+}
+
+// watchEvents publishes events for the services, nodes and tasks stored by
+// the manager of n until ctx is cancelled.
+func (c *Cluster) watchEvents(ctx context.Context, n *swarmagent.Node) {
+	m := n.Manager()
+	if m == nil {
+		return
+	}
+	w := &eventWatcher{
+		backend: c.config.Backend,
+		tasks:   make(map[string]swarmapi.TaskState),
+		nodes:   make(map[string]nodeEventState),
+	}
+	// The snapshot is taken in the same transaction the watch is started
+	// in, so that every change after the snapshot is seen, and only once.
+	eventq, cancel, err := store.ViewAndWatch(
+		m.RaftNode.MemoryStore(),
+		func(tx store.ReadTx) error {
+			tasks, err := store.FindTasks(tx, store.All)
+			if err != nil {
+				return err
+			}
+			for _, t := range tasks {
+				w.tasks[t.ID] = t.Status.State
+			}
+			nodes, err := store.FindNodes(tx, store.All)
+			if err != nil {
+				return err
+			}
+			for _, n := range nodes {
+				w.nodes[n.ID] = nodeState(n)
+			}
+			return nil
+		},
+		swarmstate.EventCreateService{}, swarmstate.EventUpdateService{}, swarmstate.EventDeleteService{},
+		swarmstate.EventCreateNode{}, swarmstate.EventUpdateNode{}, swarmstate.EventDeleteNode{},
+		swarmstate.EventCreateTask{}, swarmstate.EventUpdateTask{}, swarmstate.EventDeleteTask{},
+	)
+	if err != nil {
+		logrus.Errorf("Could not watch the cluster events: %v", err)
+		return
+	}
+	defer cancel()
+
+	for {
+		select {
+		case event, ok := <-eventq:
+			if !ok {
+				return
+			}
+			w.handle(event)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *eventWatcher) handle(event interface{}) {
+	switch v := event.(type) {
+	case swarmstate.EventCreateService:
+		w.logService(v.Service, "create")
+	case swarmstate.EventUpdateService:
+		w.logService(v.Service, "update")
+	case swarmstate.EventDeleteService:
+		w.logService(v.Service, "remove")
+	case swarmstate.EventCreateNode:
+		w.logNode(v.Node, "create")
+	case swarmstate.EventUpdateNode:
+		w.logNode(v.Node, "update")
+	case swarmstate.EventDeleteNode:
+		w.logNode(v.Node, "remove")
+	case swarmstate.EventCreateTask:
+		w.logTask(v.Task, "create")
+	case swarmstate.EventUpdateTask:
+		w.logTask(v.Task, "update")
+	case swarmstate.EventDeleteTask:
+		w.logTask(v.Task, "remove")
+	}
+}
+
+func (w *eventWatcher) logService(s *swarmapi.Service, action string) {
+	w.backend.LogClusterEvent(events.ServiceEventType, s.ID, action, map[string]string{
+		"name": s.Spec.Annotations.Name,
+	})
+}
+
+func (w *eventWatcher) logNode(n *swarmapi.Node, action string) {
+	name := n.Spec.Annotations.Name
+	if name == "" && n.Description != nil {
+		name = n.Description.Hostname
+	}
+	attributes := map[string]string{"name": name}
+
+	current := nodeState(n)
+	old, known := w.nodes[n.ID]
+	switch action {
+	case "remove":
+		delete(w.nodes, n.ID)
+	case "update":
+		if known && old == current {
+			// Nothing that is reported has changed, only the
+			// description or the status message.
+			return
+		}
+		fallthrough
+	default:
+		w.nodes[n.ID] = current
+	}
+
+	if known {
+		attributes["availability.old"] = old.availability
+		attributes["state.old"] = old.state
+	}
+	if action != "remove" {
+		attributes["availability.new"] = current.availability
+		attributes["state.new"] = current.state
+	}
+	w.backend.LogClusterEvent(events.NodeEventType, n.ID, action, attributes)
+}
+
+func (w *eventWatcher) logTask(t *swarmapi.Task, action string) {
+	attributes := map[string]string{
+		"name":    taskName(t),
+		"service": t.ServiceAnnotations.Name,
+	}
+	if t.NodeID != "" {
+		attributes["node"] = t.NodeID
+	}
+
+	old, known := w.tasks[t.ID]
+	switch action {
+	case "remove":
+		delete(w.tasks, t.ID)
+	case "update":
+		if known && old == t.Status.State {
+			// Only the state transitions are reported, not every
+			// status message sent by the agents.
+			return
+		}
+		fallthrough
+	default:
+		w.tasks[t.ID] = t.Status.State
+	}
+
+	if known {
+		attributes["state.old"] = strings.ToLower(old.String())
+	}
+	if action != "remove" {
+		attributes["state.new"] = strings.ToLower(t.Status.State.String())
+	}
+	w.backend.LogClusterEvent(events.TaskEventType, t.ID, action, attributes)
+}
+
+func nodeState(n *swarmapi.Node) nodeEventState {
+	return nodeEventState{
+		availability: strings.ToLower(n.Spec.Availability.String()),
+		state:        strings.ToLower(n.Status.State.String()),
+	}
+}
+
+// taskName returns the name the task is known under, following the naming of
+// the containers started for the task.
+func taskName(t *swarmapi.Task) string {
+	if t.Annotations.Name != "" {
+		return t.Annotations.Name
+	}
+	if t.Slot != 0 {
+		return fmt.Sprintf("%s.%d", t.ServiceAnnotations.Name, t.Slot)
+	}
+	return fmt.Sprintf("%s.%s", t.ServiceAnnotations.Name, t.NodeID)
+}
@@ -15,6 +15,7 @@ import (
 	enginecontainer "github.com/docker/engine-api/types/container"
 	"github.com/docker/engine-api/types/events"
 	"github.com/docker/engine-api/types/filters"
+	enginemount "github.com/docker/engine-api/types/mount"
 	"github.com/docker/engine-api/types/network"
 	"github.com/docker/swarmkit/agent/exec"
 	"github.com/docker/swarmkit/api"
@@ -184,6 +185,9 @@ func (c *containerConfig) tmpfs() map[string]string {
 func (c *containerConfig) binds() []string {
 	var r []string
 	for _, mount := range c.spec().Mounts {
+		if hasVolumeSubpath(&mount) {
+			continue
+		}
 		if mount.Type == api.MountTypeBind || (mount.Type == api.MountTypeVolume && mount.Source != "") {
 			spec := fmt.Sprintf("%s:%s", mount.Source, mount.Target)
 			mask := getMountMask(&mount)
@@ -196,6 +200,41 @@ func (c *containerConfig) binds() []string {
 	return r
 }
 
+// mounts returns the mounts of subpaths of volumes, which can't be set as
+// binds.
+func (c *containerConfig) mounts() []enginemount.Mount {
+	var r []enginemount.Mount
+	for _, mount := range c.spec().Mounts {
+		if !hasVolumeSubpath(&mount) {
+			continue
+		}
+		opts := mount.VolumeOptions
+		m := enginemount.Mount{
+			Type:     enginemount.TypeVolume,
+			Source:   mount.Source,
+			Target:   mount.Target,
+			ReadOnly: mount.ReadOnly,
+			VolumeOptions: &enginemount.VolumeOptions{
+				NoCopy:  opts.NoCopy,
+				Labels:  opts.Labels,
+				Subpath: opts.Subpath,
+			},
+		}
+		if opts.DriverConfig != nil {
+			m.VolumeOptions.DriverConfig = &enginemount.Driver{
+				Name:    opts.DriverConfig.Name,
+				Options: opts.DriverConfig.Options,
+			}
+		}
+		r = append(r, m)
+	}
+	return r
+}
+
+func hasVolumeSubpath(m *api.Mount) bool {
+	return m.Type == api.MountTypeVolume && m.VolumeOptions != nil && m.VolumeOptions.Subpath != ""
+}
+
 func getMountMask(m *api.Mount) string {
 	var maskOpts []string
 	if m.ReadOnly {
@@ -275,6 +314,7 @@ func (c *containerConfig) hostConfig() *enginecontainer.HostConfig {
 	hc := &enginecontainer.HostConfig{
 		Resources: c.resources(),
 		Binds:     c.binds(),
+		Mounts:    c.mounts(),
 		Tmpfs:     c.tmpfs(),
 	}
 
@@ -335,6 +375,14 @@ func (c *containerConfig) resources() enginecontainer.Resources {
 		resources.CPUQuota = r.Limits.NanoCPUs * resources.CPUPeriod / 1e9
 	}
 
+	if r.Limits.NetworkIngressRate > 0 {
+		resources.NetworkIngressRate = r.Limits.NetworkIngressRate
+	}
+
+	if r.Limits.NetworkEgressRate > 0 {
+		resources.NetworkEgressRate = r.Limits.NetworkEgressRate
+	}
+
 	return resources
 }
 
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/docker/docker/volume"
 	"github.com/docker/swarmkit/api"
 )
 
@@ -31,6 +32,14 @@ func validateMounts(mounts []api.Mount) error {
 			if filepath.IsAbs(mount.Source) {
 				return fmt.Errorf("invalid volume mount source, must not be an absolute path: %s", mount.Source)
 			}
+			if mount.VolumeOptions != nil && mount.VolumeOptions.Subpath != "" {
+				if mount.Source == "" {
+					return fmt.Errorf("invalid volume mount subpath, source must not be empty")
+				}
+				if err := volume.ValidateSubpath(mount.VolumeOptions.Subpath); err != nil {
+					return err
+				}
+			}
 		case api.MountTypeTmpfs:
 			if mount.Source != "" {
 				return fmt.Errorf("invalid tmpfs source, source must be empty")
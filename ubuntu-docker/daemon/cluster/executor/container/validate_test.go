@@ -84,6 +84,37 @@ func TestControllerValidateMountVolume(t *testing.T) {
 	}
 }
 
+func TestControllerValidateMountVolumeSubpath(t *testing.T) {
+	// with an anonymous volume
+	if _, err := newTestControllerWithMount(api.Mount{
+		Type:          api.MountTypeVolume,
+		Target:        testAbsPath,
+		VolumeOptions: &api.Mount_VolumeOptions{Subpath: "config"},
+	}); err == nil || !strings.Contains(err.Error(), "source must not be empty") {
+		t.Fatalf("expected error, got: %v", err)
+	}
+
+	// with a subpath out of the volume
+	if _, err := newTestControllerWithMount(api.Mount{
+		Type:          api.MountTypeVolume,
+		Source:        "foo",
+		Target:        testAbsPath,
+		VolumeOptions: &api.Mount_VolumeOptions{Subpath: "../config"},
+	}); err == nil || !strings.Contains(err.Error(), "invalid volume subpath") {
+		t.Fatalf("expected error, got: %v", err)
+	}
+
+	// with a proper subpath
+	if _, err := newTestControllerWithMount(api.Mount{
+		Type:          api.MountTypeVolume,
+		Source:        "foo",
+		Target:        testAbsPath,
+		VolumeOptions: &api.Mount_VolumeOptions{Subpath: "config"},
+	}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
 func TestControllerValidateMountTarget(t *testing.T) {
 	tmpdir, err := ioutil.TempDir("", "TestControllerValidateMountTarget")
 	if err != nil {
@@ -3,6 +3,7 @@ package cluster
 import (
 	"fmt"
 
+	types "github.com/docker/engine-api/types/swarm"
 	swarmapi "github.com/docker/swarmkit/api"
 	"golang.org/x/net/context"
 )
@@ -78,6 +79,70 @@ func getService(ctx context.Context, c swarmapi.ControlClient, input string) (*s
 	return rg.Service, nil
 }
 
+// getServiceStatuses summarizes the tasks of services, indexed by service ID.
+func getServiceStatuses(ctx context.Context, c swarmapi.ControlClient, services []*swarmapi.Service) (map[string]*types.ServiceStatus, error) {
+	statuses := make(map[string]*types.ServiceStatus)
+	if len(services) == 0 {
+		return statuses, nil
+	}
+
+	var serviceIDs []string
+	for _, s := range services {
+		serviceIDs = append(serviceIDs, s.ID)
+		status := &types.ServiceStatus{}
+		switch m := s.Spec.GetMode().(type) {
+		case *swarmapi.ServiceSpec_Replicated:
+			status.DesiredTasks = m.Replicated.Replicas
+		case *swarmapi.ServiceSpec_ReplicatedJob:
+			status.DesiredTasks = m.ReplicatedJob.TotalCompletions
+		}
+		statuses[s.ID] = status
+	}
+
+	r, err := c.ListTasks(ctx, &swarmapi.ListTasksRequest{Filters: &swarmapi.ListTasksRequest_Filters{ServiceIDs: serviceIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	modes := make(map[string]interface{})
+	for _, s := range services {
+		modes[s.ID] = s.Spec.GetMode()
+	}
+	jobNodes := make(map[string]map[string]struct{})
+	for _, t := range r.Tasks {
+		status, ok := statuses[t.ServiceID]
+		if !ok {
+			continue
+		}
+		active := t.DesiredState <= swarmapi.TaskStateRunning
+		if active && t.Status.State == swarmapi.TaskStateRunning {
+			status.RunningTasks++
+		}
+		if t.Status.State == swarmapi.TaskStateCompleted {
+			status.CompletedTasks++
+		}
+		switch modes[t.ServiceID].(type) {
+		case *swarmapi.ServiceSpec_Global:
+			if active {
+				status.DesiredTasks++
+			}
+		case *swarmapi.ServiceSpec_GlobalJob:
+			// A global job must complete once on every node it was
+			// started on.
+			if active || t.Status.State == swarmapi.TaskStateCompleted {
+				if jobNodes[t.ServiceID] == nil {
+					jobNodes[t.ServiceID] = make(map[string]struct{})
+				}
+				jobNodes[t.ServiceID][t.NodeID] = struct{}{}
+			}
+		}
+	}
+	for id, nodes := range jobNodes {
+		statuses[id].DesiredTasks = uint64(len(nodes))
+	}
+	return statuses, nil
+}
+
 func getTask(ctx context.Context, c swarmapi.ControlClient, input string) (*swarmapi.Task, error) {
 	// GetTask to match via full ID.
 	rg, err := c.GetTask(ctx, &swarmapi.GetTaskRequest{TaskID: input})
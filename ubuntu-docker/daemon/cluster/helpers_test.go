@@ -0,0 +1,105 @@
+package cluster
+
+import (
+	"testing"
+
+	types "github.com/docker/engine-api/types/swarm"
+	swarmapi "github.com/docker/swarmkit/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// taskListClient is a control client which only lists tasks.
+type taskListClient struct {
+	swarmapi.ControlClient
+	tasks []*swarmapi.Task
+}
+
+func (c *taskListClient) ListTasks(ctx context.Context, in *swarmapi.ListTasksRequest, opts ...grpc.CallOption) (*swarmapi.ListTasksResponse, error) {
+	return &swarmapi.ListTasksResponse{Tasks: c.tasks}, nil
+}
+
+func statusTask(serviceID, nodeID string, desired, observed swarmapi.TaskState) *swarmapi.Task {
+	return &swarmapi.Task{
+		ServiceID:    serviceID,
+		NodeID:       nodeID,
+		DesiredState: desired,
+		Status: swarmapi.TaskStatus{
+			State: observed,
+		},
+	}
+}
+
+func TestGetServiceStatusesJobs(t *testing.T) {
+	services := []*swarmapi.Service{
+		{
+			ID: "replicatedjob",
+			Spec: swarmapi.ServiceSpec{
+				Mode: &swarmapi.ServiceSpec_ReplicatedJob{
+					ReplicatedJob: &swarmapi.ReplicatedJob{
+						MaxConcurrent:    2,
+						TotalCompletions: 5,
+					},
+				},
+			},
+		},
+		{
+			ID: "globaljob",
+			Spec: swarmapi.ServiceSpec{
+				Mode: &swarmapi.ServiceSpec_GlobalJob{
+					GlobalJob: &swarmapi.GlobalJob{},
+				},
+			},
+		},
+		{
+			ID: "global",
+			Spec: swarmapi.ServiceSpec{
+				Mode: &swarmapi.ServiceSpec_Global{
+					Global: &swarmapi.GlobalService{},
+				},
+			},
+		},
+	}
+	c := &taskListClient{
+		tasks: []*swarmapi.Task{
+			// Two completed slots, a failed task replaced by a running
+			// one, and a task still starting.
+			statusTask("replicatedjob", "node1", swarmapi.TaskStateShutdown, swarmapi.TaskStateCompleted),
+			statusTask("replicatedjob", "node2", swarmapi.TaskStateShutdown, swarmapi.TaskStateCompleted),
+			statusTask("replicatedjob", "node1", swarmapi.TaskStateShutdown, swarmapi.TaskStateFailed),
+			statusTask("replicatedjob", "node1", swarmapi.TaskStateRunning, swarmapi.TaskStateRunning),
+			statusTask("replicatedjob", "node2", swarmapi.TaskStateRunning, swarmapi.TaskStatePreparing),
+
+			// Completed on node1, running on node2, and failed for good
+			// on node3.
+			statusTask("globaljob", "node1", swarmapi.TaskStateShutdown, swarmapi.TaskStateCompleted),
+			statusTask("globaljob", "node2", swarmapi.TaskStateShutdown, swarmapi.TaskStateFailed),
+			statusTask("globaljob", "node2", swarmapi.TaskStateRunning, swarmapi.TaskStateRunning),
+			statusTask("globaljob", "node3", swarmapi.TaskStateShutdown, swarmapi.TaskStateFailed),
+
+			statusTask("global", "node1", swarmapi.TaskStateRunning, swarmapi.TaskStateRunning),
+			statusTask("global", "node2", swarmapi.TaskStateRunning, swarmapi.TaskStateStarting),
+			statusTask("global", "node3", swarmapi.TaskStateShutdown, swarmapi.TaskStateShutdown),
+		},
+	}
+
+	statuses, err := getServiceStatuses(context.Background(), c, services)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]types.ServiceStatus{
+		"replicatedjob": {RunningTasks: 1, DesiredTasks: 5, CompletedTasks: 2},
+		"globaljob":     {RunningTasks: 1, DesiredTasks: 2, CompletedTasks: 1},
+		"global":        {RunningTasks: 1, DesiredTasks: 2},
+	}
+	for id, want := range expected {
+		got, ok := statuses[id]
+		if !ok {
+			t.Fatalf("no status for service %s", id)
+		}
+		if *got != want {
+			t.Fatalf("expected status %+v for service %s, got %+v", want, id, *got)
+		}
+	}
+}
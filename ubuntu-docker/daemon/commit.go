@@ -128,6 +128,9 @@ func (daemon *Daemon) Commit(name string, c *backend.ContainerCommitConfig) (str
 	if runtime.GOOS == "windows" && container.IsRunning() {
 		return "", fmt.Errorf("Windows does not support commit of a running container")
 	}
+	if runtime.GOOS == "windows" && c.Squash {
+		return "", fmt.Errorf("squashing images is not supported on Windows")
+	}
 
 	if c.Pause && !container.IsPaused() {
 		daemon.containerPause(container)
@@ -224,6 +227,19 @@ func (daemon *Daemon) Commit(name string, c *backend.ContainerCommitConfig) (str
 		}
 	}
 
+	if c.Squash {
+		squashedID, err := daemon.SquashImage(id.String(), "")
+		if err != nil {
+			return "", err
+		}
+		// The image committed before squashing is only an intermediate
+		// step, it is neither tagged nor kept.
+		if _, err := daemon.imageStore.Delete(id); err != nil {
+			return "", err
+		}
+		id = image.ID(squashedID)
+	}
+
 	if c.Repo != "" {
 		newTag, err := reference.WithName(c.Repo) // todo: should move this to API layer
 		if err != nil {
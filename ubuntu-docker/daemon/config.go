@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/opts"
@@ -26,6 +27,10 @@ const (
 	// maximum number of uploads that
 	// may take place at a time for each push.
 	defaultMaxConcurrentUploads = 5
+	// defaultMaxConcurrentDecompressions is the default value for
+	// maximum number of layers that may be decompressed while
+	// their parent layer is being registered.
+	defaultMaxConcurrentDecompressions = 1
 	// stockRuntimeName is the reserved name/alias used to represent the
 	// OCI runtime being shipped with the docker daemon package.
 	stockRuntimeName = "runc"
@@ -41,9 +46,11 @@ const (
 // Use this to differentiate these options
 // with others like the ones in CommonTLSOptions.
 var flatOptions = map[string]bool{
-	"cluster-store-opts": true,
-	"log-opts":           true,
-	"runtimes":           true,
+	"cluster-store-opts":   true,
+	"credential-helpers":   true,
+	"log-opts":             true,
+	"registry-mirrors-map": true,
+	"runtimes":             true,
 }
 
 // LogConfig represents the default log configuration.
@@ -118,6 +125,28 @@ type CommonConfig struct {
 	// may take place at a time for each push.
 	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
 
+	// MaxConcurrentDecompressions is the maximum number of layers that
+	// may be decompressed while their parent layer is being registered.
+	MaxConcurrentDecompressions *int `json:"max-concurrent-decompressions,omitempty"`
+
+	// TrustPolicy is the path of the file holding the image signature
+	// policy enforced on pulls and container creation.
+	TrustPolicy string `json:"trust-policy,omitempty"`
+
+	// ImageGCHighThreshold is the usage percentage of the filesystem of the
+	// graph driver above which the unused images are removed. Zero disables
+	// the removals driven by the filesystem usage.
+	ImageGCHighThreshold int `json:"image-gc-high-threshold,omitempty"`
+
+	// ImageGCLowThreshold is the usage percentage of the filesystem of the
+	// graph driver the unused images are removed down to. Zero means the
+	// high threshold.
+	ImageGCLowThreshold int `json:"image-gc-low-threshold,omitempty"`
+
+	// ImageGCMaxAge is the duration after which the unused images are
+	// removed, whatever the filesystem usage. Empty disables it.
+	ImageGCMaxAge string `json:"image-gc-max-age,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -147,7 +176,7 @@ type CommonConfig struct {
 // Subsequent calls to `flag.Parse` will populate config with values parsed
 // from the command-line.
 func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string) string) {
-	var maxConcurrentDownloads, maxConcurrentUploads int
+	var maxConcurrentDownloads, maxConcurrentUploads, maxConcurrentDecompressions int
 
 	config.ServiceOptions.InstallCliFlags(cmd, usageFn)
 
@@ -173,11 +202,18 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.CorsHeaders, []string{"-api-cors-header"}, "", usageFn("Set CORS headers in the remote API"))
 	cmd.IntVar(&maxConcurrentDownloads, []string{"-max-concurrent-downloads"}, defaultMaxConcurrentDownloads, usageFn("Set the max concurrent downloads for each pull"))
 	cmd.IntVar(&maxConcurrentUploads, []string{"-max-concurrent-uploads"}, defaultMaxConcurrentUploads, usageFn("Set the max concurrent uploads for each push"))
+	cmd.IntVar(&maxConcurrentDecompressions, []string{"-max-concurrent-decompressions"}, defaultMaxConcurrentDecompressions, usageFn("Set the max layers decompressed ahead of their registration"))
+
+	cmd.StringVar(&config.TrustPolicy, []string{"-trust-policy"}, "", usageFn("Image signature policy file"))
+	cmd.IntVar(&config.ImageGCHighThreshold, []string{"-image-gc-high-threshold"}, 0, usageFn("Disk usage percent above which unused images are removed"))
+	cmd.IntVar(&config.ImageGCLowThreshold, []string{"-image-gc-low-threshold"}, 0, usageFn("Disk usage percent unused images are removed down to"))
+	cmd.StringVar(&config.ImageGCMaxAge, []string{"-image-gc-max-age"}, "", usageFn("Remove the images unused for this duration"))
 
 	cmd.StringVar(&config.SwarmDefaultAdvertiseAddr, []string{"-swarm-default-advertise-addr"}, "", usageFn("Set default address or interface for swarm advertised address"))
 
 	config.MaxConcurrentDownloads = &maxConcurrentDownloads
 	config.MaxConcurrentUploads = &maxConcurrentUploads
+	config.MaxConcurrentDecompressions = &maxConcurrentDecompressions
 }
 
 // IsValueSet returns true if a configuration value
@@ -400,9 +436,26 @@ func findConfigurationConflicts(config map[string]interface{}, flags *flag.FlagS
 	return nil
 }
 
+// GetImageGCPolicy returns the thresholds and the maximum age of the image
+// garbage collection policy. A zero maxAge means no maximum age.
+func (config *Config) GetImageGCPolicy() (high, low int, maxAge time.Duration) {
+	config.reloadLock.Lock()
+	defer config.reloadLock.Unlock()
+
+	high, low = config.ImageGCHighThreshold, config.ImageGCLowThreshold
+	if low == 0 {
+		low = high
+	}
+	if config.ImageGCMaxAge != "" {
+		maxAge, _ = time.ParseDuration(config.ImageGCMaxAge)
+	}
+	return high, low, maxAge
+}
+
 // ValidateConfiguration validates some specific configs.
 // such as config.DNS, config.Labels, config.DNSSearch,
-// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads.
+// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads
+// and config.MaxConcurrentDecompressions.
 func ValidateConfiguration(config *Config) error {
 	// validate DNS
 	for _, dns := range config.DNS {
@@ -425,6 +478,16 @@ func ValidateConfiguration(config *Config) error {
 		}
 	}
 
+	// validate the mirrors of the registries
+	if err := registry.ValidateMirrorsMap(config.MirrorsMap); err != nil {
+		return err
+	}
+
+	// validate the credential helpers of the registries
+	if err := registry.ValidateCredentialHelpers(config.CredentialHelpers); err != nil {
+		return err
+	}
+
 	// validate MaxConcurrentDownloads
 	if config.IsValueSet("max-concurrent-downloads") && config.MaxConcurrentDownloads != nil && *config.MaxConcurrentDownloads < 0 {
 		return fmt.Errorf("invalid max concurrent downloads: %d", *config.MaxConcurrentDownloads)
@@ -435,6 +498,27 @@ func ValidateConfiguration(config *Config) error {
 		return fmt.Errorf("invalid max concurrent uploads: %d", *config.MaxConcurrentUploads)
 	}
 
+	// validate MaxConcurrentDecompressions
+	if config.IsValueSet("max-concurrent-decompressions") && config.MaxConcurrentDecompressions != nil && *config.MaxConcurrentDecompressions < 0 {
+		return fmt.Errorf("invalid max concurrent decompressions: %d", *config.MaxConcurrentDecompressions)
+	}
+
+	// validate the image garbage collection policy
+	if config.ImageGCHighThreshold < 0 || config.ImageGCHighThreshold > 100 {
+		return fmt.Errorf("invalid image gc high threshold: %d", config.ImageGCHighThreshold)
+	}
+	if config.ImageGCLowThreshold < 0 || config.ImageGCLowThreshold > 100 {
+		return fmt.Errorf("invalid image gc low threshold: %d", config.ImageGCLowThreshold)
+	}
+	if config.ImageGCHighThreshold > 0 && config.ImageGCLowThreshold > config.ImageGCHighThreshold {
+		return fmt.Errorf("image gc low threshold %d is above the high threshold %d", config.ImageGCLowThreshold, config.ImageGCHighThreshold)
+	}
+	if config.ImageGCMaxAge != "" {
+		if d, err := time.ParseDuration(config.ImageGCMaxAge); err != nil || d <= 0 {
+			return fmt.Errorf("invalid image gc max age: %s", config.ImageGCMaxAge)
+		}
+	}
+
 	// validate that "default" runtime is not reset
 	if runtimes := config.GetAllRuntimes(); len(runtimes) > 0 {
 		if _, ok := runtimes[stockRuntimeName]; ok {
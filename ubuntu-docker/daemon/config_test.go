@@ -275,4 +275,40 @@ func TestValidateConfiguration(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
+
+	c7 := &Config{
+		CommonConfig: CommonConfig{
+			ImageGCHighThreshold: 85,
+			ImageGCLowThreshold:  80,
+			ImageGCMaxAge:        "168h",
+		},
+	}
+
+	err = ValidateConfiguration(c7)
+	if err != nil {
+		t.Fatalf("expected no error, got error %v", err)
+	}
+
+	c8 := &Config{
+		CommonConfig: CommonConfig{
+			ImageGCHighThreshold: 80,
+			ImageGCLowThreshold:  85,
+		},
+	}
+
+	err = ValidateConfiguration(c8)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	c9 := &Config{
+		CommonConfig: CommonConfig{
+			ImageGCMaxAge: "7d",
+		},
+	}
+
+	err = ValidateConfiguration(c9)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
 }
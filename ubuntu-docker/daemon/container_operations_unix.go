@@ -116,6 +116,16 @@ func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName
 		if err := daemon.connectToNetwork(container, idOrName, endpointConfig, true); err != nil {
 			return err
 		}
+		if container.HostConfig.NetworkIngressRate > 0 || container.HostConfig.NetworkEgressRate > 0 {
+			if err := daemon.setNetworkRates(container); err != nil {
+				if n, e := daemon.FindNetwork(idOrName); e == nil {
+					if e := disconnectFromNetwork(container, n, false); e != nil {
+						logrus.Warnf("Could not rollback container connection to network %s", idOrName)
+					}
+				}
+				return err
+			}
+		}
 	}
 	if err := container.ToDiskLocking(); err != nil {
 		return fmt.Errorf("Error saving container to disk: %v", err)
@@ -3,6 +3,7 @@ package daemon
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
@@ -74,7 +75,15 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig, managed bool) (
 		if err != nil {
 			return nil, err
 		}
+		if err := daemon.verifyImageTrust(params.Config.Image, img); err != nil {
+			return nil, err
+		}
 		imgID = img.ID()
+		// The last use orders the images removed by the image garbage
+		// collector.
+		if err := daemon.imageStore.SetLastUsed(imgID, time.Now()); err != nil {
+			logrus.Warnf("failed to record last use of image %s: %v", imgID, err)
+		}
 	}
 
 	if err := daemon.mergeAndVerifyConfig(params.Config, img); err != nil {
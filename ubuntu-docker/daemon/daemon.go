@@ -26,11 +26,13 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/events"
 	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/daemon/trust"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
 	"github.com/docker/libnetwork/cluster"
 	// register graph drivers
 	_ "github.com/docker/docker/daemon/graphdriver/register"
+	"github.com/docker/docker/distribution"
 	dmetadata "github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
@@ -51,6 +53,7 @@ import (
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/docker/utils"
+	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
 	"github.com/docker/docker/volume/store"
@@ -67,6 +70,10 @@ var (
 	errSystemNotSupported = fmt.Errorf("The Docker daemon is not supported on this platform.")
 )
 
+// layerDownloadMaxAge is how long partial layer downloads are kept to be
+// resumed by a later pull.
+const layerDownloadMaxAge = 24 * time.Hour
+
 // Daemon holds information about the Docker daemon.
 type Daemon struct {
 	ID                        string
@@ -77,6 +84,7 @@ type Daemon struct {
 	downloadManager           *xfer.LayerDownloadManager
 	uploadManager             *xfer.LayerUploadManager
 	distributionMetadataStore dmetadata.Store
+	layerDownloadDir          string
 	trustKey                  libtrust.PrivateKey
 	idIndex                   *truncindex.TruncIndex
 	configStore               *Config
@@ -86,6 +94,7 @@ type Daemon struct {
 	EventsService             *events.Events
 	netController             libnetwork.NetworkController
 	volumes                   *store.VolumeStore
+	volumeSizes               volumeSizeCache
 	discoveryWatcher          discoveryReloader
 	root                      string
 	seccompEnabled            bool
@@ -93,6 +102,7 @@ type Daemon struct {
 	uidMaps                   []idtools.IDMap
 	gidMaps                   []idtools.IDMap
 	layerStore                layer.Store
+	graphDriverHome           string
 	imageStore                image.Store
 	nameIndex                 *registrar.Registrar
 	linkIndex                 *linkIndex
@@ -100,6 +110,8 @@ type Daemon struct {
 	containerdRemote          libcontainerd.Remote
 	defaultIsolation          containertypes.Isolation // Default isolation mode on Windows
 	clusterProvider           cluster.Provider
+	trustVerifier             *trust.Verifier
+	imageGCStop               chan struct{}
 }
 
 func (daemon *Daemon) restore() error {
@@ -174,6 +186,15 @@ func (daemon *Daemon) restore() error {
 		go func(c *container.Container) {
 			defer wg.Done()
 			rm := c.RestartManager(false)
+			if !c.IsRunning() && !c.IsPaused() {
+				// A daemon crash leaves the volume subpaths mounted for
+				// the container behind.
+				if dir, err := c.VolumeSubpathDir(); err == nil {
+					if err := volume.UnmountSubpaths(dir); err != nil {
+						logrus.Errorf("Failed to unmount the volume subpaths of %s: %v", c.ID, err)
+					}
+				}
+			}
 			if c.IsRunning() || c.IsPaused() {
 				if err := daemon.containerd.Restore(c.ID, c.InitializeStdio, libcontainerd.WithRestartManager(rm)); err != nil {
 					logrus.Errorf("Failed to restore %s with containerd: %s", c.ID, err)
@@ -305,6 +326,19 @@ func (daemon *Daemon) restore() error {
 
 	group.Wait()
 
+	// The volume store counts the active mounts of the volumes in memory,
+	// count those of the containers which kept running.
+	for _, c := range containers {
+		if !c.IsRunning() && !c.IsPaused() {
+			continue
+		}
+		for _, m := range c.MountPoints {
+			if m.Volume != nil {
+				daemon.volumes.RestoreMount(m.Volume)
+			}
+		}
+	}
+
 	if !debug {
 		if logrus.GetLevel() == logrus.InfoLevel {
 			fmt.Println()
@@ -496,6 +530,9 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	}
 
 	graphDriver := d.layerStore.DriverName()
+	// The layer store initializes the graph driver in a directory named
+	// after it, in its store path.
+	d.graphDriverHome = filepath.Join(config.Root, graphDriver)
 	imageRoot := filepath.Join(config.Root, "image", graphDriver)
 
 	// Configure and validate the kernels security support
@@ -505,6 +542,8 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 
 	logrus.Debugf("Max Concurrent Downloads: %d", *config.MaxConcurrentDownloads)
 	d.downloadManager = xfer.NewLayerDownloadManager(d.layerStore, *config.MaxConcurrentDownloads)
+	logrus.Debugf("Max Concurrent Decompressions: %d", *config.MaxConcurrentDecompressions)
+	d.downloadManager.SetDecompressionConcurrency(*config.MaxConcurrentDecompressions)
 	logrus.Debugf("Max Concurrent Uploads: %d", *config.MaxConcurrentUploads)
 	d.uploadManager = xfer.NewLayerUploadManager(*config.MaxConcurrentUploads)
 
@@ -540,6 +579,11 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 		return nil, err
 	}
 
+	d.layerDownloadDir = filepath.Join(imageRoot, "layer-downloads")
+	if err := distribution.CleanupLayerDownloads(d.layerDownloadDir, layerDownloadMaxAge); err != nil {
+		logrus.Warnf("Failed to remove stale layer downloads: %v", err)
+	}
+
 	eventsService := events.New()
 
 	referenceStore, err := reference.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
@@ -547,6 +591,15 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 		return nil, fmt.Errorf("Couldn't create Tag store repositories: %s", err)
 	}
 
+	d.trustVerifier = trust.NewVerifier(filepath.Join(trustDir, "notary"), registryService, ifs)
+	if config.TrustPolicy != "" {
+		policy, err := trust.LoadPolicy(config.TrustPolicy)
+		if err != nil {
+			return nil, err
+		}
+		d.trustVerifier.SetPolicy(policy)
+	}
+
 	if err := restoreCustomImage(d.imageStore, d.layerStore, referenceStore); err != nil {
 		return nil, fmt.Errorf("Couldn't restore custom images: %s", err)
 	}
@@ -606,6 +659,9 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 		return nil, err
 	}
 
+	d.imageGCStop = make(chan struct{})
+	go d.imageGC(d.imageGCStop)
+
 	// Plugin system initialization should happen before restore. Do not change order.
 	if err := pluginInit(d, config, containerdRemote); err != nil {
 		return nil, err
@@ -656,6 +712,9 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 // Shutdown stops the daemon.
 func (daemon *Daemon) Shutdown() error {
 	daemon.shutdown = true
+	if daemon.imageGCStop != nil {
+		close(daemon.imageGCStop)
+	}
 	// Keep mounts and networking running on daemon shutdown if
 	// we are to keep containers running and restore them.
 
@@ -952,6 +1011,39 @@ func (daemon *Daemon) Reload(config *Config) error {
 		daemon.uploadManager.SetConcurrency(*daemon.configStore.MaxConcurrentUploads)
 	}
 
+	// If no value is set for max-concurrent-decompressions we assume it is the default value
+	// We always "reset" as the cost is lightweight and easy to maintain.
+	if config.IsValueSet("max-concurrent-decompressions") && config.MaxConcurrentDecompressions != nil {
+		*daemon.configStore.MaxConcurrentDecompressions = *config.MaxConcurrentDecompressions
+	} else {
+		maxConcurrentDecompressions := defaultMaxConcurrentDecompressions
+		daemon.configStore.MaxConcurrentDecompressions = &maxConcurrentDecompressions
+	}
+	logrus.Debugf("Reset Max Concurrent Decompressions: %d", *daemon.configStore.MaxConcurrentDecompressions)
+	if daemon.downloadManager != nil {
+		daemon.downloadManager.SetDecompressionConcurrency(*daemon.configStore.MaxConcurrentDecompressions)
+	}
+
+	// The policy file is read again even if its path didn't change, so
+	// that editing it and reloading the daemon applies the new rules.
+	if config.IsValueSet("trust-policy") {
+		daemon.configStore.TrustPolicy = config.TrustPolicy
+	}
+	if err = daemon.reloadTrustPolicy(); err != nil {
+		return err
+	}
+
+	// The image garbage collector reads its policy on each run.
+	if config.IsValueSet("image-gc-high-threshold") {
+		daemon.configStore.ImageGCHighThreshold = config.ImageGCHighThreshold
+	}
+	if config.IsValueSet("image-gc-low-threshold") {
+		daemon.configStore.ImageGCLowThreshold = config.ImageGCLowThreshold
+	}
+	if config.IsValueSet("image-gc-max-age") {
+		daemon.configStore.ImageGCMaxAge = config.ImageGCMaxAge
+	}
+
 	// We emit daemon reload event here with updatable configurations
 	attributes["debug"] = fmt.Sprintf("%t", daemon.configStore.Debug)
 	attributes["cluster-store"] = daemon.configStore.ClusterStore
@@ -962,6 +1054,10 @@ func (daemon *Daemon) Reload(config *Config) error {
 		attributes["cluster-store-opts"] = "{}"
 	}
 	attributes["cluster-advertise"] = daemon.configStore.ClusterAdvertise
+	attributes["trust-policy"] = daemon.configStore.TrustPolicy
+	attributes["image-gc-high-threshold"] = fmt.Sprintf("%d", daemon.configStore.ImageGCHighThreshold)
+	attributes["image-gc-low-threshold"] = fmt.Sprintf("%d", daemon.configStore.ImageGCLowThreshold)
+	attributes["image-gc-max-age"] = daemon.configStore.ImageGCMaxAge
 	if daemon.configStore.Labels != nil {
 		labels, _ := json.Marshal(daemon.configStore.Labels)
 		attributes["labels"] = string(labels)
@@ -970,6 +1066,7 @@ func (daemon *Daemon) Reload(config *Config) error {
 	}
 	attributes["max-concurrent-downloads"] = fmt.Sprintf("%d", *daemon.configStore.MaxConcurrentDownloads)
 	attributes["max-concurrent-uploads"] = fmt.Sprintf("%d", *daemon.configStore.MaxConcurrentUploads)
+	attributes["max-concurrent-decompressions"] = fmt.Sprintf("%d", *daemon.configStore.MaxConcurrentDecompressions)
 
 	return nil
 }
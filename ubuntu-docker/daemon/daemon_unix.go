@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
@@ -50,6 +51,9 @@ const (
 	platformSupported = true
 	// It's not kernel limit, we want this 4M limit to supply a reasonable functional container
 	linuxMinMemory = 4194304
+	// The network rates are applied with tbf, whose rate is a 32-bit number of bytes per second
+	linuxMinNetworkRate = 1024
+	linuxMaxNetworkRate = math.MaxUint32
 	// constants for remapped root settings
 	defaultIDSpecifier string = "default"
 	defaultRemappedID  string = "dockremap"
@@ -427,9 +431,35 @@ func verifyContainerResources(resources *containertypes.Resources, sysInfo *sysi
 		resources.BlkioDeviceWriteIOps = []*pblkiodev.ThrottleDevice{}
 	}
 
+	// network rate checks
+	if err := verifyNetworkRate("ingress", resources.NetworkIngressRate, update); err != nil {
+		return warnings, err
+	}
+	if err := verifyNetworkRate("egress", resources.NetworkEgressRate, update); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
+// verifyNetworkRate validates a network rate of a container. An update can
+// remove the rate with a value of -1.
+func verifyNetworkRate(direction string, rate int64, update bool) error {
+	if rate == 0 || (update && rate == -1) {
+		return nil
+	}
+	if rate < 0 {
+		return fmt.Errorf("Invalid value %d, network %s rate must be positive", rate, direction)
+	}
+	if rate < linuxMinNetworkRate {
+		return fmt.Errorf("Minimum network %s rate allowed is 1KB per second", direction)
+	}
+	if rate > linuxMaxNetworkRate {
+		return fmt.Errorf("Maximum network %s rate allowed is 4GB per second", direction)
+	}
+	return nil
+}
+
 func (daemon *Daemon) getCgroupDriver() string {
 	cgroupDriver := cgroupFsDriver
 
@@ -486,6 +516,11 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		return warnings, fmt.Errorf("SHM size must be greater than 0")
 	}
 
+	if !update && (hostConfig.NetworkIngressRate != 0 || hostConfig.NetworkEgressRate != 0) &&
+		(hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsContainer()) {
+		return warnings, fmt.Errorf("Network rates cannot be set on a container sharing the network namespace of the host or of another container")
+	}
+
 	if hostConfig.OomScoreAdj < -1000 || hostConfig.OomScoreAdj > 1000 {
 		return warnings, fmt.Errorf("Invalid value %d, range for oom score adj is [-1000, 1000]", hostConfig.OomScoreAdj)
 	}
@@ -564,6 +599,12 @@ func verifyDaemonSettings(config *Config) error {
 	if !config.bridgeConfig.EnableIPTables && config.bridgeConfig.EnableIPMasq {
 		config.bridgeConfig.EnableIPMasq = false
 	}
+	if config.bridgeConfig.EnableIP6Tables && !config.bridgeConfig.EnableIPTables {
+		return fmt.Errorf("You specified --iptables=false with --ip6tables. The ip6tables rules mirror the iptables ones. Please set --iptables to true")
+	}
+	if config.bridgeConfig.EnableIP6Tables && !config.bridgeConfig.EnableIPv6 {
+		return fmt.Errorf("You specified --ip6tables without --ipv6. Please set --ipv6 to true")
+	}
 	if err := VerifyCgroupDriver(config); err != nil {
 		return err
 	}
@@ -674,6 +715,7 @@ func driverOptions(config *Config) []nwconfig.Option {
 	bridgeConfig := options.Generic{
 		"EnableIPForwarding":  config.bridgeConfig.EnableIPForward,
 		"EnableIPTables":      config.bridgeConfig.EnableIPTables,
+		"EnableIP6Tables":     config.bridgeConfig.EnableIP6Tables,
 		"EnableUserlandProxy": config.bridgeConfig.EnableUserlandProxy}
 	bridgeOption := options.Generic{netlabel.GenericData: bridgeConfig}
 
@@ -197,3 +197,23 @@ func TestNetworkOptions(t *testing.T) {
 		t.Fatalf("Expected networkOptions error, got nil")
 	}
 }
+
+func TestVerifyDaemonSettingsIP6Tables(t *testing.T) {
+	config := &Config{}
+	config.bridgeConfig.EnableIPTables = true
+	config.bridgeConfig.EnableIP6Tables = true
+	if err := verifyDaemonSettings(config); err == nil {
+		t.Fatal("Expected verifyDaemonSettings error with --ip6tables and without --ipv6, got nil")
+	}
+
+	config.bridgeConfig.EnableIPTables = false
+	config.bridgeConfig.EnableIPv6 = true
+	if err := verifyDaemonSettings(config); err == nil {
+		t.Fatal("Expected verifyDaemonSettings error with --ip6tables and --iptables=false, got nil")
+	}
+
+	config.bridgeConfig.EnableIPTables = true
+	if err := verifyDaemonSettings(config); err != nil {
+		t.Fatalf("Expected verifyDaemonSettings success, got error: %v", err)
+	}
+}
@@ -139,6 +139,12 @@ func verifyContainerResources(resources *containertypes.Resources, sysInfo *sysi
 		logrus.Warn("Windows does not support Block I/O write limit in IO per second. --device-write-iops discarded.")
 		resources.BlkioDeviceWriteIOps = []*pblkiodev.ThrottleDevice{}
 	}
+	if resources.NetworkIngressRate != 0 || resources.NetworkEgressRate != 0 {
+		warnings = append(warnings, "Windows does not support network rates.")
+		logrus.Warn("Windows does not support network rates. --network-ingress-rate and --network-egress-rate discarded.")
+		resources.NetworkIngressRate = 0
+		resources.NetworkEgressRate = 0
+	}
 	return warnings, nil
 }
 
@@ -152,6 +152,7 @@ func (daemon *Daemon) VolumeRm(name string) error {
 		}
 		return fmt.Errorf("Error while removing volume %s: %v", name, err)
 	}
+	daemon.volumeSizes.forget(v.Name())
 	daemon.LogVolumeEvent(v.Name(), "destroy", map[string]string{"driver": v.DriverName()})
 	return nil
 }
@@ -109,6 +109,16 @@ func (daemon *Daemon) LogDaemonEventWithAttributes(action string, attributes map
 	}
 }
 
+// LogClusterEvent generates an event related to a swarm object, like a
+// service, a node or a task.
+func (daemon *Daemon) LogClusterEvent(eventType, objectID, action string, attributes map[string]string) {
+	actor := events.Actor{
+		ID:         objectID,
+		Attributes: attributes,
+	}
+	daemon.EventsService.Log(action, eventType, actor)
+}
+
 // SubscribeToEvents returns the currently record of events, a channel to stream new events from, and a function to cancel the stream of events.
 func (daemon *Daemon) SubscribeToEvents(since, until time.Time, filter filters.Args) ([]events.Message, chan interface{}) {
 	ef := daemonevents.NewFilter(filter)
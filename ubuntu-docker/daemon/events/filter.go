@@ -26,6 +26,9 @@ func (ef *Filter) Include(ev events.Message) bool {
 		ef.matchVolume(ev) &&
 		ef.matchNetwork(ev) &&
 		ef.matchImage(ev) &&
+		ef.matchService(ev) &&
+		ef.matchNode(ev) &&
+		ef.matchTask(ev) &&
 		ef.matchLabels(ev.Actor.Attributes)
 }
 
@@ -56,6 +59,24 @@ func (ef *Filter) matchNetwork(ev events.Message) bool {
 	return ef.fuzzyMatchName(ev, events.NetworkEventType)
 }
 
+// matchService matches against both the service events and the events of
+// the tasks belonging to the service.
+func (ef *Filter) matchService(ev events.Message) bool {
+	return ef.fuzzyMatchName(ev, events.ServiceEventType) ||
+		ev.Type == events.TaskEventType && ef.filter.FuzzyMatch(events.ServiceEventType, ev.Actor.Attributes["service"])
+}
+
+// matchNode matches against both the node events and the events of the
+// tasks assigned to the node.
+func (ef *Filter) matchNode(ev events.Message) bool {
+	return ef.fuzzyMatchName(ev, events.NodeEventType) ||
+		ev.Type == events.TaskEventType && ef.filter.FuzzyMatch(events.NodeEventType, ev.Actor.Attributes["node"])
+}
+
+func (ef *Filter) matchTask(ev events.Message) bool {
+	return ef.fuzzyMatchName(ev, events.TaskEventType)
+}
+
 func (ef *Filter) fuzzyMatchName(ev events.Message, eventType string) bool {
 	return ef.filter.FuzzyMatch(eventType, ev.Actor.ID) ||
 		ef.filter.FuzzyMatch(eventType, ev.Actor.Attributes["name"])
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/docker/engine-api/types/events"
+	"github.com/docker/engine-api/types/filters"
+)
+
+func TestFilterSwarmEvents(t *testing.T) {
+	service := events.Message{
+		Type:   events.ServiceEventType,
+		Action: "create",
+		Actor:  events.Actor{ID: "svc1", Attributes: map[string]string{"name": "web"}},
+	}
+	node := events.Message{
+		Type:   events.NodeEventType,
+		Action: "update",
+		Actor:  events.Actor{ID: "node1", Attributes: map[string]string{"name": "worker1"}},
+	}
+	task := events.Message{
+		Type:   events.TaskEventType,
+		Action: "update",
+		Actor: events.Actor{ID: "task1", Attributes: map[string]string{
+			"name":    "web.1",
+			"service": "web",
+			"node":    "node1",
+		}},
+	}
+	other := events.Message{
+		Type:   events.TaskEventType,
+		Action: "update",
+		Actor: events.Actor{ID: "task2", Attributes: map[string]string{
+			"name":    "db.1",
+			"service": "db",
+			"node":    "node2",
+		}},
+	}
+
+	cases := []struct {
+		filter   string
+		value    string
+		included []events.Message
+		excluded []events.Message
+	}{
+		{"service", "web", []events.Message{service, task}, []events.Message{node, other}},
+		{"service", "svc1", []events.Message{service}, []events.Message{node, task, other}},
+		{"node", "worker1", []events.Message{node}, []events.Message{service, task, other}},
+		{"node", "node1", []events.Message{node, task}, []events.Message{service, other}},
+		{"task", "web.1", []events.Message{task}, []events.Message{service, node, other}},
+		{"type", "task", []events.Message{task, other}, []events.Message{service, node}},
+	}
+	for _, c := range cases {
+		args := filters.NewArgs()
+		args.Add(c.filter, c.value)
+		ef := NewFilter(args)
+		for _, ev := range c.included {
+			if !ef.Include(ev) {
+				t.Errorf("%s=%s should include %s %s", c.filter, c.value, ev.Type, ev.Actor.ID)
+			}
+		}
+		for _, ev := range c.excluded {
+			if ef.Include(ev) {
+				t.Errorf("%s=%s should exclude %s %s", c.filter, c.value, ev.Type, ev.Actor.ID)
+			}
+		}
+	}
+}
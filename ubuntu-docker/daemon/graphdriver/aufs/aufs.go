@@ -73,6 +73,7 @@ type Driver struct {
 	ctr           *graphdriver.RefCounter
 	pathCacheLock sync.Mutex
 	pathCache     map[string]string
+	naiveDiff     graphdriver.Driver
 }
 
 // Init returns a new AUFS driver.
@@ -111,6 +112,7 @@ func Init(root string, options []string, uidMaps, gidMaps []idtools.IDMap) (grap
 		pathCache: make(map[string]string),
 		ctr:       graphdriver.NewRefCounter(graphdriver.NewFsChecker(graphdriver.FsMagicAufs)),
 	}
+	a.naiveDiff = graphdriver.NewNaiveDiffDriver(a, uidMaps, gidMaps)
 
 	rootUID, rootGID, err := idtools.GetRootUIDGID(uidMaps, gidMaps)
 	if err != nil {
@@ -429,6 +431,10 @@ func (a *Driver) Put(id string) error {
 // Diff produces an archive of the changes between the specified
 // layer and its parent layer which may be "".
 func (a *Driver) Diff(id, parent string) (archive.Archive, error) {
+	if !a.isParent(id, parent) {
+		return a.naiveDiff.Diff(id, parent)
+	}
+
 	// AUFS doesn't need the parent layer to produce a diff.
 	return archive.TarWithOptions(path.Join(a.rootPath(), "diff", id), &archive.TarOptions{
 		Compression:     archive.Uncompressed,
@@ -438,6 +444,16 @@ func (a *Driver) Diff(id, parent string) (archive.Archive, error) {
 	})
 }
 
+// isParent returns whether parent is the direct parent of the layer id, the
+// only diff AUFS produces from the diff directory of the layer.
+func (a *Driver) isParent(id, parent string) bool {
+	parents, _ := getParentIds(a.rootPath(), id)
+	if parent == "" {
+		return len(parents) == 0
+	}
+	return len(parents) > 0 && parents[0] == parent
+}
+
 type fileGetNilCloser struct {
 	storage.FileGetter
 }
@@ -61,6 +61,13 @@ const (
 // package. This would require that we no longer need the daemon to determine
 // whether images are being used by a stopped or running container.
 func (daemon *Daemon) ImageDelete(imageRef string, force, prune bool) ([]types.ImageDelete, error) {
+	return daemon.imageDelete(imageRef, force, prune, "")
+}
+
+// imageDelete deletes an image like ImageDelete. A non-empty reason is added
+// to the attributes of the untag and delete events, to tell the deletions
+// made by the daemon itself from the ones requested by users.
+func (daemon *Daemon) imageDelete(imageRef string, force, prune bool, reason string) ([]types.ImageDelete, error) {
 	records := []types.ImageDelete{}
 
 	imgID, err := daemon.GetImageID(imageRef)
@@ -99,7 +106,7 @@ func (daemon *Daemon) ImageDelete(imageRef string, force, prune bool) ([]types.I
 
 		untaggedRecord := types.ImageDelete{Untagged: parsedRef.String()}
 
-		daemon.LogImageEvent(imgID.String(), imgID.String(), "untag")
+		daemon.logImageDeleteEvent(imgID, "untag", reason)
 		records = append(records, untaggedRecord)
 
 		repoRefs = daemon.referenceStore.References(imgID)
@@ -162,13 +169,13 @@ func (daemon *Daemon) ImageDelete(imageRef string, force, prune bool) ([]types.I
 
 				untaggedRecord := types.ImageDelete{Untagged: parsedRef.String()}
 
-				daemon.LogImageEvent(imgID.String(), imgID.String(), "untag")
+				daemon.logImageDeleteEvent(imgID, "untag", reason)
 				records = append(records, untaggedRecord)
 			}
 		}
 	}
 
-	return records, daemon.imageDeleteHelper(imgID, &records, force, prune, removedRepositoryRef)
+	return records, daemon.imageDeleteHelper(imgID, &records, force, prune, removedRepositoryRef, reason)
 }
 
 // isSingleReference returns true when all references are from one repository
@@ -238,7 +245,7 @@ func (daemon *Daemon) removeImageRef(ref reference.Named) (reference.Named, erro
 // on the first encountered error. Removed references are logged to this
 // daemon's event service. An "Untagged" types.ImageDelete is added to the
 // given list of records.
-func (daemon *Daemon) removeAllReferencesToImageID(imgID image.ID, records *[]types.ImageDelete) error {
+func (daemon *Daemon) removeAllReferencesToImageID(imgID image.ID, records *[]types.ImageDelete, reason string) error {
 	imageRefs := daemon.referenceStore.References(imgID)
 
 	for _, imageRef := range imageRefs {
@@ -249,13 +256,23 @@ func (daemon *Daemon) removeAllReferencesToImageID(imgID image.ID, records *[]ty
 
 		untaggedRecord := types.ImageDelete{Untagged: parsedRef.String()}
 
-		daemon.LogImageEvent(imgID.String(), imgID.String(), "untag")
+		daemon.logImageDeleteEvent(imgID, "untag", reason)
 		*records = append(*records, untaggedRecord)
 	}
 
 	return nil
 }
 
+// logImageDeleteEvent logs an untag or delete event for the image imgID,
+// with the reason of the deletion if there is one.
+func (daemon *Daemon) logImageDeleteEvent(imgID image.ID, action, reason string) {
+	attributes := map[string]string{}
+	if reason != "" {
+		attributes["reason"] = reason
+	}
+	daemon.LogImageEventWithAttributes(imgID.String(), imgID.String(), action, attributes)
+}
+
 // ImageDeleteConflict holds a soft or hard conflict and an associated error.
 // Implements the error interface.
 type imageDeleteConflict struct {
@@ -287,7 +304,7 @@ func (idc *imageDeleteConflict) Error() string {
 // conflict is encountered, it will be returned immediately without deleting
 // the image. If quiet is true, any encountered conflicts will be ignored and
 // the function will return nil immediately without deleting the image.
-func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDelete, force, prune, quiet bool) error {
+func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDelete, force, prune, quiet bool, reason string) error {
 	// First, determine if this image has any conflicts. Ignore soft conflicts
 	// if force is true.
 	c := conflictHard
@@ -313,7 +330,7 @@ func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDe
 	}
 
 	// Delete all repository tag/digest references to this image.
-	if err := daemon.removeAllReferencesToImageID(imgID, records); err != nil {
+	if err := daemon.removeAllReferencesToImageID(imgID, records, reason); err != nil {
 		return err
 	}
 
@@ -322,7 +339,7 @@ func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDe
 		return err
 	}
 
-	daemon.LogImageEvent(imgID.String(), imgID.String(), "delete")
+	daemon.logImageDeleteEvent(imgID, "delete", reason)
 	*records = append(*records, types.ImageDelete{Deleted: imgID.String()})
 	for _, removedLayer := range removedLayers {
 		*records = append(*records, types.ImageDelete{Deleted: removedLayer.ChainID.String()})
@@ -337,7 +354,7 @@ func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDe
 	// either running or stopped).
 	// Do not force prunings, but do so quietly (stopping on any encountered
 	// conflicts).
-	return daemon.imageDeleteHelper(parent, records, false, true, true)
+	return daemon.imageDeleteHelper(parent, records, false, true, true, reason)
 }
 
 // checkImageDeleteConflict determines whether there are any conflicts
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/docker/docker/image/tarexport"
@@ -9,11 +10,18 @@ import (
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
-// the same tag are exported. names is the set of tags to export, and
+// the same tag are exported. names is the set of tags to export, format
+// is either "docker" (the default) or "oci" for an OCI image layout, and
 // outStream is the writer which the images are written to.
-func (daemon *Daemon) ExportImage(names []string, outStream io.Writer) error {
+func (daemon *Daemon) ExportImage(names []string, format string, outStream io.Writer) error {
 	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore, daemon)
-	return imageExporter.Save(names, outStream)
+	switch format {
+	case "", "docker":
+		return imageExporter.Save(names, outStream)
+	case "oci":
+		return imageExporter.SaveOCI(names, outStream)
+	}
+	return fmt.Errorf("invalid export format %q: must be docker or oci", format)
 }
 
 // LoadImage uploads a set of images into the repository. This is the
@@ -0,0 +1,183 @@
+package daemon
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/image"
+)
+
+const (
+	// imageGCKeepLabel is the label protecting the images it is set on
+	// from the image garbage collector, whatever its value.
+	imageGCKeepLabel = "com.docker.image.gc.keep"
+
+	// imageGCReason is the reason set on the untag and delete events of
+	// the images removed by the image garbage collector.
+	imageGCReason = "gc"
+)
+
+var (
+	// imageGCInterval is the time between two runs of the image garbage
+	// collector.
+	imageGCInterval = 5 * time.Minute
+
+	// imageGCMinAge is the time an image is kept after its last use, so
+	// that the images of running builds and pulls are not removed before
+	// a container uses them.
+	imageGCMinAge = 2 * time.Minute
+)
+
+// imageGCCandidate is an image the garbage collector may remove.
+type imageGCCandidate struct {
+	id       image.ID
+	lastUsed time.Time
+}
+
+type byLastUsed []imageGCCandidate
+
+func (c byLastUsed) Len() int           { return len(c) }
+func (c byLastUsed) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c byLastUsed) Less(i, j int) bool { return c[i].lastUsed.Before(c[j].lastUsed) }
+
+// imageGC runs the image garbage collector periodically until stop is
+// closed, when the daemon shuts down.
+func (daemon *Daemon) imageGC(stop <-chan struct{}) {
+	ticker := time.NewTicker(imageGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := daemon.collectImages(time.Now()); err != nil {
+				logrus.Errorf("Image garbage collection failed: %v", err)
+			}
+		}
+	}
+}
+
+// imageGCPath returns a path on the filesystem holding the layers of the
+// graph driver. Drivers which don't store them in their home directory
+// report where they store them in their status.
+func (daemon *Daemon) imageGCPath() (string, error) {
+	status := make(map[string]string)
+	for _, kv := range daemon.layerStore.DriverStatus() {
+		status[kv[0]] = kv[1]
+	}
+	if p := status["Data loop file"]; p != "" {
+		return p, nil
+	}
+	if daemon.GraphDriverName() == "devicemapper" {
+		return "", fmt.Errorf("image garbage collection thresholds are not supported with devicemapper on a thin pool device")
+	}
+	if p := status["Root Dir"]; p != "" {
+		return p, nil
+	}
+	return daemon.graphDriverHome, nil
+}
+
+// collectImages removes the unused images last used longer than the maximum
+// age of the policy ago. Then, if the usage of the filesystem of the graph
+// driver is above the high threshold, it removes the least recently used
+// images until the usage is below the low threshold.
+func (daemon *Daemon) collectImages(now time.Time) error {
+	high, low, maxAge := daemon.configStore.GetImageGCPolicy()
+	if high == 0 && maxAge == 0 {
+		return nil
+	}
+
+	var root string
+	overThreshold := false
+	if high > 0 {
+		var err error
+		if root, err = daemon.imageGCPath(); err != nil {
+			return err
+		}
+		usage, err := diskUsage(root)
+		if err != nil {
+			return err
+		}
+		overThreshold = usage >= float64(high)
+	}
+
+	for _, c := range daemon.imageGCCandidates(now.Add(-imageGCMinAge)) {
+		expired := maxAge > 0 && now.Sub(c.lastUsed) > maxAge
+		if !expired && !overThreshold {
+			// The candidates are sorted, none of the next ones expired.
+			break
+		}
+		if err := daemon.collectImage(c.id); err != nil {
+			logrus.Warnf("Image garbage collection could not remove image %s: %v", c.id, err)
+			continue
+		}
+		if overThreshold {
+			usage, err := diskUsage(root)
+			if err != nil {
+				return err
+			}
+			overThreshold = usage > float64(low)
+		}
+	}
+	if overThreshold {
+		logrus.Warnf("Image garbage collection could not bring the usage of %s below %d%%", root, low)
+	}
+	return nil
+}
+
+// imageGCCandidates returns the images the garbage collector may remove,
+// least recently used first. These are the images without children, which
+// are neither used by a container nor protected by the keep label, and were
+// last used before usedBefore.
+func (daemon *Daemon) imageGCCandidates(usedBefore time.Time) []imageGCCandidate {
+	var candidates []imageGCCandidate
+	for id, img := range daemon.imageStore.Heads() {
+		if img.Config != nil {
+			if _, keep := img.Config.Labels[imageGCKeepLabel]; keep {
+				continue
+			}
+		}
+		if daemon.getContainerUsingImage(id) != nil {
+			continue
+		}
+		lastUsed, err := daemon.imageStore.GetLastUsed(id)
+		if err != nil {
+			logrus.Warnf("Image garbage collection skipped image %s: %v", id, err)
+			continue
+		}
+		if !lastUsed.Before(usedBefore) {
+			continue
+		}
+		candidates = append(candidates, imageGCCandidate{id: id, lastUsed: lastUsed})
+	}
+	sort.Sort(byLastUsed(candidates))
+	return candidates
+}
+
+// collectImage removes the image id through the regular image deletion,
+// without force. The references to the image are removed first, and the
+// untagged parent images are pruned. Untagging an image doesn't check whether
+// it is used, so the image is skipped if a container or a child image was
+// created since it was selected, before each of its references is removed.
+func (daemon *Daemon) collectImage(id image.ID) error {
+	for {
+		refs := daemon.referenceStore.References(id)
+		if len(refs) == 0 {
+			break
+		}
+		if conflict := daemon.checkImageDeleteConflict(id, conflictHard|conflictStoppedContainer); conflict != nil {
+			return conflict
+		}
+		if _, err := daemon.imageDelete(refs[0].String(), false, true, imageGCReason); err != nil {
+			return err
+		}
+	}
+	if _, err := daemon.imageStore.Get(id); err != nil {
+		// Removing the last reference removed the image.
+		return nil
+	}
+	_, err := daemon.imageDelete(id.String(), false, true, imageGCReason)
+	return err
+}
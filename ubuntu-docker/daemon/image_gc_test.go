@@ -0,0 +1,196 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/reference"
+)
+
+func TestImageGCCandidates(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-daemon-image-gc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	ifs, err := image.NewFSStoreBackend(filepath.Join(tmp, "imagedb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The images have no layers, the layer store is never used.
+	is, err := image.NewImageStore(ifs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := reference.NewReferenceStore(filepath.Join(tmp, "repositories.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	daemon := &Daemon{
+		imageStore:     is,
+		referenceStore: rs,
+		containers:     container.NewMemoryStore(),
+	}
+
+	now := time.Now()
+	create := func(name, labels string, lastUsed time.Duration) image.ID {
+		config := fmt.Sprintf(`{"comment": %q, "config": {"Labels": {%s}}, "rootfs": {"type": "layers"}}`, name, labels)
+		id, err := is.Create([]byte(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := is.SetLastUsed(id, now.Add(-lastUsed)); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	unused := create("unused", "", 3*time.Hour)
+	create("kept", `"com.docker.image.gc.keep": ""`, 5*time.Hour)
+	used := create("used", "", 5*time.Hour)
+	parent := create("parent", "", 5*time.Hour)
+	child := create("child", "", 4*time.Hour)
+	create("recent", "", time.Minute)
+
+	if err := is.SetParent(child, parent); err != nil {
+		t.Fatal(err)
+	}
+	daemon.containers.Add("container", &container.Container{
+		CommonContainer: container.CommonContainer{ID: "container", ImageID: used},
+	})
+
+	candidates := daemon.imageGCCandidates(now.Add(-imageGCMinAge))
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %v", candidates)
+	}
+	if candidates[0].id != child || candidates[1].id != unused {
+		t.Fatalf("expected candidates %s and %s, got %v", child, unused, candidates)
+	}
+}
+
+type testDriverStatusStore struct {
+	layer.Store
+	driver string
+	status [][2]string
+}
+
+func (ls testDriverStatusStore) DriverName() string {
+	return ls.driver
+}
+
+func (ls testDriverStatusStore) DriverStatus() [][2]string {
+	return ls.status
+}
+
+func TestImageGCPath(t *testing.T) {
+	for _, tc := range []struct {
+		driver   string
+		status   [][2]string
+		expected string
+	}{
+		{"overlay", [][2]string{{"Backing Filesystem", "extfs"}}, "/var/lib/docker/overlay"},
+		{"aufs", [][2]string{{"Root Dir", "/mnt/aufs"}, {"Backing Filesystem", "extfs"}}, "/mnt/aufs"},
+		{"devicemapper", [][2]string{{"Data file", "/dev/loop0"}, {"Data loop file", "/var/lib/docker/devicemapper/devicemapper/data"}}, "/var/lib/docker/devicemapper/devicemapper/data"},
+	} {
+		daemon := &Daemon{
+			layerStore:      testDriverStatusStore{driver: tc.driver, status: tc.status},
+			graphDriverHome: "/var/lib/docker/" + tc.driver,
+		}
+		path, err := daemon.imageGCPath()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.driver, err)
+		}
+		if path != tc.expected {
+			t.Fatalf("%s: expected %s, got %s", tc.driver, tc.expected, path)
+		}
+	}
+
+	// A thin pool device isn't a filesystem the usage can be read from.
+	daemon := &Daemon{
+		layerStore: testDriverStatusStore{driver: "devicemapper", status: [][2]string{{"Pool Name", "docker-thinpool"}}},
+	}
+	if _, err := daemon.imageGCPath(); err == nil {
+		t.Fatal("expected an error for devicemapper on a thin pool device")
+	}
+}
+
+func TestImageGCStop(t *testing.T) {
+	daemon := &Daemon{configStore: &Config{}}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		daemon.imageGC(stop)
+		close(done)
+	}()
+
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the image garbage collector to stop")
+	}
+}
+
+func TestCollectImageInUse(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-daemon-image-gc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	ifs, err := image.NewFSStoreBackend(filepath.Join(tmp, "imagedb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := image.NewImageStore(ifs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := reference.NewReferenceStore(filepath.Join(tmp, "repositories.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	daemon := &Daemon{
+		imageStore:     is,
+		referenceStore: rs,
+		containers:     container.NewMemoryStore(),
+	}
+
+	id, err := is.Create([]byte(`{"comment": "used", "rootfs": {"type": "layers"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"used:latest", "used:1.0"} {
+		ref, err := reference.ParseNamed(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rs.AddTag(ref, id, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A container created after the image was selected keeps both the
+	// image and its references, even those the regular image deletion
+	// would untag as the image has other references.
+	daemon.containers.Add("container", &container.Container{
+		CommonContainer: container.CommonContainer{ID: "container", ImageID: id, State: container.NewState()},
+	})
+	if err := daemon.collectImage(id); err == nil {
+		t.Fatal("expected an image used by a container not to be collected")
+	}
+	if refs := rs.References(id); len(refs) != 2 {
+		t.Fatalf("expected the references of the image to be kept, got %v", refs)
+	}
+	if _, err := is.Get(id); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,20 @@
+// +build linux freebsd
+
+package daemon
+
+import "syscall"
+
+// diskUsage returns the percentage of the space of the filesystem holding
+// path in use, computed like df does.
+func diskUsage(path string) (float64, error) {
+	var buf syscall.Statfs_t
+	if err := syscall.Statfs(path, &buf); err != nil {
+		return 0, err
+	}
+	used := buf.Blocks - buf.Bfree
+	total := used + uint64(buf.Bavail)
+	if total == 0 {
+		return 0, nil
+	}
+	return 100 * float64(used) / float64(total), nil
+}
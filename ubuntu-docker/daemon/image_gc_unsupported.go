@@ -0,0 +1,11 @@
+// +build !linux,!freebsd
+
+package daemon
+
+import "fmt"
+
+// diskUsage is not supported on this platform, only the maximum age of the
+// image garbage collection policy applies.
+func diskUsage(path string) (float64, error) {
+	return 0, fmt.Errorf("image garbage collection thresholds are not supported on this platform")
+}
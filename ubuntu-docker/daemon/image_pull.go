@@ -5,8 +5,11 @@ import (
 	"strings"
 
 	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/docker/builder"
+	"github.com/docker/docker/daemon/trust"
 	"github.com/docker/docker/distribution"
+	"github.com/docker/docker/errors"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -15,8 +18,10 @@ import (
 )
 
 // PullImage initiates a pull operation. image is the repository name to pull, and
-// tag may be either empty, or indicate a specific tag to pull.
-func (daemon *Daemon) PullImage(ctx context.Context, image, tag string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// tag may be either empty, or indicate a specific tag to pull. platform selects
+// the image to pull from manifest lists, it defaults to the platform of the
+// daemon when empty.
+func (daemon *Daemon) PullImage(ctx context.Context, image, tag, platform string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	// Special case: "pull -a" may send an image name with a
 	// trailing :. This is ugly, but let's not break API
 	// compatibility.
@@ -41,7 +46,14 @@ func (daemon *Daemon) PullImage(ctx context.Context, image, tag string, metaHead
 		}
 	}
 
-	return daemon.pullImageWithReference(ctx, ref, metaHeaders, authConfig, outStream)
+	var pullPlatform *manifestlist.PlatformSpec
+	if platform != "" {
+		if pullPlatform, err = distribution.ParsePlatform(platform); err != nil {
+			return errors.NewBadRequestError(err)
+		}
+	}
+
+	return daemon.pullImageWithReference(ctx, ref, pullPlatform, metaHeaders, authConfig, outStream)
 }
 
 // PullOnBuild tells Docker to pull image referenced by `name`.
@@ -67,13 +79,24 @@ func (daemon *Daemon) PullOnBuild(ctx context.Context, name string, authConfigs
 		pullRegistryAuth = &resolvedConfig
 	}
 
-	if err := daemon.pullImageWithReference(ctx, ref, nil, pullRegistryAuth, output); err != nil {
+	if err := daemon.pullImageWithReference(ctx, ref, nil, nil, pullRegistryAuth, output); err != nil {
 		return nil, err
 	}
 	return daemon.GetImage(name)
 }
 
-func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.Named, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.Named, platform *manifestlist.PlatformSpec, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+	rule, err := daemon.trustVerifier.CheckPull(ref)
+	if err != nil {
+		return err
+	}
+	if rule.Type == trust.SignedBy {
+		return daemon.pullSignedImage(ctx, ref, rule, platform, metaHeaders, authConfig, outStream)
+	}
+	return daemon.pullImage(ctx, ref, platform, metaHeaders, authConfig, outStream)
+}
+
+func (daemon *Daemon) pullImage(ctx context.Context, ref reference.Named, platform *manifestlist.PlatformSpec, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
 	progressChan := make(chan progress.Progress, 100)
@@ -97,6 +120,8 @@ func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.
 		ImageStore:       daemon.imageStore,
 		ReferenceStore:   daemon.referenceStore,
 		DownloadManager:  daemon.downloadManager,
+		Platform:         platform,
+		DownloadDir:      daemon.layerDownloadDir,
 	}
 
 	err := distribution.Pull(ctx, ref, imagePullConfig)
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// SquashImage creates a new image from the image id, with the layers added
+// on top of the image parent merged into a single layer. An empty parent
+// merges all the layers of the image. The history of the image is kept, the
+// entries of the merged layers are marked as empty layers.
+func (daemon *Daemon) SquashImage(id, parent string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("squashing images is not supported on Windows")
+	}
+	if id == parent {
+		return id, nil
+	}
+
+	img, err := daemon.imageStore.Get(image.ID(id))
+	if err != nil {
+		return "", err
+	}
+
+	parentImg := &image.Image{RootFS: image.NewRootFS()}
+	var parentChainID layer.ChainID
+	if parent != "" {
+		parentImg, err = daemon.imageStore.Get(image.ID(parent))
+		if err != nil {
+			return "", fmt.Errorf("error getting specified parent layer: %v", err)
+		}
+		parentChainID = parentImg.RootFS.ChainID()
+	}
+
+	l, err := daemon.layerStore.Get(img.RootFS.ChainID())
+	if err != nil {
+		return "", fmt.Errorf("error getting image layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+
+	// The changes are streamed from the graph driver to the new layer, they
+	// are never written to a temporary archive.
+	ts, err := l.TarStreamFrom(parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error getting tar stream to parent: %v", err)
+	}
+	defer ts.Close()
+
+	newL, err := daemon.layerStore.Register(ts, parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error registering layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, newL)
+
+	newImage := *img
+	rootFS := *parentImg.RootFS
+	rootFS.DiffIDs = append([]layer.DiffID(nil), rootFS.DiffIDs...)
+	emptyLayer := newL.DiffID() == layer.DigestSHA256EmptyTar
+	if !emptyLayer {
+		rootFS.Append(newL.DiffID())
+	}
+	newImage.RootFS = &rootFS
+
+	newImage.History = make([]image.History, len(img.History))
+	for i, h := range img.History {
+		if i >= len(parentImg.History) {
+			h.EmptyLayer = true
+		}
+		newImage.History[i] = h
+	}
+
+	now := time.Now().UTC()
+	comment := fmt.Sprintf("merge %s to %s", id, parent)
+	if parent == "" {
+		comment = fmt.Sprintf("create new from %s", id)
+	}
+	newImage.History = append(newImage.History, image.History{
+		Created:    now,
+		Comment:    comment,
+		EmptyLayer: emptyLayer,
+	})
+	newImage.Created = now
+
+	config, err := json.Marshal(&newImage)
+	if err != nil {
+		return "", err
+	}
+
+	newID, err := daemon.imageStore.Create(config)
+	if err != nil {
+		return "", err
+	}
+
+	if parent != "" {
+		if err := daemon.imageStore.SetParent(newID, image.ID(parent)); err != nil {
+			return "", err
+		}
+	}
+	return newID.String(), nil
+}
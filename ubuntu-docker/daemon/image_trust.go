@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/daemon/trust"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// pullSignedImage pulls the images of ref signed by the keys of rule. They
+// are pulled by the digest found in the trust data of the repository, then
+// tagged, and the verified signature is recorded with the image.
+func (daemon *Daemon) pullSignedImage(ctx context.Context, ref reference.Named, rule trust.Rule, platform *manifestlist.PlatformSpec, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+	targets, err := daemon.trustVerifier.SignedTargets(ctx, ref, rule, authConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		if err := daemon.pullImage(ctx, t.Ref, platform, metaHeaders, authConfig, outStream); err != nil {
+			return err
+		}
+		imgID, err := daemon.referenceStore.Get(t.Ref)
+		if err != nil {
+			return err
+		}
+		if err := daemon.trustVerifier.AddSignature(imgID, t.Signature); err != nil {
+			return err
+		}
+		if t.Tagged != nil {
+			if err := daemon.referenceStore.AddTag(t.Tagged, imgID, true); err != nil {
+				return err
+			}
+			daemon.LogImageEvent(imgID.String(), t.Tagged.String(), "tag")
+		}
+	}
+	return nil
+}
+
+// verifyImageTrust checks that the trust policy allows creating a container
+// from img, referenced as refOrID. An image referenced by ID must satisfy
+// the rules of all its references.
+func (daemon *Daemon) verifyImageTrust(refOrID string, img *image.Image) error {
+	var refs []reference.Named
+	if _, ref, err := reference.ParseIDOrReference(refOrID); err == nil && ref != nil {
+		ref = reference.WithDefaultTag(ref)
+		if id, err := daemon.referenceStore.Get(ref); err == nil && id == img.ID() {
+			refs = []reference.Named{ref}
+		}
+	}
+	if refs == nil {
+		refs = daemon.referenceStore.References(img.ID())
+	}
+	return daemon.trustVerifier.CheckImage(img.ID(), refs)
+}
+
+// reloadTrustPolicy reads the trust policy file of the configuration again.
+// The current policy is kept if the file is invalid.
+func (daemon *Daemon) reloadTrustPolicy() error {
+	if daemon.trustVerifier == nil {
+		return nil
+	}
+	if daemon.configStore.TrustPolicy == "" {
+		daemon.trustVerifier.SetPolicy(nil)
+		return nil
+	}
+	policy, err := trust.LoadPolicy(daemon.configStore.TrustPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to reload trust policy: %v", err)
+	}
+	daemon.trustVerifier.SetPolicy(policy)
+	logrus.Debugf("Reloaded trust policy %s", daemon.configStore.TrustPolicy)
+	return nil
+}
@@ -200,8 +200,9 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 }
 
 // VolumeInspect looks up a volume by name. An error is returned if
-// the volume cannot be found.
-func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
+// the volume cannot be found. The disk usage of the volume is computed if
+// size is set.
+func (daemon *Daemon) VolumeInspect(name string, size bool) (*types.Volume, error) {
 	v, err := daemon.volumes.Get(name)
 	if err != nil {
 		return nil, err
@@ -209,6 +210,12 @@ func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
 	apiV := volumeToAPIType(v)
 	apiV.Mountpoint = v.Path()
 	apiV.Status = v.Status()
+	if size {
+		apiV.UsageData, err = daemon.volumeUsageData(v)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return apiV, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
@@ -14,13 +15,16 @@ import (
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
 	networktypes "github.com/docker/engine-api/types/network"
+	timetypes "github.com/docker/engine-api/types/time"
 	"github.com/docker/go-connections/nat"
 )
 
 var acceptedVolumeFilterTags = map[string]bool{
-	"dangling": true,
-	"name":     true,
-	"driver":   true,
+	"dangling":   true,
+	"name":       true,
+	"driver":     true,
+	"since":      true,
+	"unused-for": true,
 }
 
 var acceptedPsFilterTags = map[string]bool{
@@ -532,8 +536,9 @@ func (daemon *Daemon) transformContainer(container *container.Container, ctx *li
 }
 
 // Volumes lists known volumes, using the filter to restrict the range
-// of volumes returned.
-func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error) {
+// of volumes returned. The disk usage of the volumes is computed if size
+// is set.
+func (daemon *Daemon) Volumes(filter string, size bool) ([]*types.Volume, []string, error) {
 	var (
 		volumesOut []*types.Volume
 	)
@@ -564,6 +569,12 @@ func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error)
 		} else {
 			apiV.Mountpoint = v.Path()
 		}
+		if size {
+			apiV.UsageData, err = daemon.volumeUsageData(v)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 		volumesOut = append(volumesOut, apiV)
 	}
 	return volumesOut, warnings, nil
@@ -600,9 +611,46 @@ func (daemon *Daemon) filterVolumes(vols []volume.Volume, filter filters.Args) (
 		}
 		retVols = daemon.volumes.FilterByUsed(retVols, !danglingOnly)
 	}
+	now := time.Now()
+	err := filter.WalkValues("since", func(value string) error {
+		since, err := parseVolumeFilterTime(value, now)
+		if err != nil {
+			return fmt.Errorf("Invalid filter 'since=%s': %v", value, err)
+		}
+		retVols = daemon.volumes.FilterByCreatedSince(retVols, since)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = filter.WalkValues("unused-for", func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("Invalid filter 'unused-for=%s'", value)
+		}
+		retVols = daemon.volumes.FilterByUnusedSince(retVols, now.Add(-d))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return retVols, nil
 }
 
+// parseVolumeFilterTime parses a timestamp, or a duration relative to now,
+// like the since option of events.
+func parseVolumeFilterTime(value string, now time.Time) (time.Time, error) {
+	ts, err := timetypes.GetTimestamp(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, nsec), nil
+}
+
 func populateImageFilterByParents(ancestorMap map[image.ID]bool, imageID image.ID, getChildren func(image.ID) []image.ID) {
 	if !ancestorMap[imageID] {
 		for _, id := range getChildren(imageID) {
@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/errors"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/libnetwork"
+	networktypes "github.com/docker/libnetwork/types"
+)
+
+// policyFromAPIType converts the policy of the API to the libnetwork one.
+func policyFromAPIType(p types.NetworkPolicy) (networktypes.NetworkPolicy, error) {
+	policy := networktypes.NetworkPolicy{
+		Name: p.Name,
+		From: p.From,
+		To:   p.To,
+		Port: p.Port,
+	}
+	if p.Protocol != "" {
+		policy.Proto = networktypes.ParseProtocol(p.Protocol)
+		if policy.Proto == 0 {
+			return policy, errors.NewBadRequestError(fmt.Errorf("invalid protocol %s for network policy %s", p.Protocol, p.Name))
+		}
+	}
+	return policy, nil
+}
+
+func policyToAPIType(p networktypes.NetworkPolicy) types.NetworkPolicy {
+	policy := types.NetworkPolicy{
+		Name: p.Name,
+		From: p.From,
+		To:   p.To,
+		Port: p.Port,
+	}
+	if p.Proto != 0 {
+		policy.Protocol = p.Proto.String()
+	}
+	return policy
+}
+
+// networkPolicyError returns the error of a policy operation with the status
+// of the API error it should be reported as.
+func networkPolicyError(err error) error {
+	switch err.(type) {
+	case networktypes.BadRequestError:
+		return errors.NewBadRequestError(err)
+	case networktypes.ForbiddenError:
+		return errors.NewRequestForbiddenError(err)
+	case networktypes.NotFoundError:
+		return errors.NewRequestNotFoundError(err)
+	}
+	return err
+}
+
+// findPolicyNetwork returns the network idName, which must be local to the
+// daemon to have policies.
+func (daemon *Daemon) findPolicyNetwork(idName string) (libnetwork.Network, error) {
+	nw, err := daemon.FindNetwork(idName)
+	if err != nil {
+		return nil, err
+	}
+	if nw.Info().Dynamic() {
+		return nil, errors.NewRequestForbiddenError(fmt.Errorf("operation not supported for swarm scoped networks"))
+	}
+	return nw, nil
+}
+
+// CreateNetworkPolicy adds the policy to the network idName. The rules of
+// the policy are programmed for the containers already connected to the
+// network.
+func (daemon *Daemon) CreateNetworkPolicy(idName string, p types.NetworkPolicy) error {
+	nw, err := daemon.findPolicyNetwork(idName)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.NewBadRequestError(fmt.Errorf("network policy name cannot be empty"))
+	}
+	policy, err := policyFromAPIType(p)
+	if err != nil {
+		return err
+	}
+
+	existing, err := nw.Policies()
+	if err != nil {
+		return networkPolicyError(err)
+	}
+	for _, e := range existing {
+		if e.Name == policy.Name {
+			return errors.NewRequestConflictError(fmt.Errorf("network policy %s already exists on network %s", policy.Name, nw.Name()))
+		}
+	}
+
+	if err := nw.AddPolicy(policy); err != nil {
+		return networkPolicyError(err)
+	}
+	return nil
+}
+
+// NetworkPolicies returns the policies of the network idName.
+func (daemon *Daemon) NetworkPolicies(idName string) ([]types.NetworkPolicy, error) {
+	nw, err := daemon.findPolicyNetwork(idName)
+	if err != nil {
+		return nil, err
+	}
+
+	ls, err := nw.Policies()
+	if err != nil {
+		return nil, networkPolicyError(err)
+	}
+	policies := []types.NetworkPolicy{}
+	for _, p := range ls {
+		policies = append(policies, policyToAPIType(p))
+	}
+	return policies, nil
+}
+
+// DeleteNetworkPolicy removes the policy name from the network idName.
+func (daemon *Daemon) DeleteNetworkPolicy(idName, name string) error {
+	nw, err := daemon.findPolicyNetwork(idName)
+	if err != nil {
+		return err
+	}
+
+	if err := nw.RemovePolicy(name); err != nil {
+		return networkPolicyError(err)
+	}
+	return nil
+}
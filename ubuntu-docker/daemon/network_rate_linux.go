@@ -0,0 +1,126 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// The network rates of a container are applied with a token bucket filter
+// (tbf) as the root queueing discipline of both ends of the veth pairs of
+// its endpoints: the end in the container shapes the traffic the container
+// sends, the end in the host the traffic it receives. The interfaces which
+// are not veth pairs with an end in the host, like the macvlan ones, only
+// get the egress rate.
+const (
+	// networkRateBurst is the time of traffic at the full rate which can be
+	// sent at once, networkRateLatency the longest time a packet can wait in
+	// the queue before being dropped.
+	networkRateBurst   = 10 * time.Millisecond
+	networkRateLatency = 50 * time.Millisecond
+	// networkRateMinBurst holds at least a full sized ethernet frame.
+	networkRateMinBurst = 4096
+)
+
+// setNetworkRates applies the network rates of container c to the
+// interfaces of its network sandbox. The interfaces of a container without a
+// rate get their default queueing discipline back.
+func (daemon *Daemon) setNetworkRates(c *container.Container) error {
+	if c.HostConfig.NetworkMode.IsHost() || c.HostConfig.NetworkMode.IsContainer() {
+		return nil
+	}
+
+	sb, err := daemon.netController.SandboxByID(c.NetworkSettings.SandboxID)
+	if err != nil {
+		return err
+	}
+
+	ns, err := netns.GetFromPath(sb.Key())
+	if err != nil {
+		return fmt.Errorf("failed to get the network namespace of container %s: %v", c.ID, err)
+	}
+	defer ns.Close()
+
+	nlh, err := netlink.NewHandleAt(ns, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return fmt.Errorf("failed to open the network namespace of container %s: %v", c.ID, err)
+	}
+	defer nlh.Delete()
+
+	hostNlh, err := netlink.NewHandle(syscall.NETLINK_ROUTE)
+	if err != nil {
+		return err
+	}
+	defer hostNlh.Delete()
+
+	links, err := nlh.LinkList()
+	if err != nil {
+		return fmt.Errorf("failed to list the interfaces of container %s: %v", c.ID, err)
+	}
+
+	for _, link := range links {
+		if link.Attrs().Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if err := setLinkRate(nlh, link, c.HostConfig.NetworkEgressRate); err != nil {
+			return fmt.Errorf("failed to set the network egress rate of interface %s of container %s: %v", link.Attrs().Name, c.ID, err)
+		}
+
+		if _, ok := link.(*netlink.Veth); !ok {
+			continue
+		}
+		// The peer of a veth is reported by its index in the namespace of
+		// the peer, which is not the host one for the overlay networks.
+		peer, err := hostNlh.LinkByIndex(link.Attrs().ParentIndex)
+		if err != nil {
+			continue
+		}
+		if _, ok := peer.(*netlink.Veth); !ok || peer.Attrs().ParentIndex != link.Attrs().Index {
+			continue
+		}
+		if err := setLinkRate(hostNlh, peer, c.HostConfig.NetworkIngressRate); err != nil {
+			return fmt.Errorf("failed to set the network ingress rate of interface %s of container %s: %v", link.Attrs().Name, c.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// setLinkRate replaces the root queueing discipline of the link with a tbf
+// limited to rate bytes per second, or removes the tbf when rate is not
+// positive.
+func setLinkRate(nlh *netlink.Handle, link netlink.Link, rate int64) error {
+	if rate <= 0 {
+		qdiscs, err := nlh.QdiscList(link)
+		if err != nil {
+			return err
+		}
+		for _, qdisc := range qdiscs {
+			if qdisc.Type() == "tbf" && qdisc.Attrs().Parent == netlink.HANDLE_ROOT {
+				return nlh.QdiscDel(qdisc)
+			}
+		}
+		return nil
+	}
+
+	burst := uint32(rate * int64(networkRateBurst) / int64(time.Second))
+	if burst < networkRateMinBurst {
+		burst = networkRateMinBurst
+	}
+	qdisc := &netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   uint64(rate),
+		Buffer: uint32(netlink.Xmittime(uint64(rate), burst)),
+		Limit:  burst + uint32(rate*int64(networkRateLatency)/int64(time.Second)),
+	}
+	return nlh.QdiscReplace(qdisc)
+}
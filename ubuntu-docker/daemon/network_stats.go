@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/engine-api/types"
+	networktypes "github.com/docker/libnetwork/types"
+)
+
+// networkStatsFromInterface converts the libnetwork statistics of an
+// interface into the engine-api ones.
+func networkStatsFromInterface(s *networktypes.InterfaceStatistics) types.NetworkStats {
+	return types.NetworkStats{
+		RxBytes:   s.RxBytes,
+		RxPackets: s.RxPackets,
+		RxErrors:  s.RxErrors,
+		RxDropped: s.RxDropped,
+		TxBytes:   s.TxBytes,
+		TxPackets: s.TxPackets,
+		TxErrors:  s.TxErrors,
+		TxDropped: s.TxDropped,
+	}
+}
+
+func addNetworkStats(total *types.NetworkStats, s types.NetworkStats) {
+	total.RxBytes += s.RxBytes
+	total.RxPackets += s.RxPackets
+	total.RxErrors += s.RxErrors
+	total.RxDropped += s.RxDropped
+	total.TxBytes += s.TxBytes
+	total.TxPackets += s.TxPackets
+	total.TxErrors += s.TxErrors
+	total.TxDropped += s.TxDropped
+}
+
+// NetworkStats returns the traffic of the network idName on this host. The
+// totals are the sum of the counters of the endpoints joined to a container,
+// as seen from the container. The interfaces the driver created for the
+// network, like the VXLAN ones of the overlay driver, are reported apart as
+// they carry the traffic of the endpoints again.
+func (daemon *Daemon) NetworkStats(idName string) (*types.NetworkTrafficStats, error) {
+	nw, err := daemon.FindNetwork(idName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &types.NetworkTrafficStats{
+		Read:      time.Now(),
+		ID:        nw.ID(),
+		Name:      nw.Name(),
+		Endpoints: make(map[string]types.EndpointTrafficStats),
+	}
+
+	for _, ep := range nw.Endpoints() {
+		epStats, err := ep.Statistics()
+		if err != nil {
+			// The endpoint can leave its sandbox while it is being read.
+			logrus.Debugf("Failed to get the statistics of endpoint %s on network %s: %v", ep.Name(), nw.Name(), err)
+			continue
+		}
+		if epStats == nil {
+			continue
+		}
+		s := types.EndpointTrafficStats{
+			Name:         ep.Name(),
+			NetworkStats: networkStatsFromInterface(epStats),
+		}
+		if sb := ep.Info().Sandbox(); sb != nil {
+			s.ContainerID = sb.ContainerID()
+		}
+		stats.Endpoints[ep.ID()] = s
+		addNetworkStats(&stats.Totals, s.NetworkStats)
+	}
+
+	ifaces, err := nw.DriverStatistics()
+	if err != nil {
+		return nil, err
+	}
+	if len(ifaces) > 0 {
+		stats.Interfaces = make(map[string]types.NetworkStats, len(ifaces))
+		for name, s := range ifaces {
+			stats.Interfaces[name] = networkStatsFromInterface(s)
+		}
+	}
+
+	return stats, nil
+}
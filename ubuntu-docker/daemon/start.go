@@ -127,6 +127,12 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		return err
 	}
 
+	if container.HostConfig.NetworkIngressRate > 0 || container.HostConfig.NetworkEgressRate > 0 {
+		if err := daemon.setNetworkRates(container); err != nil {
+			return err
+		}
+	}
+
 	spec, err := daemon.createSpec(container)
 	if err != nil {
 		return err
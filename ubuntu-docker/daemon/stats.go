@@ -178,16 +178,7 @@ func (daemon *Daemon) getNetworkStats(c *container.Container) (map[string]types.
 	stats := make(map[string]types.NetworkStats)
 	// Convert libnetwork nw stats into engine-api stats
 	for ifName, ifStats := range lnstats {
-		stats[ifName] = types.NetworkStats{
-			RxBytes:   ifStats.RxBytes,
-			RxPackets: ifStats.RxPackets,
-			RxErrors:  ifStats.RxErrors,
-			RxDropped: ifStats.RxDropped,
-			TxBytes:   ifStats.TxBytes,
-			TxPackets: ifStats.TxPackets,
-			TxErrors:  ifStats.TxErrors,
-			TxDropped: ifStats.TxDropped,
-		}
+		stats[ifName] = networkStatsFromInterface(ifStats)
 	}
 
 	return stats, nil
@@ -0,0 +1,137 @@
+package trust
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/docker/reference"
+)
+
+const (
+	// Accept allows the images of a scope without checking signatures.
+	Accept = "accept"
+	// Reject refuses the images of a scope.
+	Reject = "reject"
+	// SignedBy only allows the images of a scope that are signed by one of
+	// the keys of the rule.
+	SignedBy = "signedBy"
+)
+
+// Rule is the requirement applied to the images of the repositories under
+// Scope.
+type Rule struct {
+	// Scope is a registry hostname, or a repository name or namespace in
+	// its full form, like "docker.io/library/busybox" or
+	// "registry.example.com/prod".
+	Scope string `json:"scope"`
+	// Type is one of Accept, Reject or SignedBy.
+	Type string `json:"type"`
+	// Keys are the IDs of the keys allowed to sign the images of the
+	// scope, for SignedBy rules.
+	Keys []string `json:"keys,omitempty"`
+	// Server is the URL of the trust server of the scope. It defaults
+	// to the notary server of Docker Hub for official images and to the
+	// registry of the repository for the others.
+	Server string `json:"server,omitempty"`
+}
+
+// Policy is the set of rules deciding which images the daemon runs.
+type Policy struct {
+	// Default is the type of the rule applied to the repositories that
+	// are in no scope. It defaults to Accept.
+	Default string `json:"default,omitempty"`
+	// DefaultKeys are the keys of the default rule when it is SignedBy.
+	DefaultKeys []string `json:"defaultKeys,omitempty"`
+	Rules       []Rule   `json:"rules,omitempty"`
+}
+
+// LoadPolicy reads and validates the policy stored in the JSON file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read trust policy: %v", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(content, &p); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %v", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %v", path, err)
+	}
+	return &p, nil
+}
+
+// Validate checks that the rules of the policy are well formed.
+func (p *Policy) Validate() error {
+	if err := validateRule(Rule{Scope: "default", Type: p.defaultType(), Keys: p.DefaultKeys}); err != nil {
+		return err
+	}
+	scopes := make(map[string]struct{})
+	for _, r := range p.Rules {
+		if r.Scope == "" || strings.HasSuffix(r.Scope, "/") {
+			return fmt.Errorf("invalid scope %q", r.Scope)
+		}
+		if _, exists := scopes[r.Scope]; exists {
+			return fmt.Errorf("duplicate rule for scope %q", r.Scope)
+		}
+		scopes[r.Scope] = struct{}{}
+		if err := validateRule(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRule(r Rule) error {
+	switch r.Type {
+	case Accept, Reject:
+		if len(r.Keys) != 0 {
+			return fmt.Errorf("keys are only allowed in %s rules, scope %q", SignedBy, r.Scope)
+		}
+	case SignedBy:
+		if len(r.Keys) == 0 {
+			return fmt.Errorf("%s rule for scope %q has no keys", SignedBy, r.Scope)
+		}
+	default:
+		return fmt.Errorf("invalid rule type %q for scope %q", r.Type, r.Scope)
+	}
+	return nil
+}
+
+func (p *Policy) defaultType() string {
+	if p.Default == "" {
+		return Accept
+	}
+	return p.Default
+}
+
+// RuleFor returns the rule applied to the repository of ref, the rule with
+// the longest scope containing it, or the default rule. A nil ref, for an
+// image that is only known by its ID, gets the default rule.
+func (p *Policy) RuleFor(ref reference.Named) Rule {
+	rule := Rule{Type: p.defaultType(), Keys: p.DefaultKeys}
+	if ref == nil {
+		return rule
+	}
+	name := ref.FullName()
+	for _, r := range p.Rules {
+		if (name == r.Scope || strings.HasPrefix(name, r.Scope+"/")) && len(r.Scope) > len(rule.Scope) {
+			rule = r
+		}
+	}
+	return rule
+}
+
+// allowsKeys returns whether one of keyIDs is allowed by the rule.
+func (r Rule) allowsKeys(keyIDs []string) bool {
+	for _, allowed := range r.Keys {
+		for _, id := range keyIDs {
+			if id == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
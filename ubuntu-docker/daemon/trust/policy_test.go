@@ -0,0 +1,96 @@
+package trust
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/reference"
+)
+
+func TestPolicyRuleFor(t *testing.T) {
+	p := &Policy{
+		Default:     SignedBy,
+		DefaultKeys: []string{"defaultkey"},
+		Rules: []Rule{
+			{Scope: "docker.io/library", Type: Accept},
+			{Scope: "docker.io/library/ubuntu", Type: Reject},
+			{Scope: "registry.example.com", Type: SignedBy, Keys: []string{"key1"}},
+		},
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		ref      string
+		expected string
+		scope    string
+	}{
+		{"busybox", Accept, "docker.io/library"},
+		{"busybox:1.24", Accept, "docker.io/library"},
+		{"ubuntu:14.04", Reject, "docker.io/library/ubuntu"},
+		{"ubuntu-debootstrap", Accept, "docker.io/library"},
+		{"registry.example.com/prod/app:1", SignedBy, "registry.example.com"},
+		{"registry.example.com:5000/app", SignedBy, ""},
+		{"someone/app", SignedBy, ""},
+	}
+	for _, c := range cases {
+		ref, err := reference.ParseNamed(c.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule := p.RuleFor(ref)
+		if rule.Type != c.expected || rule.Scope != c.scope {
+			t.Errorf("%s: expected %s rule of scope %q, got %s rule of scope %q", c.ref, c.expected, c.scope, rule.Type, rule.Scope)
+		}
+	}
+
+	if rule := p.RuleFor(nil); rule.Type != SignedBy || len(rule.Keys) != 1 {
+		t.Errorf("expected the default rule for an image without reference, got %v", rule)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "trust-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	invalid := map[string]string{
+		"not json":     `{`,
+		"unknown type": `{"rules": [{"scope": "docker.io", "type": "maybe"}]}`,
+		"no keys":      `{"rules": [{"scope": "docker.io", "type": "signedBy"}]}`,
+		"keys":         `{"rules": [{"scope": "docker.io", "type": "accept", "keys": ["key1"]}]}`,
+		"empty scope":  `{"rules": [{"type": "reject"}]}`,
+		"slash":        `{"rules": [{"scope": "docker.io/", "type": "reject"}]}`,
+		"duplicate":    `{"rules": [{"scope": "docker.io", "type": "reject"}, {"scope": "docker.io", "type": "accept"}]}`,
+		"default":      `{"default": "signedBy"}`,
+		"default type": `{"default": "never"}`,
+		"default keys": `{"defaultKeys": ["key1"]}`,
+	}
+	for name, content := range invalid {
+		path := filepath.Join(tmpDir, "policy.json")
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	path := filepath.Join(tmpDir, "policy.json")
+	content := `{"default": "reject", "rules": [{"scope": "docker.io/library", "type": "signedBy", "keys": ["key1"]}]}`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Default != Reject || len(p.Rules) != 1 || p.Rules[0].Keys[0] != "key1" {
+		t.Fatalf("unexpected policy %+v", p)
+	}
+}
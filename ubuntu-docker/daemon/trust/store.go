@@ -0,0 +1,82 @@
+package trust
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/image"
+)
+
+// signaturesKey is the image metadata key the signatures are stored under.
+const signaturesKey = "signatures"
+
+// Signature records that an image was verified against the trust data of a
+// repository when it was pulled.
+type Signature struct {
+	// Repository is the full name of the repository.
+	Repository string `json:"repository"`
+	// Tag is the tag the image was pulled by, if any.
+	Tag string `json:"tag,omitempty"`
+	// Digest is the digest of the manifest of the image.
+	Digest digest.Digest `json:"digest"`
+	// Role is the role of the trust data that signed the image.
+	Role string `json:"role"`
+	// KeyIDs are the IDs of the keys of the role that signed the image.
+	KeyIDs   []string  `json:"keyIDs"`
+	Verified time.Time `json:"verified"`
+}
+
+// MetadataStore stores the metadata of images, it is implemented by
+// image.StoreBackend.
+type MetadataStore interface {
+	SetMetadata(id image.ID, key string, data []byte) error
+	GetMetadata(id image.ID, key string) ([]byte, error)
+}
+
+// signatureStore keeps the signatures of an image with its metadata, so
+// that they are removed with the image.
+type signatureStore struct {
+	mu sync.Mutex
+	ms MetadataStore
+}
+
+func (s *signatureStore) get(id image.ID) ([]Signature, error) {
+	content, err := s.ms.GetMetadata(id, signaturesKey)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sigs []Signature
+	if err := json.Unmarshal(content, &sigs); err != nil {
+		return nil, err
+	}
+	return sigs, nil
+}
+
+// add records sig for the image id, replacing a previous signature of the
+// same repository and tag.
+func (s *signatureStore) add(id image.ID, sig Signature) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sigs, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(sigs); i++ {
+		if sigs[i].Repository == sig.Repository && sigs[i].Tag == sig.Tag {
+			sigs = append(sigs[:i], sigs[i+1:]...)
+			i--
+		}
+	}
+	content, err := json.Marshal(append(sigs, sig))
+	if err != nil {
+		return err
+	}
+	return s.ms.SetMetadata(id, signaturesKey, content)
+}
@@ -0,0 +1,317 @@
+// Package trust enforces the image signature policy of the daemon. Images of
+// the repositories that require a signature are pulled by the digest signed
+// in the trust data of the repository, and the verified signatures are kept
+// with the image so that containers are only created from signed images.
+package trust
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/notary/client"
+	"github.com/docker/notary/passphrase"
+	"github.com/docker/notary/trustpinning"
+	"github.com/docker/notary/tuf/data"
+	"golang.org/x/net/context"
+)
+
+// releasesRole is the delegation role the docker client signs images with.
+var releasesRole = path.Join(data.CanonicalTargetsRole, "releases")
+
+// Target is an image of a repository signed in its trust data.
+type Target struct {
+	// Ref is the reference to pull the image by.
+	Ref reference.Canonical
+	// Tagged is the reference to tag the image with, nil for a target
+	// requested by digest.
+	Tagged    reference.NamedTagged
+	Signature Signature
+}
+
+// Verifier checks images against the trust policy of the daemon.
+type Verifier struct {
+	mu              sync.RWMutex
+	policy          *Policy
+	trustDir        string
+	registryService registry.Service
+	signatures      *signatureStore
+}
+
+// NewVerifier returns a verifier caching trust data in trustDir and keeping
+// the signatures of the images in ms. It accepts all images until a policy
+// is set.
+func NewVerifier(trustDir string, registryService registry.Service, ms MetadataStore) *Verifier {
+	return &Verifier{
+		trustDir:        trustDir,
+		registryService: registryService,
+		signatures:      &signatureStore{ms: ms},
+	}
+}
+
+// SetPolicy replaces the policy of the verifier, a nil policy accepts all
+// images.
+func (v *Verifier) SetPolicy(p *Policy) {
+	v.mu.Lock()
+	v.policy = p
+	v.mu.Unlock()
+}
+
+// Policy returns the policy of the verifier.
+func (v *Verifier) Policy() *Policy {
+	if v == nil {
+		return &Policy{}
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.policy == nil {
+		return &Policy{}
+	}
+	return v.policy
+}
+
+// Signatures returns the signatures recorded for the image id.
+func (v *Verifier) Signatures(id image.ID) ([]Signature, error) {
+	return v.signatures.get(id)
+}
+
+// AddSignature records sig for the image id.
+func (v *Verifier) AddSignature(id image.ID, sig Signature) error {
+	return v.signatures.add(id, sig)
+}
+
+// CheckImage checks that the policy allows running the image id, known as
+// refs. An image without references only has to satisfy the default rule.
+func (v *Verifier) CheckImage(id image.ID, refs []reference.Named) error {
+	p := v.Policy()
+	if len(p.Rules) == 0 && p.defaultType() == Accept {
+		return nil
+	}
+
+	sigs, err := v.signatures.get(id)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		refs = []reference.Named{nil}
+	}
+	for _, ref := range refs {
+		rule := p.RuleFor(ref)
+		switch rule.Type {
+		case Reject:
+			return rejectedError(ref)
+		case SignedBy:
+			if !isSigned(sigs, ref, rule) {
+				name := id.String()
+				if ref != nil {
+					name = ref.String()
+				}
+				return errors.NewRequestForbiddenError(fmt.Errorf("trust policy requires %s to be signed by one of the keys %v", name, rule.Keys))
+			}
+		}
+	}
+	return nil
+}
+
+func isSigned(sigs []Signature, ref reference.Named, rule Rule) bool {
+	for _, sig := range sigs {
+		if ref != nil && sig.Repository != ref.FullName() {
+			continue
+		}
+		if rule.allowsKeys(sig.KeyIDs) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPull returns the rule for pulling ref, or an error if the policy
+// rejects the repository.
+func (v *Verifier) CheckPull(ref reference.Named) (Rule, error) {
+	rule := v.Policy().RuleFor(ref)
+	if rule.Type == Reject {
+		return rule, rejectedError(ref)
+	}
+	return rule, nil
+}
+
+func rejectedError(ref reference.Named) error {
+	if ref == nil {
+		return errors.NewRequestForbiddenError(fmt.Errorf("trust policy rejects images without a repository"))
+	}
+	return errors.NewRequestForbiddenError(fmt.Errorf("trust policy rejects images of %s", ref.FullName()))
+}
+
+// SignedTargets returns the images to pull for ref, as signed by the keys of
+// rule in the trust data of the repository. A reference without tag or
+// digest returns all the signed tags of the repository.
+func (v *Verifier) SignedTargets(ctx context.Context, ref reference.Named, rule Rule, authConfig *types.AuthConfig) ([]Target, error) {
+	repoInfo, err := v.registryService.ResolveRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := v.notaryRepository(ctx, repoInfo, rule, authConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*client.TargetWithRole
+	if tagged, ok := ref.(reference.NamedTagged); ok {
+		t, err := repo.GetTargetByName(tagged.Tag(), releasesRole, data.CanonicalTargetsRole)
+		if err != nil {
+			return nil, errors.NewRequestForbiddenError(fmt.Errorf("no trust data for %s: %v", ref.String(), err))
+		}
+		targets = append(targets, t)
+	} else {
+		targets, err = repo.ListTargets(releasesRole, data.CanonicalTargetsRole)
+		if err != nil {
+			return nil, errors.NewRequestForbiddenError(fmt.Errorf("no trust data for %s: %v", ref.FullName(), err))
+		}
+	}
+
+	signers, err := roleSigners(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Target
+	for _, t := range targets {
+		// Only the top level targets role and the releases delegation
+		// are trusted, like the docker client does.
+		if t.Role != releasesRole && t.Role != data.CanonicalTargetsRole {
+			continue
+		}
+		h, ok := t.Hashes["sha256"]
+		if !ok {
+			continue
+		}
+		dgst := digest.NewDigestFromHex("sha256", hex.EncodeToString(h))
+		if canonical, ok := ref.(reference.Canonical); ok && canonical.Digest() != dgst {
+			continue
+		}
+		if !rule.allowsKeys(signers[t.Role]) {
+			logrus.Debugf("%s:%s is signed by %v, not by one of %v", ref.FullName(), t.Name, signers[t.Role], rule.Keys)
+			continue
+		}
+
+		target := Target{
+			Signature: Signature{
+				Repository: ref.FullName(),
+				Digest:     dgst,
+				Role:       t.Role,
+				KeyIDs:     signers[t.Role],
+				Verified:   time.Now().UTC(),
+			},
+		}
+		if target.Ref, err = reference.WithDigest(ref, dgst); err != nil {
+			return nil, err
+		}
+		if _, isCanonical := ref.(reference.Canonical); !isCanonical {
+			if target.Tagged, err = reference.WithTag(ref, t.Name); err != nil {
+				return nil, err
+			}
+			target.Signature.Tag = t.Name
+		}
+		result = append(result, target)
+
+		// A digest may be signed under several tags, one is enough.
+		if _, isCanonical := ref.(reference.Canonical); isCanonical {
+			break
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errors.NewRequestForbiddenError(fmt.Errorf("trust policy requires %s to be signed by one of the keys %v", ref.String(), rule.Keys))
+	}
+	return result, nil
+}
+
+// roleSigners returns the IDs of the keys of each role that signed the
+// current trust data of repo.
+func roleSigners(repo *client.NotaryRepository) (map[string][]string, error) {
+	roles, err := repo.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	signers := make(map[string][]string)
+	for _, r := range roles {
+		keys := make(map[string]struct{})
+		for _, id := range r.KeyIDs {
+			keys[id] = struct{}{}
+		}
+		for _, sig := range r.Signatures {
+			if _, ok := keys[sig.KeyID]; ok {
+				signers[r.Name] = append(signers[r.Name], sig.KeyID)
+			}
+		}
+	}
+	return signers, nil
+}
+
+// notaryRepository returns the trust data of the repository, fetched from
+// the trust server of the rule.
+func (v *Verifier) notaryRepository(ctx context.Context, repoInfo *registry.RepositoryInfo, rule Rule, authConfig *types.AuthConfig) (*client.NotaryRepository, error) {
+	server := rule.Server
+	if server == "" {
+		if repoInfo.Index.Official {
+			server = registry.NotaryServer
+		} else {
+			server = "https://" + repoInfo.Index.Name
+		}
+	}
+	u, err := url.Parse(server)
+	if err != nil || u.Scheme != "https" {
+		return nil, fmt.Errorf("valid https URL required for trust server, got %s", server)
+	}
+
+	tlsConfig, err := v.registryService.TLSConfig(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	base := registry.NewTransport(tlsConfig)
+	modifiers := registry.DockerHeaders(dockerversion.DockerUserAgent(ctx), nil)
+	authTransport := transport.NewTransport(base, modifiers...)
+
+	challengeManager := auth.NewSimpleChallengeManager()
+	pingClient := &http.Client{
+		Transport: authTransport,
+		Timeout:   5 * time.Second,
+	}
+	resp, err := pingClient.Get(server + "/v2/")
+	if err != nil {
+		// The cached trust data is used when the server is unreachable.
+		logrus.Debugf("Error pinging trust server %q: %v", server, err)
+	} else {
+		defer resp.Body.Close()
+		if err := challengeManager.AddResponse(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	if authConfig == nil {
+		authConfig = &types.AuthConfig{}
+	}
+	creds := registry.NewStaticCredentialStore(authConfig)
+	tokenHandler := auth.NewTokenHandler(authTransport, creds, repoInfo.FullName(), "pull")
+	basicHandler := auth.NewBasicHandler(creds)
+	modifiers = append(modifiers, auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler))
+
+	// The daemon only reads trust data, it has no signing keys to unlock.
+	return client.NewNotaryRepository(
+		v.trustDir, repoInfo.FullName(), server, transport.NewTransport(base, modifiers...),
+		passphrase.ConstantRetriever(""), trustpinning.TrustPinConfig{})
+}
@@ -0,0 +1,88 @@
+package trust
+
+import (
+	"os"
+	"testing"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+)
+
+type fakeMetadataStore map[string][]byte
+
+func (s fakeMetadataStore) SetMetadata(id image.ID, key string, data []byte) error {
+	s[id.String()+"/"+key] = data
+	return nil
+}
+
+func (s fakeMetadataStore) GetMetadata(id image.ID, key string) ([]byte, error) {
+	data, ok := s[id.String()+"/"+key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return data, nil
+}
+
+func TestCheckImage(t *testing.T) {
+	v := NewVerifier("", nil, fakeMetadataStore{})
+	signed := image.ID("sha256:2b8fd9751c4c0f5dd266fcae00707e67a2545ef34f9a29354585f93dac906749")
+	unsigned := image.ID("sha256:7f6a7e2a1c1e8bb34b4f9a0d5a9b5f4c3c53c0e4c6b30d4a8a4d71f8a5e9c3d1")
+
+	busybox, _ := reference.ParseNamed("busybox:latest")
+	app, _ := reference.ParseNamed("registry.example.com/app:1")
+	ubuntu, _ := reference.ParseNamed("ubuntu:14.04")
+
+	// Everything is accepted without a policy.
+	if err := v.CheckImage(unsigned, []reference.Named{app}); err != nil {
+		t.Fatal(err)
+	}
+
+	v.SetPolicy(&Policy{
+		Rules: []Rule{
+			{Scope: "registry.example.com", Type: SignedBy, Keys: []string{"key1"}},
+			{Scope: "docker.io/library/ubuntu", Type: Reject},
+		},
+	})
+	if err := v.AddSignature(signed, Signature{Repository: app.FullName(), Tag: "1", KeyIDs: []string{"key1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.CheckImage(signed, []reference.Named{app}); err != nil {
+		t.Fatalf("signed image should be accepted: %v", err)
+	}
+	if err := v.CheckImage(signed, nil); err != nil {
+		t.Fatalf("image without reference should get the default rule: %v", err)
+	}
+	if err := v.CheckImage(unsigned, []reference.Named{app}); err == nil {
+		t.Fatal("unsigned image should be refused")
+	}
+	if err := v.CheckImage(unsigned, []reference.Named{busybox}); err != nil {
+		t.Fatalf("image of an accepted repository should be accepted: %v", err)
+	}
+	if err := v.CheckImage(signed, []reference.Named{app, ubuntu}); err == nil {
+		t.Fatal("image also known in a rejected repository should be refused")
+	}
+
+	// The signature is checked against the keys of the current policy.
+	v.SetPolicy(&Policy{
+		Rules: []Rule{{Scope: "registry.example.com", Type: SignedBy, Keys: []string{"key2"}}},
+	})
+	if err := v.CheckImage(signed, []reference.Named{app}); err == nil {
+		t.Fatal("image signed by a key no longer trusted should be refused")
+	}
+
+	// A new signature of the same tag replaces the previous one.
+	if err := v.AddSignature(signed, Signature{Repository: app.FullName(), Tag: "1", KeyIDs: []string{"key2"}}); err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := v.Signatures(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigs) != 1 || sigs[0].KeyIDs[0] != "key2" {
+		t.Fatalf("unexpected signatures %+v", sigs)
+	}
+	if err := v.CheckImage(signed, []reference.Named{app}); err != nil {
+		t.Fatal(err)
+	}
+}
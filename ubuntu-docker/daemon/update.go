@@ -82,6 +82,12 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 			restoreConfig = true
 			return errCannotUpdate(container.ID, err)
 		}
+		if hostConfig.NetworkIngressRate != 0 || hostConfig.NetworkEgressRate != 0 {
+			if err := daemon.setNetworkRates(container); err != nil {
+				restoreConfig = true
+				return errCannotUpdate(container.ID, err)
+			}
+		}
 	}
 
 	daemon.LogContainerEvent(container, "update")
@@ -0,0 +1,157 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+)
+
+// VolumeExport writes the contents of the volume to the given writer as
+// a tar archive.
+func (daemon *Daemon) VolumeExport(name string, out io.Writer) error {
+	v, ref, path, err := daemon.mountVolume(name)
+	if err != nil {
+		return err
+	}
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	data, err := archive.TarWithOptions(path, &archive.TarOptions{
+		Compression: archive.Uncompressed,
+		UIDMaps:     uidMaps,
+		GIDMaps:     gidMaps,
+	})
+	if err != nil {
+		daemon.unmountVolume(v, ref)
+		return fmt.Errorf("Error exporting volume %s: %v", name, err)
+	}
+	arch := ioutils.NewReadCloserWrapper(data, func() error {
+		err := data.Close()
+		daemon.unmountVolume(v, ref)
+		return err
+	})
+	defer arch.Close()
+
+	if _, err := io.Copy(out, arch); err != nil {
+		return fmt.Errorf("Error exporting volume %s: %v", name, err)
+	}
+	daemon.LogVolumeEvent(v.Name(), "export", map[string]string{"driver": v.DriverName()})
+	return nil
+}
+
+// VolumeImport extracts the tar archive read from content into the volume.
+// The files of the volume which are not in the archive are kept.
+func (daemon *Daemon) VolumeImport(name string, content io.Reader) error {
+	v, ref, path, err := daemon.mountVolume(name)
+	if err != nil {
+		return err
+	}
+	defer daemon.unmountVolume(v, ref)
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	if err := chrootarchive.Untar(content, path, &archive.TarOptions{
+		UIDMaps: uidMaps,
+		GIDMaps: gidMaps,
+	}); err != nil {
+		return fmt.Errorf("Error importing into volume %s: %v", name, err)
+	}
+	daemon.LogVolumeEvent(v.Name(), "import", map[string]string{"driver": v.DriverName()})
+	return nil
+}
+
+// VolumeClone creates the volume newName with a copy of the contents of the
+// volume name. The new volume uses the driver and the labels of the source
+// volume, unless driverName or labels are set. Cloning into an existing
+// volume is refused.
+func (daemon *Daemon) VolumeClone(name, newName, driverName string, opts, labels map[string]string) (*types.Volume, error) {
+	if newName != "" {
+		if _, err := daemon.volumes.Get(newName); err == nil {
+			return nil, errors.NewRequestConflictError(fmt.Errorf("A volume named %s already exists. Choose a different volume name.", newName))
+		}
+	}
+
+	src, srcRef, srcPath, err := daemon.mountVolume(name)
+	if err != nil {
+		return nil, err
+	}
+	defer daemon.unmountVolume(src, srcRef)
+
+	if driverName == "" {
+		driverName = src.DriverName()
+	}
+	if labels == nil {
+		if lv, ok := src.(volume.LabeledVolume); ok {
+			labels = lv.Labels()
+		}
+	}
+
+	apiV, err := daemon.VolumeCreate(newName, driverName, opts, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := daemon.copyVolume(srcPath, apiV.Name); err != nil {
+		if rmErr := daemon.VolumeRm(apiV.Name); rmErr != nil {
+			err = fmt.Errorf("%v, and the volume %s could not be removed: %v", err, apiV.Name, rmErr)
+		}
+		return nil, fmt.Errorf("Error cloning volume %s: %v", name, err)
+	}
+	daemon.LogVolumeEvent(src.Name(), "clone", map[string]string{"driver": src.DriverName(), "target": apiV.Name})
+	return apiV, nil
+}
+
+// copyVolume copies the files under srcPath into the volume name.
+func (daemon *Daemon) copyVolume(srcPath, name string) error {
+	v, ref, path, err := daemon.mountVolume(name)
+	if err != nil {
+		return err
+	}
+	defer daemon.unmountVolume(v, ref)
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	archiver := &archive.Archiver{
+		Untar:   chrootarchive.Untar,
+		UIDMaps: uidMaps,
+		GIDMaps: gidMaps,
+	}
+	return archiver.CopyWithTar(srcPath, path)
+}
+
+// mountVolume mounts the volume name through its driver, and returns it with
+// the reference it is mounted with and its path. The reference keeps the
+// volume from being removed until unmountVolume is called.
+func (daemon *Daemon) mountVolume(name string) (volume.Volume, string, string, error) {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	ref := stringid.GenerateNonCryptoID()
+	v, err = daemon.volumes.GetWithRef(v.Name(), v.DriverName(), ref)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	path, err := v.Mount(ref)
+	if err != nil {
+		daemon.volumes.Dereference(v, ref)
+		return nil, "", "", fmt.Errorf("Error mounting volume %s: %v", name, err)
+	}
+	return v, ref, path, nil
+}
+
+// unmountVolume unmounts a volume mounted with mountVolume and releases its
+// reference.
+func (daemon *Daemon) unmountVolume(v volume.Volume, ref string) {
+	if err := v.Unmount(ref); err != nil {
+		logrus.Warnf("Error unmounting volume %s: %v", v.Name(), err)
+	}
+	daemon.volumes.Dereference(v, ref)
+}
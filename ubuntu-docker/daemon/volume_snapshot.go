@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/volume"
+	volumestore "github.com/docker/docker/volume/store"
+	"github.com/docker/engine-api/types"
+)
+
+// snapshotNameFormat is the format of the time in the name generated for a
+// snapshot created without name.
+const snapshotNameFormat = "20060102T150405Z"
+
+func snapshotToAPIType(s volume.Snapshot) types.VolumeSnapshot {
+	ts := types.VolumeSnapshot{Name: s.Name}
+	if !s.CreatedAt.IsZero() {
+		ts.CreatedAt = s.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return ts
+}
+
+// snapshotError returns the error of a snapshot operation on the volume
+// name, with the status of the API error it should be reported as.
+func snapshotError(err error, name string) error {
+	switch {
+	case volumestore.IsSnapshotsNotSupported(err):
+		return errors.NewBadRequestError(fmt.Errorf("Snapshots are not supported by the driver of volume %s", name))
+	case volumestore.IsInUse(err):
+		return errors.NewRequestConflictError(fmt.Errorf("Unable to restore volume, volume still in use: %v", err))
+	}
+	return err
+}
+
+// VolumeSnapshotCreate takes a snapshot of the volume name. A name based on
+// the current time is generated for the snapshot if snapshot is empty.
+func (daemon *Daemon) VolumeSnapshotCreate(name, snapshot string) (*types.VolumeSnapshot, error) {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == "" {
+		snapshot = time.Now().UTC().Format(snapshotNameFormat)
+	}
+	if err := daemon.volumes.Snapshot(v, snapshot); err != nil {
+		return nil, snapshotError(err, name)
+	}
+	daemon.LogVolumeEvent(v.Name(), "snapshot", map[string]string{"driver": v.DriverName(), "snapshot": snapshot})
+
+	// the driver knows the creation time of the snapshot
+	ls, err := daemon.volumes.ListSnapshots(v)
+	if err == nil {
+		for _, s := range ls {
+			if s.Name == snapshot {
+				ts := snapshotToAPIType(s)
+				return &ts, nil
+			}
+		}
+	}
+	return &types.VolumeSnapshot{Name: snapshot}, nil
+}
+
+// VolumeSnapshotList returns the snapshots of the volume name.
+func (daemon *Daemon) VolumeSnapshotList(name string) ([]types.VolumeSnapshot, error) {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ls, err := daemon.volumes.ListSnapshots(v)
+	if err != nil {
+		return nil, snapshotError(err, name)
+	}
+	snapshots := []types.VolumeSnapshot{}
+	for _, s := range ls {
+		snapshots = append(snapshots, snapshotToAPIType(s))
+	}
+	return snapshots, nil
+}
+
+// VolumeSnapshotRestore reverts the data of the volume name to the snapshot.
+// A volume mounted by a running container is not restored.
+func (daemon *Daemon) VolumeSnapshotRestore(name, snapshot string) error {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := daemon.volumes.RestoreSnapshot(v, snapshot); err != nil {
+		return snapshotError(err, name)
+	}
+	daemon.volumeSizes.forget(v.Name())
+	daemon.LogVolumeEvent(v.Name(), "restore", map[string]string{"driver": v.DriverName(), "snapshot": snapshot})
+	return nil
+}
+
+// VolumeSnapshotRemove removes the snapshot of the volume name.
+func (daemon *Daemon) VolumeSnapshotRemove(name, snapshot string) error {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := daemon.volumes.DeleteSnapshot(v, snapshot); err != nil {
+		return snapshotError(err, name)
+	}
+	return nil
+}
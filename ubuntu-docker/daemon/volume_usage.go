@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+)
+
+// volumeSizeCacheTime is the time the disk usage computed for a volume is
+// reused, as walking the volume is expensive.
+var volumeSizeCacheTime = time.Minute
+
+// volumeSizeCache caches the disk usage of the local volumes.
+type volumeSizeCache struct {
+	sync.Mutex
+	sizes map[string]volumeSize
+}
+
+type volumeSize struct {
+	size       int64
+	computedAt time.Time
+}
+
+// volumeUsageData returns the disk usage and the reference count of a
+// volume. The disk usage is only computed for the volumes of the local
+// driver, and cached.
+func (daemon *Daemon) volumeUsageData(v volume.Volume) (*types.VolumeUsageData, error) {
+	usage := &types.VolumeUsageData{
+		Size:     -1,
+		RefCount: len(daemon.volumes.Refs(v)),
+	}
+	if v.DriverName() != volume.DefaultDriverName {
+		return usage, nil
+	}
+
+	size, err := daemon.volumeSizes.get(v.Name(), v.Path(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	usage.Size = size
+	return usage, nil
+}
+
+// get returns the size of the directory path of the volume name, computed
+// at most volumeSizeCacheTime before now.
+func (c *volumeSizeCache) get(name, path string, now time.Time) (int64, error) {
+	c.Lock()
+	cached, ok := c.sizes[name]
+	c.Unlock()
+	if ok && now.Sub(cached.computedAt) < volumeSizeCacheTime {
+		return cached.size, nil
+	}
+
+	size, err := directory.Size(path)
+	if err != nil {
+		return 0, err
+	}
+
+	c.Lock()
+	if c.sizes == nil {
+		c.sizes = make(map[string]volumeSize)
+	}
+	c.sizes[name] = volumeSize{size: size, computedAt: now}
+	c.Unlock()
+	return size, nil
+}
+
+// forget removes the cached size of the volume name.
+func (c *volumeSizeCache) forget(name string) {
+	c.Lock()
+	delete(c.sizes, name)
+	c.Unlock()
+}
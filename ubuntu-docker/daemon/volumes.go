@@ -6,11 +6,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/volume"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 var (
@@ -34,6 +37,15 @@ func volumeToAPIType(v volume.Volume) *types.Volume {
 	if v, ok := v.(volume.ScopedVolume); ok {
 		tv.Scope = v.Scope()
 	}
+
+	if v, ok := v.(volume.TimestampedVolume); ok {
+		if t := v.CreatedAt(); !t.IsZero() {
+			tv.CreatedAt = t.Format(time.RFC3339Nano)
+		}
+		if t := v.LastUsed(); !t.IsZero() {
+			tv.LastUsed = t.Format(time.RFC3339Nano)
+		}
+	}
 	return tv
 }
 
@@ -65,7 +77,8 @@ func (m mounts) parts(i int) int {
 // 1. Select the previously configured mount points for the containers, if any.
 // 2. Select the volumes mounted from another containers. Overrides previously configured mount point destination.
 // 3. Select the bind mounts set by the client. Overrides previously configured mount point destinations.
-// 4. Cleanup old volumes that are about to be reassigned.
+// 4. Select the mounts of the mount specs set by the client. Overrides previously configured mount point destinations.
+// 5. Cleanup old volumes that are about to be reassigned.
 func (daemon *Daemon) registerMountPoints(container *container.Container, hostConfig *containertypes.HostConfig) (retErr error) {
 	binds := map[string]bool{}
 	mountPoints := map[string]*volume.MountPoint{}
@@ -107,6 +120,7 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 				Destination: m.Destination,
 				Propagation: m.Propagation,
 				Named:       m.Named,
+				Subpath:     m.Subpath,
 			}
 
 			if len(cp.Source) == 0 {
@@ -154,9 +168,47 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 		mountPoints[bind.Destination] = bind
 	}
 
+	// 4. Read the mount specs
+	for _, cfg := range hostConfig.Mounts {
+		mp, err := mountPointFromSpec(cfg, hostConfig.VolumeDriver)
+		if err != nil {
+			return err
+		}
+
+		_, tmpfsExists := hostConfig.Tmpfs[mp.Destination]
+		if binds[mp.Destination] || tmpfsExists {
+			return fmt.Errorf("Duplicate mount point '%s'", mp.Destination)
+		}
+
+		if cfg.Type == mounttypes.TypeVolume {
+			var driverOpts, labels map[string]string
+			if cfg.VolumeOptions != nil {
+				labels = cfg.VolumeOptions.Labels
+				if cfg.VolumeOptions.DriverConfig != nil {
+					driverOpts = cfg.VolumeOptions.DriverConfig.Options
+				}
+			}
+
+			name := mp.Name
+			if name == "" {
+				name = stringid.GenerateNonCryptoID()
+			}
+			v, err := daemon.volumes.CreateWithRef(name, mp.Driver, container.ID, driverOpts, labels)
+			if err != nil {
+				return err
+			}
+			mp.Volume = v
+			mp.Name = v.Name()
+			mp.Driver = v.DriverName()
+		}
+
+		binds[mp.Destination] = true
+		mountPoints[mp.Destination] = mp
+	}
+
 	container.Lock()
 
-	// 4. Cleanup old volumes that are about to be reassigned.
+	// 5. Cleanup old volumes that are about to be reassigned.
 	for _, m := range mountPoints {
 		if m.BackwardsCompatible() {
 			if mp, exists := container.MountPoints[m.Destination]; exists && mp.Volume != nil {
@@ -171,6 +223,74 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 	return nil
 }
 
+// mountPointFromSpec validates the mount spec cfg and returns the mount point
+// it describes. The volume of a volume mount is not created.
+func mountPointFromSpec(cfg mounttypes.Mount, volumeDriver string) (*volume.MountPoint, error) {
+	if !filepath.IsAbs(cfg.Target) {
+		return nil, fmt.Errorf("invalid mount target %q: must be an absolute path", cfg.Target)
+	}
+
+	mp := &volume.MountPoint{
+		Destination: filepath.Clean(cfg.Target),
+		RW:          !cfg.ReadOnly,
+		Propagation: volume.DefaultPropagationMode,
+		CopyData:    volume.DefaultCopyMode,
+	}
+
+	switch cfg.Type {
+	case mounttypes.TypeBind:
+		if cfg.VolumeOptions != nil {
+			return nil, fmt.Errorf("volume options are not allowed with mount type %q", cfg.Type)
+		}
+		if !filepath.IsAbs(cfg.Source) {
+			return nil, fmt.Errorf("invalid bind mount source %q: must be an absolute path", cfg.Source)
+		}
+		mp.Source = filepath.Clean(cfg.Source)
+		mp.CopyData = false
+		if cfg.BindOptions != nil && cfg.BindOptions.Propagation != "" {
+			if !volume.HasPropagation(string(cfg.BindOptions.Propagation)) {
+				return nil, fmt.Errorf("invalid bind mount propagation %q", cfg.BindOptions.Propagation)
+			}
+			mp.Propagation = string(cfg.BindOptions.Propagation)
+		}
+	case mounttypes.TypeVolume:
+		if cfg.BindOptions != nil {
+			return nil, fmt.Errorf("bind options are not allowed with mount type %q", cfg.Type)
+		}
+		mp.Name = cfg.Source
+		mp.Named = cfg.Source != ""
+		mp.Driver = volumeDriver
+		if opts := cfg.VolumeOptions; opts != nil {
+			if opts.NoCopy {
+				mp.CopyData = false
+			}
+			if opts.DriverConfig != nil && opts.DriverConfig.Name != "" {
+				mp.Driver = opts.DriverConfig.Name
+			}
+			if opts.Subpath != "" {
+				if cfg.Source == "" {
+					return nil, fmt.Errorf("volume subpath %q requires a named volume", opts.Subpath)
+				}
+				if err := volume.ValidateSubpath(opts.Subpath); err != nil {
+					return nil, err
+				}
+				if subpath := filepath.Clean(opts.Subpath); subpath != "." {
+					mp.Subpath = subpath
+					// The content of the image is never copied into a
+					// directory of an existing volume.
+					mp.CopyData = false
+				}
+			}
+		}
+		if mp.Driver == "" {
+			mp.Driver = volume.DefaultDriverName
+		}
+	default:
+		return nil, fmt.Errorf("invalid mount type %q", cfg.Type)
+	}
+	return mp, nil
+}
+
 // lazyInitializeVolume initializes a mountpoint's volume if needed.
 // This happens after a daemon restart.
 func (daemon *Daemon) lazyInitializeVolume(containerID string, m *volume.MountPoint) error {
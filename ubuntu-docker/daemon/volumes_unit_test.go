@@ -1,9 +1,11 @@
 package daemon
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/volume"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 func TestParseVolumesFrom(t *testing.T) {
@@ -37,3 +39,51 @@ func TestParseVolumesFrom(t *testing.T) {
 		}
 	}
 }
+
+func TestMountPointFromSpec(t *testing.T) {
+	mp, err := mountPointFromSpec(mounttypes.Mount{
+		Type:          mounttypes.TypeVolume,
+		Source:        "data",
+		Target:        "/etc/app/",
+		ReadOnly:      true,
+		VolumeOptions: &mounttypes.VolumeOptions{Subpath: "config/"},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mp.Name != "data" || mp.Destination != "/etc/app" || mp.RW || mp.Subpath != "config" || mp.Driver != volume.DefaultDriverName || mp.CopyData {
+		t.Fatalf("Unexpected mount point %+v", mp)
+	}
+
+	mp, err = mountPointFromSpec(mounttypes.Mount{
+		Type:          mounttypes.TypeVolume,
+		Source:        "data",
+		Target:        "/etc/app",
+		VolumeOptions: &mounttypes.VolumeOptions{Subpath: "."},
+	}, "custom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mp.Subpath != "" || mp.Driver != "custom" || !mp.RW || !mp.CopyData {
+		t.Fatalf("Unexpected mount point %+v", mp)
+	}
+
+	invalid := map[string]mounttypes.Mount{
+		"invalid mount target": {Type: mounttypes.TypeVolume, Source: "data", Target: "etc"},
+		"invalid mount type":   {Type: "tmpfs", Target: "/etc"},
+		"requires a named volume": {
+			Type: mounttypes.TypeVolume, Target: "/etc",
+			VolumeOptions: &mounttypes.VolumeOptions{Subpath: "config"},
+		},
+		"invalid volume subpath": {
+			Type: mounttypes.TypeVolume, Source: "data", Target: "/etc",
+			VolumeOptions: &mounttypes.VolumeOptions{Subpath: "../config"},
+		},
+		"invalid bind mount source": {Type: mounttypes.TypeBind, Source: "data", Target: "/etc"},
+	}
+	for expected, cfg := range invalid {
+		if _, err := mountPointFromSpec(cfg, ""); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected an error with %q for %+v, got %v", expected, cfg, err)
+		}
+	}
+}
@@ -21,6 +21,10 @@ func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, er
 	for _, m := range c.TmpfsMounts() {
 		tmpfsMounts[m.Destination] = true
 	}
+	subpathDir, err := c.VolumeSubpathDir()
+	if err != nil {
+		return nil, err
+	}
 	for _, m := range c.MountPoints {
 		if tmpfsMounts[m.Destination] {
 			continue
@@ -28,7 +32,7 @@ func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, er
 		if err := daemon.lazyInitializeVolume(c.ID, m); err != nil {
 			return nil, err
 		}
-		path, err := m.Setup(c.MountLabel)
+		path, err := m.Setup(c.MountLabel, subpathDir)
 		if err != nil {
 			return nil, err
 		}
@@ -25,6 +25,9 @@ func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, er
 		if err := daemon.lazyInitializeVolume(c.ID, mount); err != nil {
 			return nil, err
 		}
+		if mount.Subpath != "" {
+			return nil, fmt.Errorf("volume subpaths are not supported on Windows")
+		}
 		// If there is no source, take it from the volume path
 		s := mount.Source
 		if s == "" && mount.Volume != nil {
@@ -0,0 +1,303 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	distreference "github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// ManifestListEntry is a manifest referenced from a manifest list.
+type ManifestListEntry struct {
+	manifestlist.ManifestDescriptor
+
+	// Repository is the full name of the repository holding the manifest.
+	Repository string `json:"repository"`
+	// Blobs are the blobs the manifest references. They are mounted in
+	// the repository of the manifest list, with the manifest, when it is
+	// another repository.
+	Blobs []distribution.Descriptor `json:"blobs,omitempty"`
+}
+
+// ManifestListConfig stores the configuration used to fetch manifests and
+// push manifest lists.
+type ManifestListConfig struct {
+	// MetaHeaders stores HTTP headers with metadata about the image
+	MetaHeaders map[string][]string
+	// AuthConfig holds authentication credentials for authenticating with
+	// the registry.
+	AuthConfig *types.AuthConfig
+	// RegistryService is the registry service to use for TLS configuration
+	// and endpoint lookup.
+	RegistryService registry.Service
+}
+
+// GetManifestListEntry fetches the manifest of ref, so that it can be
+// referenced from a manifest list. The platform of the entry is read from
+// the image configuration.
+func GetManifestListEntry(ctx context.Context, ref reference.Named, config *ManifestListConfig) (ManifestListEntry, error) {
+	var entry ManifestListEntry
+	err := withV2Repository(ctx, ref, config, []string{"pull"}, func(repo distribution.Repository) error {
+		var err error
+		entry, err = fetchManifestListEntry(ctx, repo, ref)
+		return err
+	})
+	return entry, err
+}
+
+func fetchManifestListEntry(ctx context.Context, repo distribution.Repository, ref reference.Named) (ManifestListEntry, error) {
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return ManifestListEntry{}, err
+	}
+
+	var manifest distribution.Manifest
+	if canonical, ok := ref.(reference.Canonical); ok {
+		manifest, err = manSvc.Get(ctx, canonical.Digest())
+	} else {
+		tag := reference.DefaultTag
+		if tagged, ok := ref.(reference.NamedTagged); ok {
+			tag = tagged.Tag()
+		}
+		manifest, err = manSvc.Get(ctx, "", distribution.WithTag(tag))
+	}
+	if err != nil {
+		return ManifestListEntry{}, err
+	}
+
+	mediaType, payload, err := manifest.Payload()
+	if err != nil {
+		return ManifestListEntry{}, err
+	}
+	if m, ok := manifest.(*schema1.SignedManifest); ok {
+		// The payload of a schema1 manifest includes its signatures,
+		// registries address it by the digest of its canonical form.
+		payload = m.Canonical
+	}
+	entry := ManifestListEntry{Repository: ref.FullName()}
+	entry.MediaType = mediaType
+	entry.Size = int64(len(payload))
+	entry.Digest = digest.FromBytes(payload)
+	if canonical, ok := ref.(reference.Canonical); ok && canonical.Digest() != entry.Digest {
+		return ManifestListEntry{}, fmt.Errorf("manifest verification failed for digest %s", canonical.Digest())
+	}
+
+	switch m := manifest.(type) {
+	case *schema2.DeserializedManifest:
+		configJSON, err := repo.Blobs(ctx).Get(ctx, m.Config.Digest)
+		if err != nil {
+			return ManifestListEntry{}, err
+		}
+		var platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		}
+		if err := json.Unmarshal(configJSON, &platform); err != nil {
+			return ManifestListEntry{}, err
+		}
+		entry.Platform.Architecture = platform.Architecture
+		entry.Platform.OS = platform.OS
+		entry.Blobs = append([]distribution.Descriptor{m.Config}, m.Layers...)
+	case *schema1.SignedManifest:
+		entry.Platform.Architecture = m.Architecture
+		// Schema1 manifests don't record the OS, it is read from the
+		// configuration of the top layer. Images that don't record it
+		// either must be annotated with their OS before the push.
+		if len(m.History) > 0 {
+			var v1Compat struct {
+				OS string `json:"os"`
+			}
+			if err := json.Unmarshal([]byte(m.History[0].V1Compatibility), &v1Compat); err != nil {
+				return ManifestListEntry{}, err
+			}
+			entry.Platform.OS = v1Compat.OS
+		}
+		entry.Blobs = m.References()
+	case *manifestlist.DeserializedManifestList:
+		return ManifestListEntry{}, fmt.Errorf("%s is a manifest list", ref.String())
+	default:
+		return ManifestListEntry{}, fmt.Errorf("unsupported manifest format for %s", ref.String())
+	}
+	return entry, nil
+}
+
+// PushManifestList assembles a manifest list referencing the manifests of
+// entries and pushes it as ref. The manifests must be stored in the registry
+// of ref. Those of other repositories are copied, with their blobs, to the
+// repository of ref first.
+func PushManifestList(ctx context.Context, ref reference.NamedTagged, entries []ManifestListEntry, config *ManifestListConfig) (digest.Digest, error) {
+	return pushManifestList(ctx, ref, entries, func(ref reference.Named, actions []string, fn func(distribution.Repository) error) error {
+		return withV2Repository(ctx, ref, config, actions, fn)
+	})
+}
+
+// repositoryFunc calls fn with the repository of ref, authorized for actions.
+type repositoryFunc func(ref reference.Named, actions []string, fn func(distribution.Repository) error) error
+
+func pushManifestList(ctx context.Context, ref reference.NamedTagged, entries []ManifestListEntry, withRepository repositoryFunc) (digest.Digest, error) {
+	descriptors := make([]manifestlist.ManifestDescriptor, 0, len(entries))
+	for _, e := range entries {
+		source, err := reference.ParseNamed(e.Repository)
+		if err != nil {
+			return "", err
+		}
+		if source.Hostname() != ref.Hostname() {
+			return "", fmt.Errorf("cannot reference %s@%s from %s, manifests must be in the same registry as the manifest list", e.Repository, e.Digest, ref.String())
+		}
+		if e.MediaType == schema1.MediaTypeSignedManifest && e.Repository != ref.FullName() {
+			return "", fmt.Errorf("cannot copy the schema1 manifest %s@%s to %s, it is signed for its repository", e.Repository, e.Digest, ref.FullName())
+		}
+		descriptors = append(descriptors, e.ManifestDescriptor)
+	}
+	list, err := manifestlist.FromDescriptors(descriptors)
+	if err != nil {
+		return "", err
+	}
+
+	var dgst digest.Digest
+	err = withRepository(ref, []string{"push", "pull"}, func(repo distribution.Repository) error {
+		for _, e := range entries {
+			if e.Repository == ref.FullName() {
+				continue
+			}
+			if err := copyManifest(ctx, repo, e, withRepository); err != nil {
+				return err
+			}
+		}
+
+		manSvc, err := repo.Manifests(ctx)
+		if err != nil {
+			return err
+		}
+		dgst, err = manSvc.Put(ctx, list, distribution.WithTag(ref.Tag()))
+		return err
+	})
+	return dgst, err
+}
+
+// copyManifest copies the manifest of entry and its blobs to repo, mounting
+// the blobs from the repository of the entry when the registry allows it.
+func copyManifest(ctx context.Context, repo distribution.Repository, entry ManifestListEntry, withRepository repositoryFunc) error {
+	source, err := reference.ParseNamed(entry.Repository)
+	if err != nil {
+		return err
+	}
+
+	return withRepository(source, []string{"pull"}, func(sourceRepo distribution.Repository) error {
+		bs := repo.Blobs(ctx)
+		for _, blob := range entry.Blobs {
+			if _, err := bs.Stat(ctx, blob.Digest); err == nil {
+				continue
+			}
+			if err := copyBlob(ctx, bs, sourceRepo, source, blob); err != nil {
+				return err
+			}
+		}
+
+		sourceManSvc, err := sourceRepo.Manifests(ctx)
+		if err != nil {
+			return err
+		}
+		manifest, err := sourceManSvc.Get(ctx, entry.Digest)
+		if err != nil {
+			return err
+		}
+		manSvc, err := repo.Manifests(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = manSvc.Put(ctx, manifest)
+		return err
+	})
+}
+
+func copyBlob(ctx context.Context, bs distribution.BlobStore, sourceRepo distribution.Repository, source reference.Named, blob distribution.Descriptor) error {
+	// Mounts take the remote name of the source repository, without its
+	// registry hostname.
+	remoteRef, err := distreference.WithName(source.RemoteName())
+	if err != nil {
+		return err
+	}
+	canonicalRef, err := distreference.WithDigest(remoteRef, blob.Digest)
+	if err != nil {
+		return err
+	}
+
+	upload, err := bs.Create(ctx, client.WithMountFrom(canonicalRef))
+	switch err.(type) {
+	case distribution.ErrBlobMounted:
+		logrus.Debugf("Mounted %s from %s", blob.Digest, source.FullName())
+		return nil
+	case nil:
+	default:
+		return err
+	}
+	defer upload.Close()
+
+	rc, err := sourceRepo.Blobs(ctx).Open(ctx, blob.Digest)
+	if err != nil {
+		upload.Cancel(ctx)
+		return err
+	}
+	defer rc.Close()
+
+	if _, err := io.Copy(upload, rc); err != nil {
+		upload.Cancel(ctx)
+		return err
+	}
+	_, err = upload.Commit(ctx, blob)
+	return err
+}
+
+// withV2Repository calls fn with the repository of ref, trying the v2
+// endpoints of its registry in turn.
+func withV2Repository(ctx context.Context, ref reference.Named, config *ManifestListConfig, actions []string, fn func(distribution.Repository) error) error {
+	repoInfo, err := config.RegistryService.ResolveRepository(ref)
+	if err != nil {
+		return err
+	}
+	authConfig, err := config.RegistryService.LookupAuthConfig(config.AuthConfig, repoInfo.Index)
+	if err != nil {
+		return err
+	}
+	// Push endpoints don't include mirrors, the manifests of a list must
+	// come from the registry itself.
+	endpoints, err := config.RegistryService.LookupPushEndpoints(repoInfo.Hostname())
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, endpoint := range endpoints {
+		if endpoint.Version == registry.APIVersion1 {
+			continue
+		}
+		logrus.Debugf("Trying %s on %s", repoInfo.FullName(), endpoint.URL)
+		repo, _, err := NewV2Repository(ctx, repoInfo, endpoint, http.Header(config.MetaHeaders), authConfig, actions...)
+		if err != nil {
+			if fallbackErr, ok := err.(fallbackError); ok {
+				lastErr = fallbackErr.err
+				continue
+			}
+			return err
+		}
+		return fn(repo)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no v2 endpoint found for %s", repoInfo.Hostname())
+	}
+	return lastErr
+}
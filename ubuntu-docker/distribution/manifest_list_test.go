@@ -0,0 +1,434 @@
+package distribution
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/docker/distribution"
+	dcontext "github.com/docker/distribution/context"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/reference"
+	"github.com/docker/libtrust"
+)
+
+type testManifestService struct {
+	distribution.ManifestService
+	manifests map[string]distribution.Manifest
+}
+
+func (ms *testManifestService) Get(ctx dcontext.Context, dgst digest.Digest, options ...distribution.ManifestServiceOption) (distribution.Manifest, error) {
+	key := dgst.String()
+	for _, o := range options {
+		if tag, ok := o.(distribution.WithTagOption); ok {
+			key = tag.Tag
+		}
+	}
+	m, ok := ms.manifests[key]
+	if !ok {
+		return nil, distribution.ErrManifestUnknownRevision{Revision: dgst}
+	}
+	return m, nil
+}
+
+func (ms *testManifestService) Put(ctx dcontext.Context, m distribution.Manifest, options ...distribution.ManifestServiceOption) (digest.Digest, error) {
+	_, payload, err := m.Payload()
+	if err != nil {
+		return "", err
+	}
+	dgst := digest.FromBytes(payload)
+	ms.manifests[dgst.String()] = m
+	for _, o := range options {
+		if tag, ok := o.(distribution.WithTagOption); ok {
+			ms.manifests[tag.Tag] = m
+		}
+	}
+	return dgst, nil
+}
+
+type testConfigStore struct {
+	distribution.BlobStore
+	blobs map[digest.Digest][]byte
+}
+
+func (bs *testConfigStore) Get(ctx dcontext.Context, dgst digest.Digest) ([]byte, error) {
+	b, ok := bs.blobs[dgst]
+	if !ok {
+		return nil, distribution.ErrBlobUnknown
+	}
+	return b, nil
+}
+
+type testManifestRepository struct {
+	distribution.Repository
+	manifests *testManifestService
+	blobs     distribution.BlobStore
+}
+
+func (r testManifestRepository) Manifests(ctx dcontext.Context, options ...distribution.ManifestServiceOption) (distribution.ManifestService, error) {
+	return r.manifests, nil
+}
+
+func (r testManifestRepository) Blobs(ctx dcontext.Context) distribution.BlobStore {
+	return r.blobs
+}
+
+func TestFetchManifestListEntry(t *testing.T) {
+	config := []byte(`{"architecture":"arm64","os":"linux","rootfs":{"type":"layers"}}`)
+	configDesc := distribution.Descriptor{
+		MediaType: schema2.MediaTypeConfig,
+		Size:      int64(len(config)),
+		Digest:    digest.FromBytes(config),
+	}
+	layer := distribution.Descriptor{
+		MediaType: schema2.MediaTypeLayer,
+		Size:      3,
+		Digest:    digest.FromBytes([]byte("foo")),
+	}
+	manifest, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config:    configDesc,
+		Layers:    []distribution.Descriptor{layer},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, payload, err := manifest.Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := manifestlist.FromDescriptors(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := testManifestRepository{
+		manifests: &testManifestService{manifests: map[string]distribution.Manifest{
+			"arm64":                            manifest,
+			digest.FromBytes(payload).String(): manifest,
+			"list":                             list,
+		}},
+		blobs: &testConfigStore{blobs: map[digest.Digest][]byte{configDesc.Digest: config}},
+	}
+
+	for _, name := range []string{"example.com/app:arm64", "example.com/app@" + digest.FromBytes(payload).String()} {
+		ref, err := reference.ParseNamed(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := fetchManifestListEntry(dcontext.Background(), repo, ref)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if entry.Digest != digest.FromBytes(payload) || entry.Size != int64(len(payload)) || entry.MediaType != schema2.MediaTypeManifest {
+			t.Fatalf("%s: unexpected descriptor %+v", name, entry.Descriptor)
+		}
+		if entry.Platform.Architecture != "arm64" || entry.Platform.OS != "linux" {
+			t.Fatalf("%s: unexpected platform %+v", name, entry.Platform)
+		}
+		if entry.Repository != "example.com/app" {
+			t.Fatalf("%s: unexpected repository %s", name, entry.Repository)
+		}
+		if len(entry.Blobs) != 2 || entry.Blobs[0].Digest != configDesc.Digest || entry.Blobs[1].Digest != layer.Digest {
+			t.Fatalf("%s: unexpected blobs %+v", name, entry.Blobs)
+		}
+	}
+
+	ref, _ := reference.ParseNamed("example.com/app:list")
+	if _, err := fetchManifestListEntry(dcontext.Background(), repo, ref); err == nil {
+		t.Fatal("expected an error for a manifest list")
+	}
+}
+
+func TestFetchManifestListEntrySchema1(t *testing.T) {
+	key, err := libtrust.GenerateECP256PrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := digest.FromBytes([]byte("foo"))
+	sign := func(v1Compat string) *schema1.SignedManifest {
+		m, err := schema1.Sign(&schema1.Manifest{
+			Versioned:    manifest.Versioned{SchemaVersion: 1},
+			Name:         "app",
+			Tag:          "latest",
+			Architecture: "arm",
+			FSLayers:     []schema1.FSLayer{{BlobSum: layer}},
+			History:      []schema1.History{{V1Compatibility: v1Compat}},
+		}, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	withOS := sign(`{"id":"a","architecture":"arm","os":"windows"}`)
+	withoutOS := sign(`{"id":"a"}`)
+
+	repo := testManifestRepository{
+		manifests: &testManifestService{manifests: map[string]distribution.Manifest{
+			"windows": withOS,
+			"unknown": withoutOS,
+			digest.FromBytes(withOS.Canonical).String(): withOS,
+		}},
+	}
+
+	for _, name := range []string{"example.com/app:windows", "example.com/app@" + digest.FromBytes(withOS.Canonical).String()} {
+		ref, err := reference.ParseNamed(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := fetchManifestListEntry(dcontext.Background(), repo, ref)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// The descriptor is that of the canonical manifest, without
+		// the signatures.
+		_, payload, _ := withOS.Payload()
+		if bytes.Equal(payload, withOS.Canonical) {
+			t.Fatal("expected the payload to include the signatures")
+		}
+		if entry.Digest != digest.FromBytes(withOS.Canonical) || entry.Size != int64(len(withOS.Canonical)) || entry.MediaType != schema1.MediaTypeSignedManifest {
+			t.Fatalf("%s: unexpected descriptor %+v", name, entry.Descriptor)
+		}
+		if entry.Platform.Architecture != "arm" || entry.Platform.OS != "windows" {
+			t.Fatalf("%s: unexpected platform %+v", name, entry.Platform)
+		}
+		if len(entry.Blobs) != 1 || entry.Blobs[0].Digest != layer {
+			t.Fatalf("%s: unexpected blobs %+v", name, entry.Blobs)
+		}
+	}
+
+	// The OS is left to be annotated when the image doesn't record it.
+	ref, _ := reference.ParseNamed("example.com/app:unknown")
+	entry, err := fetchManifestListEntry(dcontext.Background(), repo, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Platform.OS != "" {
+		t.Fatalf("expected no OS, got %s", entry.Platform.OS)
+	}
+}
+
+type testBlobWriter struct {
+	distribution.BlobWriter
+	bs  *testPushBlobStore
+	buf bytes.Buffer
+}
+
+func (w *testBlobWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *testBlobWriter) Close() error {
+	return nil
+}
+
+func (w *testBlobWriter) Cancel(ctx dcontext.Context) error {
+	w.bs.canceled++
+	return nil
+}
+
+func (w *testBlobWriter) Commit(ctx dcontext.Context, provisional distribution.Descriptor) (distribution.Descriptor, error) {
+	if digest.FromBytes(w.buf.Bytes()) != provisional.Digest {
+		return distribution.Descriptor{}, fmt.Errorf("digest mismatch for %s", provisional.Digest)
+	}
+	w.bs.blobs[provisional.Digest] = w.buf.Bytes()
+	return provisional, nil
+}
+
+// testPushBlobStore stores blobs in memory. Blob uploads are mounted when
+// mount is set.
+type testPushBlobStore struct {
+	distribution.BlobStore
+	blobs    map[digest.Digest][]byte
+	mount    bool
+	mounted  int
+	canceled int
+}
+
+func (bs *testPushBlobStore) Stat(ctx dcontext.Context, dgst digest.Digest) (distribution.Descriptor, error) {
+	b, ok := bs.blobs[dgst]
+	if !ok {
+		return distribution.Descriptor{}, distribution.ErrBlobUnknown
+	}
+	return distribution.Descriptor{Digest: dgst, Size: int64(len(b))}, nil
+}
+
+func (bs *testPushBlobStore) Get(ctx dcontext.Context, dgst digest.Digest) ([]byte, error) {
+	b, ok := bs.blobs[dgst]
+	if !ok {
+		return nil, distribution.ErrBlobUnknown
+	}
+	return b, nil
+}
+
+func (bs *testPushBlobStore) Open(ctx dcontext.Context, dgst digest.Digest) (distribution.ReadSeekCloser, error) {
+	b, ok := bs.blobs[dgst]
+	if !ok {
+		return nil, distribution.ErrBlobUnknown
+	}
+	return testBlobReader{Reader: bytes.NewReader(b), offsets: new([]int64)}, nil
+}
+
+func (bs *testPushBlobStore) Create(ctx dcontext.Context, options ...distribution.BlobCreateOption) (distribution.BlobWriter, error) {
+	if bs.mount {
+		bs.mounted++
+		return nil, distribution.ErrBlobMounted{}
+	}
+	return &testBlobWriter{bs: bs}, nil
+}
+
+func testSchema2Manifest(t *testing.T, blobs map[digest.Digest][]byte, arch string) (ManifestListEntry, *schema2.DeserializedManifest) {
+	config := []byte(`{"architecture":"` + arch + `","os":"linux"}`)
+	layer := []byte("layer for " + arch)
+	configDesc := distribution.Descriptor{MediaType: schema2.MediaTypeConfig, Size: int64(len(config)), Digest: digest.FromBytes(config)}
+	layerDesc := distribution.Descriptor{MediaType: schema2.MediaTypeLayer, Size: int64(len(layer)), Digest: digest.FromBytes(layer)}
+	blobs[configDesc.Digest] = config
+	blobs[layerDesc.Digest] = layer
+
+	m, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config:    configDesc,
+		Layers:    []distribution.Descriptor{layerDesc},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, payload, err := m.Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry ManifestListEntry
+	entry.MediaType = schema2.MediaTypeManifest
+	entry.Size = int64(len(payload))
+	entry.Digest = digest.FromBytes(payload)
+	entry.Platform.Architecture = arch
+	entry.Platform.OS = "linux"
+	entry.Blobs = []distribution.Descriptor{configDesc, layerDesc}
+	return entry, m
+}
+
+func testPushRepositories(t *testing.T, mount bool) (map[string]testManifestRepository, []ManifestListEntry) {
+	target := &testPushBlobStore{blobs: make(map[digest.Digest][]byte), mount: mount}
+	source := &testPushBlobStore{blobs: make(map[digest.Digest][]byte)}
+
+	local, localManifest := testSchema2Manifest(t, target.blobs, "amd64")
+	local.Repository = "example.com/app"
+	other, otherManifest := testSchema2Manifest(t, source.blobs, "arm64")
+	other.Repository = "example.com/other"
+
+	repos := map[string]testManifestRepository{
+		"example.com/app": {
+			manifests: &testManifestService{manifests: map[string]distribution.Manifest{local.Digest.String(): localManifest}},
+			blobs:     target,
+		},
+		"example.com/other": {
+			manifests: &testManifestService{manifests: map[string]distribution.Manifest{other.Digest.String(): otherManifest}},
+			blobs:     source,
+		},
+	}
+	return repos, []ManifestListEntry{local, other}
+}
+
+func testWithRepository(repos map[string]testManifestRepository) repositoryFunc {
+	return func(ref reference.Named, actions []string, fn func(distribution.Repository) error) error {
+		repo, ok := repos[ref.FullName()]
+		if !ok {
+			return fmt.Errorf("unknown repository %s", ref.FullName())
+		}
+		return fn(repo)
+	}
+}
+
+func TestPushManifestList(t *testing.T) {
+	for _, mount := range []bool{false, true} {
+		repos, entries := testPushRepositories(t, mount)
+		named, _ := reference.ParseNamed("example.com/app:multi")
+		ref := named.(reference.NamedTagged)
+
+		dgst, err := pushManifestList(dcontext.Background(), ref, entries, testWithRepository(repos))
+		if err != nil {
+			t.Fatalf("mount: %v: %v", mount, err)
+		}
+
+		target := repos["example.com/app"]
+		list, ok := target.manifests.manifests["multi"].(*manifestlist.DeserializedManifestList)
+		if !ok {
+			t.Fatalf("mount: %v: expected a manifest list to be pushed as multi", mount)
+		}
+		if _, payload, _ := list.Payload(); digest.FromBytes(payload) != dgst {
+			t.Fatalf("mount: %v: unexpected digest %s", mount, dgst)
+		}
+		if len(list.Manifests) != 2 || list.Manifests[0].Digest != entries[0].Digest || list.Manifests[1].Platform.Architecture != "arm64" {
+			t.Fatalf("mount: %v: unexpected manifests %+v", mount, list.Manifests)
+		}
+
+		// The manifest of the other repository is copied with its
+		// blobs, the blobs are mounted when the registry allows it.
+		if _, ok := target.manifests.manifests[entries[1].Digest.String()]; !ok {
+			t.Fatalf("mount: %v: expected the manifest of example.com/other to be copied", mount)
+		}
+		bs := target.blobs.(*testPushBlobStore)
+		if mount {
+			if bs.mounted != len(entries[1].Blobs) {
+				t.Fatalf("expected %d blobs to be mounted, got %d", len(entries[1].Blobs), bs.mounted)
+			}
+			continue
+		}
+		source := repos["example.com/other"].blobs.(*testPushBlobStore)
+		for _, blob := range entries[1].Blobs {
+			if !bytes.Equal(bs.blobs[blob.Digest], source.blobs[blob.Digest]) {
+				t.Fatalf("expected blob %s to be copied", blob.Digest)
+			}
+		}
+	}
+}
+
+func TestPushManifestListInvalid(t *testing.T) {
+	repos, entries := testPushRepositories(t, false)
+	named, _ := reference.ParseNamed("example.com/app:multi")
+	ref := named.(reference.NamedTagged)
+
+	otherRegistry := entries[1]
+	otherRegistry.Repository = "registry.example.org/other"
+	schema1Entry := entries[1]
+	schema1Entry.MediaType = schema1.MediaTypeSignedManifest
+
+	for _, tc := range []struct {
+		entry    ManifestListEntry
+		expected string
+	}{
+		{otherRegistry, "manifests must be in the same registry as the manifest list"},
+		{schema1Entry, "it is signed for its repository"},
+	} {
+		_, err := pushManifestList(dcontext.Background(), ref, []ManifestListEntry{entries[0], tc.entry}, testWithRepository(repos))
+		if err == nil || !bytes.Contains([]byte(err.Error()), []byte(tc.expected)) {
+			t.Fatalf("expected an error containing %q, got %v", tc.expected, err)
+		}
+	}
+	if _, ok := repos["example.com/app"].manifests.manifests["multi"]; ok {
+		t.Fatal("expected no manifest list to be pushed")
+	}
+}
+
+func TestCopyBlobMissing(t *testing.T) {
+	repos, entries := testPushRepositories(t, false)
+	source := repos["example.com/other"]
+	blob := entries[1].Blobs[1]
+	delete(source.blobs.(*testPushBlobStore).blobs, blob.Digest)
+
+	sourceRef, _ := reference.ParseNamed("example.com/other")
+	bs := repos["example.com/app"].blobs.(*testPushBlobStore)
+	if err := copyBlob(dcontext.Background(), bs, source, sourceRef, blob); err != distribution.ErrBlobUnknown {
+		t.Fatalf("expected ErrBlobUnknown, got %v", err)
+	}
+	if bs.canceled != 1 {
+		t.Fatalf("expected the upload to be canceled, got %d cancellations", bs.canceled)
+	}
+	if _, ok := bs.blobs[blob.Digest]; ok {
+		t.Fatal("expected the blob not to be stored")
+	}
+}
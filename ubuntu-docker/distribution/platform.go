@@ -0,0 +1,95 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/image"
+)
+
+// ParsePlatform parses a platform in the os/arch[/variant] format, like
+// "linux/arm/v7".
+func ParsePlatform(s string) (*manifestlist.PlatformSpec, error) {
+	parts := strings.Split(strings.ToLower(s), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid platform %q, the format is os/arch[/variant]", s)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("invalid platform %q, the format is os/arch[/variant]", s)
+		}
+	}
+	platform := &manifestlist.PlatformSpec{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		platform.Variant = parts[2]
+	}
+	return platform, nil
+}
+
+func formatPlatform(platform manifestlist.PlatformSpec) string {
+	s := platform.OS + "/" + platform.Architecture
+	if platform.Variant != "" {
+		s += "/" + platform.Variant
+	}
+	return s
+}
+
+// defaultPlatform is the platform pulled from manifest lists when none is
+// requested, the platform of the daemon.
+func defaultPlatform() manifestlist.PlatformSpec {
+	return manifestlist.PlatformSpec{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+}
+
+// matchPlatform returns whether an image built for have can be pulled for
+// the platform want. A platform without variant matches all the variants of
+// its architecture.
+func matchPlatform(want, have manifestlist.PlatformSpec) bool {
+	return want.OS == have.OS && want.Architecture == have.Architecture &&
+		(want.Variant == "" || want.Variant == have.Variant)
+}
+
+// checkImagePlatform returns an error if the image configuration records a
+// platform other than want. Configurations without platform are accepted.
+func checkImagePlatform(want manifestlist.PlatformSpec, img *image.Image) error {
+	have := manifestlist.PlatformSpec{OS: img.OS, Architecture: img.Architecture, Variant: img.Variant}
+	if have.OS == "" {
+		have.OS = want.OS
+	}
+	if have.Architecture == "" {
+		have.Architecture = want.Architecture
+	}
+	if have.Variant == "" {
+		have.Variant = want.Variant
+	}
+	if !matchPlatform(want, have) {
+		return fmt.Errorf("image is for platform %s, not %s", formatPlatform(have), formatPlatform(want))
+	}
+	return nil
+}
+
+// setConfigPlatform records platform in the image configuration config.
+func setConfigPlatform(config []byte, platform manifestlist.PlatformSpec) ([]byte, error) {
+	var c map[string]*json.RawMessage
+	if err := json.Unmarshal(config, &c); err != nil {
+		return nil, err
+	}
+	c["os"] = rawJSON(platform.OS)
+	c["architecture"] = rawJSON(platform.Architecture)
+	if platform.Variant != "" {
+		c["variant"] = rawJSON(platform.Variant)
+	} else {
+		delete(c, "variant")
+	}
+	return json.Marshal(c)
+}
+
+func rawJSON(value interface{}) *json.RawMessage {
+	jsonval, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return (*json.RawMessage)(&jsonval)
+}
@@ -0,0 +1,80 @@
+package distribution
+
+import (
+	"testing"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/image"
+)
+
+func TestParsePlatform(t *testing.T) {
+	valid := map[string]manifestlist.PlatformSpec{
+		"linux/amd64":    {OS: "linux", Architecture: "amd64"},
+		"linux/arm/v7":   {OS: "linux", Architecture: "arm", Variant: "v7"},
+		"Windows/AMD64":  {OS: "windows", Architecture: "amd64"},
+		"linux/arm64/v8": {OS: "linux", Architecture: "arm64", Variant: "v8"},
+	}
+	for s, expected := range valid {
+		platform, err := ParsePlatform(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if platform.OS != expected.OS || platform.Architecture != expected.Architecture || platform.Variant != expected.Variant {
+			t.Fatalf("%s: expected %+v, got %+v", s, expected, *platform)
+		}
+	}
+
+	for _, s := range []string{"", "linux", "linux/", "/amd64", "linux//v7", "linux/arm/v7/extra"} {
+		if _, err := ParsePlatform(s); err == nil {
+			t.Fatalf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestMatchPlatform(t *testing.T) {
+	armv7 := manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v7"}
+	if !matchPlatform(manifestlist.PlatformSpec{OS: "linux", Architecture: "arm"}, armv7) {
+		t.Fatal("a platform without variant should match all variants")
+	}
+	if !matchPlatform(armv7, armv7) {
+		t.Fatal("a platform should match itself")
+	}
+	if matchPlatform(manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v6"}, armv7) {
+		t.Fatal("different variants should not match")
+	}
+	if matchPlatform(armv7, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm"}) {
+		t.Fatal("a variant should not match a manifest without variant")
+	}
+	if matchPlatform(manifestlist.PlatformSpec{OS: "windows", Architecture: "arm"}, armv7) {
+		t.Fatal("different operating systems should not match")
+	}
+}
+
+func TestSetConfigPlatform(t *testing.T) {
+	config := []byte(`{"architecture":"amd64","os":"linux","variant":"v1","rootfs":{"type":"layers"}}`)
+	for _, platform := range []manifestlist.PlatformSpec{
+		{OS: "linux", Architecture: "arm", Variant: "v7"},
+		{OS: "linux", Architecture: "arm64"},
+	} {
+		updated, err := setConfigPlatform(config, platform)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := image.NewFromJSON(updated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.OS != platform.OS || img.Architecture != platform.Architecture || img.Variant != platform.Variant {
+			t.Fatalf("expected platform %s, got %s/%s/%s", formatPlatform(platform), img.OS, img.Architecture, img.Variant)
+		}
+		if img.RootFS == nil || img.RootFS.Type != "layers" {
+			t.Fatalf("unexpected rootfs %+v", img.RootFS)
+		}
+		if err := checkImagePlatform(platform, img); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkImagePlatform(manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}, img); err == nil {
+			t.Fatal("expected an error for a different platform")
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
@@ -40,6 +41,12 @@ type ImagePullConfig struct {
 	ReferenceStore reference.Store
 	// DownloadManager manages concurrent pulls.
 	DownloadManager *xfer.LayerDownloadManager
+	// Platform is the platform of the image to pull from manifest lists,
+	// nil for the platform of the daemon.
+	Platform *manifestlist.PlatformSpec
+	// DownloadDir is the directory the layers are downloaded to. Partial
+	// downloads are kept there so that a later pull can resume them.
+	DownloadDir string
 }
 
 // Puller is an interface that abstracts pulling for different API versions.
@@ -89,6 +96,11 @@ func Pull(ctx context.Context, ref reference.Named, imagePullConfig *ImagePullCo
 		return err
 	}
 
+	imagePullConfig.AuthConfig, err = imagePullConfig.RegistryService.LookupAuthConfig(imagePullConfig.AuthConfig, repoInfo.Index)
+	if err != nil {
+		return err
+	}
+
 	endpoints, err := imagePullConfig.RegistryService.LookupPullEndpoints(repoInfo.Hostname())
 	if err != nil {
 		return err
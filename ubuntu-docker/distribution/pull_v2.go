@@ -8,8 +8,11 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/distribution"
@@ -136,7 +139,9 @@ type v2LayerDescriptor struct {
 	repoInfo          *registry.RepositoryInfo
 	repo              distribution.Repository
 	V2MetadataService *metadata.V2MetadataService
+	downloadDir       string
 	tmpFile           *os.File
+	resumable         bool
 	verifier          digest.Verifier
 	src               distribution.Descriptor
 }
@@ -162,26 +167,35 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	)
 
 	if ld.tmpFile == nil {
-		ld.tmpFile, err = createDownloadFile()
+		// A partial download of the blob may be left over from an
+		// earlier pull that failed, or from before a daemon restart.
+		ld.tmpFile, ld.resumable, err = openDownloadFile(ld.downloadDir, ld.digest)
 		if err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
-	} else {
-		offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
-		if err != nil {
-			logrus.Debugf("error seeking to end of download file: %v", err)
-			offset = 0
+		ld.verifier = nil
+	}
 
-			ld.tmpFile.Close()
-			if err := os.Remove(ld.tmpFile.Name()); err != nil {
-				logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
-			}
-			ld.tmpFile, err = createDownloadFile()
-			if err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
+	offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
+	if err != nil {
+		logrus.Debugf("error seeking to end of download file: %v", err)
+		offset = 0
+
+		if err := ld.truncateDownloadFile(); err != nil {
+			return nil, 0, xfer.DoNotRetry{Err: err}
+		}
+	} else if offset != 0 {
+		logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
+		if ld.verifier == nil {
+			// The partial download was not hashed by this descriptor,
+			// hash it now so the full digest can still be verified.
+			if offset, err = ld.hashDownloadFile(); err != nil {
+				logrus.Debugf("error hashing partial download of %q: %v", ld.digest, err)
+				offset = 0
+				if err := ld.truncateDownloadFile(); err != nil {
+					return nil, 0, xfer.DoNotRetry{Err: err}
+				}
 			}
-		} else if offset != 0 {
-			logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
 		}
 	}
 
@@ -239,7 +253,9 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		}
 	}
 
-	_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
+	if size == 0 || offset < size {
+		_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
+	}
 	if err != nil {
 		if err == transport.ErrWrongCodeForByteRange {
 			if err := ld.truncateDownloadFile(); err != nil {
@@ -256,13 +272,13 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		err = fmt.Errorf("filesystem layer verification failed for digest %s", ld.digest)
 		logrus.Error(err)
 
+		// The partial download can't be trusted anymore, discard it
+		// so that it isn't resumed by the next attempt.
+		ld.removeDownloadFile()
+
 		// Allow a retry if this digest verification error happened
 		// after a resumed download.
 		if offset != 0 {
-			if err := ld.truncateDownloadFile(); err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
-
 			return nil, 0, err
 		}
 		return nil, 0, xfer.DoNotRetry{Err: err}
@@ -274,10 +290,7 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 	_, err = tmpFile.Seek(0, os.SEEK_SET)
 	if err != nil {
-		tmpFile.Close()
-		if err := os.Remove(tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
+		closeDownloadFile(tmpFile, true)
 		ld.tmpFile = nil
 		ld.verifier = nil
 		return nil, 0, xfer.DoNotRetry{Err: err}
@@ -288,22 +301,40 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	ld.tmpFile = nil
 
 	return ioutils.NewReadCloserWrapper(tmpFile, func() error {
-		tmpFile.Close()
-		err := os.RemoveAll(tmpFile.Name())
-		if err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
-		return err
+		return closeDownloadFile(tmpFile, true)
 	}), size, nil
 }
 
 func (ld *v2LayerDescriptor) Close() {
 	if ld.tmpFile != nil {
-		ld.tmpFile.Close()
-		if err := os.RemoveAll(ld.tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
+		// Keep the partial download around, so that a later pull of
+		// the same blob can resume it.
+		keep := false
+		if fi, err := ld.tmpFile.Stat(); err == nil && fi.Size() != 0 {
+			keep = ld.resumable
 		}
+		closeDownloadFile(ld.tmpFile, !keep)
+		ld.tmpFile = nil
+		ld.verifier = nil
+	}
+}
+
+// hashDownloadFile feeds the content of a partial download to a new verifier
+// and returns the size of the partial download.
+func (ld *v2LayerDescriptor) hashDownloadFile() (int64, error) {
+	verifier, err := digest.NewDigestVerifier(ld.digest)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := ld.tmpFile.Seek(0, os.SEEK_SET); err != nil {
+		return 0, err
+	}
+	offset, err := io.Copy(verifier, ld.tmpFile)
+	if err != nil {
+		return 0, err
 	}
+	ld.verifier = verifier
+	return offset, nil
 }
 
 func (ld *v2LayerDescriptor) truncateDownloadFile() error {
@@ -323,6 +354,13 @@ func (ld *v2LayerDescriptor) truncateDownloadFile() error {
 	return nil
 }
 
+// removeDownloadFile closes and removes the download file.
+func (ld *v2LayerDescriptor) removeDownloadFile() {
+	closeDownloadFile(ld.tmpFile, true)
+	ld.tmpFile = nil
+	ld.verifier = nil
+}
+
 func (ld *v2LayerDescriptor) Registered(diffID layer.DiffID) {
 	// Cache mapping from this layer's DiffID to the blobsum
 	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.FullName()})
@@ -381,7 +419,7 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdat
 
 	switch v := manifest.(type) {
 	case *schema1.SignedManifest:
-		imageID, manifestDigest, err = p.pullSchema1(ctx, ref, v)
+		imageID, manifestDigest, err = p.pullSchema1(ctx, ref, v, nil)
 		if err != nil {
 			return false, err
 		}
@@ -425,7 +463,9 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdat
 	return true, nil
 }
 
-func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverifiedManifest *schema1.SignedManifest) (imageID image.ID, manifestDigest digest.Digest, err error) {
+// pullSchema1 pulls the image of a schema1 manifest. listPlatform is the
+// platform of the manifest in the manifest list it was selected from, if any.
+func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverifiedManifest *schema1.SignedManifest, listPlatform *manifestlist.PlatformSpec) (imageID image.ID, manifestDigest digest.Digest, err error) {
 	var verifiedManifest *schema1.Manifest
 	verifiedManifest, err = verifySchema1Manifest(unverifiedManifest, ref)
 	if err != nil {
@@ -476,6 +516,7 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 			repoInfo:          p.repoInfo,
 			repo:              p.repo,
 			V2MetadataService: p.V2MetadataService,
+			downloadDir:       p.config.DownloadDir,
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -492,6 +533,23 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 		return "", "", err
 	}
 
+	if listPlatform != nil {
+		// The platform of schema1 images is often the one of the client
+		// that pushed them, the manifest list knows the actual one.
+		if config, err = setConfigPlatform(config, *listPlatform); err != nil {
+			return "", "", err
+		}
+	}
+	if p.config.Platform != nil {
+		img, err := image.NewFromJSON(config)
+		if err != nil {
+			return "", "", err
+		}
+		if err := checkImagePlatform(*p.config.Platform, img); err != nil {
+			return "", "", err
+		}
+	}
+
 	imageID, err = p.config.ImageStore.Create(config)
 	if err != nil {
 		return "", "", err
@@ -526,6 +584,7 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 			repo:              p.repo,
 			repoInfo:          p.repoInfo,
 			V2MetadataService: p.V2MetadataService,
+			downloadDir:       p.config.DownloadDir,
 			src:               d,
 		}
 
@@ -611,6 +670,12 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 		}
 	}
 
+	if p.config.Platform != nil {
+		if err := checkImagePlatform(*p.config.Platform, &unmarshalledConfig); err != nil {
+			return "", "", err
+		}
+	}
+
 	imageID, err = p.config.ImageStore.Create(configJSON)
 	if err != nil {
 		return "", "", err
@@ -642,20 +707,26 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 		return "", "", err
 	}
 
-	var manifestDigest digest.Digest
-	for _, manifestDescriptor := range mfstList.Manifests {
-		// TODO(aaronl): The manifest list spec supports optional
-		// "features" and "variant" fields. These are not yet used.
-		// Once they are, their values should be interpreted here.
-		if manifestDescriptor.Platform.Architecture == runtime.GOARCH && manifestDescriptor.Platform.OS == runtime.GOOS {
-			manifestDigest = manifestDescriptor.Digest
+	platform := defaultPlatform()
+	if p.config.Platform != nil {
+		platform = *p.config.Platform
+	}
+
+	var manifestDescriptor *manifestlist.ManifestDescriptor
+	for i := range mfstList.Manifests {
+		// TODO(aaronl): The manifest list spec supports an optional
+		// "features" field. It is not yet used. Once it is, its value
+		// should be interpreted here.
+		if matchPlatform(platform, mfstList.Manifests[i].Platform) {
+			manifestDescriptor = &mfstList.Manifests[i]
 			break
 		}
 	}
 
-	if manifestDigest == "" {
-		return "", "", errors.New("no supported platform found in manifest list")
+	if manifestDescriptor == nil {
+		return "", "", fmt.Errorf("no manifest for platform %s found in manifest list", formatPlatform(platform))
 	}
+	manifestDigest := manifestDescriptor.Digest
 
 	manSvc, err := p.repo.Manifests(ctx)
 	if err != nil {
@@ -674,7 +745,7 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 
 	switch v := manifest.(type) {
 	case *schema1.SignedManifest:
-		imageID, _, err = p.pullSchema1(ctx, manifestRef, v)
+		imageID, _, err = p.pullSchema1(ctx, manifestRef, v, &manifestDescriptor.Platform)
 		if err != nil {
 			return "", "", err
 		}
@@ -855,6 +926,85 @@ func fixManifestLayers(m *schema1.Manifest) error {
 	return nil
 }
 
-func createDownloadFile() (*os.File, error) {
-	return ioutil.TempFile("", "GetImageBlob")
+// downloadFiles holds the names of the resumable download files in use. The
+// same blob can be downloaded by several transfers at once, when it is part of
+// several images pulled together or appears twice in the same image, and
+// only one of them can use the resumable download file of the blob.
+var downloadFiles = struct {
+	sync.Mutex
+	names map[string]struct{}
+}{names: make(map[string]struct{})}
+
+// openDownloadFile opens the download file of the blob dgst in dir, creating
+// it if no partial download of the blob exists. Each download is named after
+// the digest of the blob, so that it can be resumed by a later pull. If the
+// download file of the blob is in use by another transfer, a temporary file
+// which can't be resumed is returned instead. The file must be closed with
+// closeDownloadFile.
+func openDownloadFile(dir string, dgst digest.Digest) (f *os.File, resumable bool, err error) {
+	if dir == "" {
+		return nil, false, errors.New("no directory to download layers to")
+	}
+	if err := dgst.Validate(); err != nil {
+		return nil, false, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, false, err
+	}
+	name := filepath.Join(dir, dgst.Algorithm().String()+"-"+dgst.Hex())
+
+	downloadFiles.Lock()
+	defer downloadFiles.Unlock()
+
+	if _, inUse := downloadFiles.names[name]; inUse {
+		f, err = ioutil.TempFile(dir, "GetImageBlob")
+		return f, false, err
+	}
+	f, err = os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, false, err
+	}
+	downloadFiles.names[name] = struct{}{}
+	return f, true, nil
+}
+
+// closeDownloadFile closes the download file f, removes it if remove is set,
+// and releases it for the other transfers of the blob.
+func closeDownloadFile(f *os.File, remove bool) error {
+	f.Close()
+
+	var err error
+	if remove {
+		if err = os.RemoveAll(f.Name()); err != nil {
+			logrus.Errorf("Failed to remove temp file: %s", f.Name())
+		}
+	}
+
+	downloadFiles.Lock()
+	delete(downloadFiles.names, f.Name())
+	downloadFiles.Unlock()
+	return err
+}
+
+// CleanupLayerDownloads removes the partial layer downloads in dir which
+// weren't written to for maxAge, as the pulls they were left by are unlikely
+// to be retried.
+func CleanupLayerDownloads(dir string, maxAge time.Duration) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, fi := range files {
+		if fi.IsDir() || time.Since(fi.ModTime()) < maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, fi.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		logrus.Debugf("Removed stale layer download %s", fi.Name())
+	}
+	return nil
 }
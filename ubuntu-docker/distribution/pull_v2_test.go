@@ -1,16 +1,24 @@
 package distribution
 
 import (
+	"bytes"
 	"encoding/json"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/distribution"
+	dcontext "github.com/docker/distribution/context"
 	"github.com/docker/distribution/digest"
 	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/reference"
+	"golang.org/x/net/context"
 )
 
 // TestFixManifestLayers checks that fixManifestLayers removes a duplicate
@@ -181,3 +189,314 @@ func TestValidateManifest(t *testing.T) {
 		t.Fatal("expected validateManifest to fail with digest error")
 	}
 }
+
+type testBlobReader struct {
+	*bytes.Reader
+	offsets *[]int64
+}
+
+func (r testBlobReader) Seek(offset int64, whence int) (int64, error) {
+	if whence == os.SEEK_SET {
+		*r.offsets = append(*r.offsets, offset)
+	}
+	return r.Reader.Seek(offset, whence)
+}
+
+func (r testBlobReader) Close() error {
+	return nil
+}
+
+// gatedBlobReader stops reading at gateAt bytes until gate is closed. It
+// doesn't embed its reader, so that the reads can't bypass Read.
+type gatedBlobReader struct {
+	r      testBlobReader
+	gate   chan struct{}
+	gateAt int64
+}
+
+func (r gatedBlobReader) Read(p []byte) (int, error) {
+	pos, err := r.r.Reader.Seek(0, os.SEEK_CUR)
+	if err != nil {
+		return 0, err
+	}
+	if pos >= r.gateAt {
+		<-r.gate
+	} else if remaining := r.gateAt - pos; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	return r.r.Read(p)
+}
+
+func (r gatedBlobReader) Seek(offset int64, whence int) (int64, error) {
+	return r.r.Seek(offset, whence)
+}
+
+func (r gatedBlobReader) Close() error {
+	return r.r.Close()
+}
+
+type testBlobStore struct {
+	distribution.BlobStore
+	content []byte
+	offsets []int64
+	// If gate is set, the reads stop at gateAt bytes until it's closed.
+	gate   chan struct{}
+	gateAt int64
+}
+
+func (bs *testBlobStore) Open(ctx dcontext.Context, dgst digest.Digest) (distribution.ReadSeekCloser, error) {
+	r := testBlobReader{Reader: bytes.NewReader(bs.content), offsets: &bs.offsets}
+	if bs.gate != nil {
+		return gatedBlobReader{r: r, gate: bs.gate, gateAt: bs.gateAt}, nil
+	}
+	return r, nil
+}
+
+type testRepository struct {
+	distribution.Repository
+	blobs *testBlobStore
+}
+
+func (r testRepository) Blobs(ctx dcontext.Context) distribution.BlobStore {
+	return r.blobs
+}
+
+func discardProgress() (progress.Output, func()) {
+	progressChan := make(chan progress.Progress, 100)
+	go func() {
+		for range progressChan {
+		}
+	}()
+	return progress.ChanOutput(progressChan), func() { close(progressChan) }
+}
+
+// TestResumeLayerDownload checks that a partial download left over from an
+// earlier pull is resumed and verified against the digest of the blob.
+func TestResumeLayerDownload(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "resume-download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	content := bytes.Repeat([]byte("layer data "), 10000)
+	dgst := digest.FromBytes(content)
+	blobs := &testBlobStore{content: content}
+
+	partial, _, err := openDownloadFile(tmpDir, dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	half := int64(len(content) / 2)
+	if _, err := partial.Write(content[:half]); err != nil {
+		t.Fatal(err)
+	}
+	closeDownloadFile(partial, false)
+
+	ld := &v2LayerDescriptor{
+		digest:      dgst,
+		repo:        testRepository{blobs: blobs},
+		downloadDir: tmpDir,
+	}
+	output, done := discardProgress()
+	defer done()
+
+	rc, _, err := ld.Download(context.Background(), output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloaded, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, content) {
+		t.Fatal("downloaded content doesn't match the blob")
+	}
+	if len(blobs.offsets) == 0 || blobs.offsets[0] != half {
+		t.Fatalf("expected the download to resume at %d, got seeks to %v", half, blobs.offsets)
+	}
+	if _, err := os.Stat(partial.Name()); !os.IsNotExist(err) {
+		t.Fatalf("expected the download file to be removed, got %v", err)
+	}
+}
+
+// TestLayerDownloadDigestMismatch checks that a download which doesn't match
+// the digest of the blob isn't kept to be resumed.
+func TestLayerDownloadDigestMismatch(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "download-mismatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dgst := digest.FromBytes([]byte("expected layer data"))
+	ld := &v2LayerDescriptor{
+		digest:      dgst,
+		repo:        testRepository{blobs: &testBlobStore{content: []byte("corrupted layer data")}},
+		downloadDir: tmpDir,
+	}
+	output, done := discardProgress()
+	defer done()
+
+	if _, _, err := ld.Download(context.Background(), output); err == nil {
+		t.Fatal("expected the download to fail verification")
+	}
+	ld.Close()
+	files, err := ioutil.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected the download file to be removed, found %s", files[0].Name())
+	}
+}
+
+// TestConcurrentLayerDownloads checks that two transfers of the same blob,
+// as for an image which contains the same layer twice, don't share the
+// download file.
+func TestConcurrentLayerDownloads(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "concurrent-downloads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	content := bytes.Repeat([]byte("layer data "), 10000)
+	dgst := digest.FromBytes(content)
+	half := int64(len(content) / 2)
+	resumable := filepath.Join(tmpDir, dgst.Algorithm().String()+"-"+dgst.Hex())
+
+	output, done := discardProgress()
+	defer done()
+
+	// The first transfer stops halfway through the blob.
+	gate := make(chan struct{})
+	first := &v2LayerDescriptor{
+		digest:      dgst,
+		repo:        testRepository{blobs: &testBlobStore{content: content, gate: gate, gateAt: half}},
+		downloadDir: tmpDir,
+	}
+	type result struct {
+		data []byte
+		err  error
+	}
+	firstDone := make(chan result)
+	go func() {
+		rc, _, err := first.Download(context.Background(), output)
+		if err != nil {
+			firstDone <- result{err: err}
+			return
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		firstDone <- result{data, err}
+	}()
+
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		if fi, err := os.Stat(resumable); err == nil && fi.Size() == half {
+			break
+		}
+		if time.Now().After(deadline) {
+			close(gate)
+			t.Fatal("timeout waiting for the first transfer to write half of the blob")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The second transfer of the blob runs to completion meanwhile.
+	second := &v2LayerDescriptor{
+		digest:      dgst,
+		repo:        testRepository{blobs: &testBlobStore{content: content}},
+		downloadDir: tmpDir,
+	}
+	rc, _, err := second.Download(context.Background(), output)
+	if err != nil {
+		close(gate)
+		t.Fatal(err)
+	}
+	downloaded, err := ioutil.ReadAll(rc)
+	rc.Close()
+	second.Close()
+	if err != nil {
+		close(gate)
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, content) {
+		close(gate)
+		t.Fatal("downloaded content of the second transfer doesn't match the blob")
+	}
+
+	// The file of the first transfer is left alone.
+	fi, err := os.Stat(resumable)
+	if err != nil {
+		close(gate)
+		t.Fatalf("expected the download file of the first transfer to be kept: %v", err)
+	}
+	if fi.Size() != half {
+		close(gate)
+		t.Fatalf("expected the download file of the first transfer to hold %d bytes, got %d", half, fi.Size())
+	}
+
+	close(gate)
+	res := <-firstDone
+	first.Close()
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	if !bytes.Equal(res.data, content) {
+		t.Fatal("downloaded content of the first transfer doesn't match the blob")
+	}
+
+	files, err := ioutil.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected the download files to be removed, found %s", files[0].Name())
+	}
+}
+
+func TestOpenDownloadFileNoDir(t *testing.T) {
+	if _, _, err := openDownloadFile("", digest.FromBytes([]byte("layer"))); err == nil {
+		t.Fatal("expected an error without download directory")
+	}
+}
+
+func TestCleanupLayerDownloads(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "cleanup-downloads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	stale, _, err := openDownloadFile(tmpDir, digest.FromBytes([]byte("stale")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeDownloadFile(stale, false)
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(stale.Name(), old, old); err != nil {
+		t.Fatal(err)
+	}
+	recent, _, err := openDownloadFile(tmpDir, digest.FromBytes([]byte("recent")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeDownloadFile(recent, false)
+
+	if err := CleanupLayerDownloads(tmpDir, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale.Name()); !os.IsNotExist(err) {
+		t.Fatalf("expected the stale download to be removed, got %v", err)
+	}
+	if _, err := os.Stat(recent.Name()); err != nil {
+		t.Fatalf("expected the recent download to be kept: %v", err)
+	}
+
+	// A missing download directory is not an error.
+	if err := CleanupLayerDownloads(filepath.Join(tmpDir, "missing"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -100,6 +100,11 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 		return err
 	}
 
+	imagePushConfig.AuthConfig, err = imagePushConfig.RegistryService.LookupAuthConfig(imagePushConfig.AuthConfig, repoInfo.Index)
+	if err != nil {
+		return err
+	}
+
 	endpoints, err := imagePushConfig.RegistryService.LookupPushEndpoints(repoInfo.Hostname())
 	if err != nil {
 		return err
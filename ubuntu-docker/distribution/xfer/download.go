@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -24,6 +27,12 @@ const maxDownloadAttempts = 5
 type LayerDownloadManager struct {
 	layerStore layer.Store
 	tm         TransferManager
+
+	// decompressMu protects the limit and the number of the layers
+	// decompressed ahead of the registration of their parent.
+	decompressMu     sync.Mutex
+	decompressLimit  int
+	decompressActive int
 }
 
 // SetConcurrency set the max concurrent downloads for each pull
@@ -31,6 +40,16 @@ func (ldm *LayerDownloadManager) SetConcurrency(concurrency int) {
 	ldm.tm.SetConcurrency(concurrency)
 }
 
+// SetDecompressionConcurrency sets the max number of layers that are
+// decompressed while their parent layer is still being registered. Each of
+// them is decompressed to a temporary file. Zero disables the decompression
+// ahead of registration.
+func (ldm *LayerDownloadManager) SetDecompressionConcurrency(concurrency int) {
+	ldm.decompressMu.Lock()
+	ldm.decompressLimit = concurrency
+	ldm.decompressMu.Unlock()
+}
+
 // NewLayerDownloadManager returns a new LayerDownloadManager.
 func NewLayerDownloadManager(layerStore layer.Store, concurrencyLimit int) *LayerDownloadManager {
 	return &LayerDownloadManager{
@@ -292,11 +311,28 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 
 			close(inactive)
 
+			reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(d.Transfer.Context(), downloadReader), progressOutput, size, descriptor.ID(), "Extracting")
+			defer reader.Close()
+
+			var inflatedLayerData io.ReadCloser
 			if parentDownload != nil {
+				inflatedLayerData, err = ldm.decompressAhead(reader, parentDownload)
+				if err != nil {
+					select {
+					case <-d.Transfer.Context().Done():
+						d.err = errors.New("layer registration cancelled")
+					default:
+						d.err = fmt.Errorf("could not decompress layer: %v", err)
+					}
+					return
+				}
+				if inflatedLayerData != nil {
+					defer inflatedLayerData.Close()
+				}
+
 				select {
 				case <-d.Transfer.Context().Done():
 					d.err = errors.New("layer registration cancelled")
-					downloadReader.Close()
 					return
 				case <-parentDownload.Done():
 				}
@@ -304,19 +340,18 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 				l, err := parentDownload.result()
 				if err != nil {
 					d.err = err
-					downloadReader.Close()
 					return
 				}
 				parentLayer = l.ChainID()
 			}
 
-			reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(d.Transfer.Context(), downloadReader), progressOutput, size, descriptor.ID(), "Extracting")
-			defer reader.Close()
-
-			inflatedLayerData, err := archive.DecompressStream(reader)
-			if err != nil {
-				d.err = fmt.Errorf("could not get decompression stream: %v", err)
-				return
+			if inflatedLayerData == nil {
+				inflatedLayerData, err = archive.DecompressStream(reader)
+				if err != nil {
+					d.err = fmt.Errorf("could not get decompression stream: %v", err)
+					return
+				}
+				defer inflatedLayerData.Close()
 			}
 
 			var src distribution.Descriptor
@@ -358,6 +393,62 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 	}
 }
 
+// decompressAhead decompresses the layer data to a temporary file while
+// parentDownload is still being registered, so that the decompression of a
+// layer overlaps with the registration of its parent. It returns nil if the
+// parent is already done, or if the limit of layers decompressed ahead is
+// reached. In that case the layer is decompressed during its registration.
+func (ldm *LayerDownloadManager) decompressAhead(compressed io.Reader, parentDownload *downloadTransfer) (io.ReadCloser, error) {
+	select {
+	case <-parentDownload.Done():
+		return nil, nil
+	default:
+	}
+
+	ldm.decompressMu.Lock()
+	if ldm.decompressActive >= ldm.decompressLimit {
+		ldm.decompressMu.Unlock()
+		return nil, nil
+	}
+	ldm.decompressActive++
+	ldm.decompressMu.Unlock()
+
+	defer func() {
+		ldm.decompressMu.Lock()
+		ldm.decompressActive--
+		ldm.decompressMu.Unlock()
+	}()
+
+	inflated, err := archive.DecompressStream(compressed)
+	if err != nil {
+		return nil, err
+	}
+	defer inflated.Close()
+
+	tmpFile, err := ioutil.TempFile("", "LayerDecompressed")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() error {
+		tmpFile.Close()
+		if err := os.RemoveAll(tmpFile.Name()); err != nil {
+			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
+			return err
+		}
+		return nil
+	}
+
+	if _, err := io.Copy(tmpFile, inflated); err != nil {
+		cleanup()
+		return nil, err
+	}
+	if _, err := tmpFile.Seek(0, os.SEEK_SET); err != nil {
+		cleanup()
+		return nil, err
+	}
+	return ioutils.NewReadCloserWrapper(tmpFile, cleanup), nil
+}
+
 // makeDownloadFuncFromDownload returns a function that performs the layer
 // registration when the layer data is coming from an existing download. It
 // waits for sourceDownload and parentDownload to complete, and then
@@ -31,6 +31,10 @@ func (ml *mockLayer) TarStream() (io.ReadCloser, error) {
 	return ioutil.NopCloser(bytes.NewBuffer(ml.layerData.Bytes())), nil
 }
 
+func (ml *mockLayer) TarStreamFrom(layer.ChainID) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (ml *mockLayer) ChainID() layer.ChainID {
 	return ml.chainID
 }
@@ -246,12 +250,23 @@ func downloadDescriptors(currentDownloads *int32) []DownloadDescriptor {
 }
 
 func TestSuccessfulDownload(t *testing.T) {
+	testSuccessfulDownload(t, 0)
+}
+
+// TestSuccessfulDownloadDecompressAhead checks that layers decompressed while
+// their parent is being registered end up with the same content.
+func TestSuccessfulDownloadDecompressAhead(t *testing.T) {
+	testSuccessfulDownload(t, maxDownloadConcurrency)
+}
+
+func testSuccessfulDownload(t *testing.T, decompressions int) {
 	// TODO Windows: Fix this unit text
 	if runtime.GOOS == "windows" {
 		t.Skip("Needs fixing on Windows")
 	}
 	layerStore := &mockLayerStore{make(map[layer.ChainID]*mockLayer)}
 	ldm := NewLayerDownloadManager(layerStore, maxDownloadConcurrency)
+	ldm.SetDecompressionConcurrency(decompressions)
 
 	progressChan := make(chan progress.Progress)
 	progressDone := make(chan struct{})
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/distribution/digest"
@@ -22,6 +23,8 @@ type Store interface {
 	Children(id ID) []ID
 	Map() map[ID]*Image
 	Heads() map[ID]*Image
+	SetLastUsed(id ID, t time.Time) error
+	GetLastUsed(id ID) (time.Time, error)
 }
 
 // LayerGetReleaser is a minimal interface for getting and releasing images.
@@ -85,6 +88,14 @@ func (is *store) restore() error {
 
 		is.images[ID(id)] = imageMeta
 
+		// Images stored before the last use was recorded are considered
+		// used now, rather than never.
+		if _, err := is.GetLastUsed(id); err != nil {
+			if err := is.setLastUsed(id, time.Now()); err != nil {
+				logrus.Warnf("failed to record last use of image %v: %v", id, err)
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -137,7 +148,7 @@ func (is *store) Create(config []byte) (ID, error) {
 	defer is.Unlock()
 
 	if _, exists := is.images[imageID]; exists {
-		return imageID, nil
+		return imageID, is.setLastUsed(imageID, time.Now())
 	}
 
 	layerID := img.RootFS.ChainID()
@@ -161,7 +172,7 @@ func (is *store) Create(config []byte) (ID, error) {
 		return "", err
 	}
 
-	return imageID, nil
+	return imageID, is.setLastUsed(imageID, time.Now())
 }
 
 func (is *store) Search(term string) (ID, error) {
@@ -249,6 +260,29 @@ func (is *store) GetParent(id ID) (ID, error) {
 	return ID(d), nil // todo: validate?
 }
 
+// SetLastUsed records t as the last time the image was used. Creating an
+// image in the store also counts as a use.
+func (is *store) SetLastUsed(id ID, t time.Time) error {
+	is.Lock()
+	defer is.Unlock()
+	if is.images[id] == nil {
+		return fmt.Errorf("unrecognized image ID %s", id.String())
+	}
+	return is.setLastUsed(id, t)
+}
+
+func (is *store) setLastUsed(id ID, t time.Time) error {
+	return is.fs.SetMetadata(id, "lastUsed", []byte(t.UTC().Format(time.RFC3339Nano)))
+}
+
+func (is *store) GetLastUsed(id ID) (time.Time, error) {
+	d, err := is.fs.GetMetadata(id, "lastUsed")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, string(d))
+}
+
 func (is *store) Children(id ID) []ID {
 	is.Lock()
 	defer is.Unlock()
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/docker/distribution/digest"
 	"github.com/docker/docker/layer"
@@ -289,6 +290,59 @@ func TestParentReset(t *testing.T) {
 
 }
 
+func TestLastUsed(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images-fs-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	fs, err := NewFSStoreBackend(tmpdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	is, err := NewImageStore(fs, &mockLayerGetReleaser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	id, err := is.Create([]byte(`{"comment": "abc1", "rootfs": {"type": "layers"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastUsed, err := is.GetLastUsed(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastUsed.Before(before) || lastUsed.After(time.Now()) {
+		t.Fatalf("expected last use at creation time, got %v", lastUsed)
+	}
+
+	used := time.Date(2016, 1, 2, 3, 4, 5, 6, time.UTC)
+	if err := is.SetLastUsed(id, used); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last use is kept when the store is restored.
+	is, err = NewImageStore(fs, &mockLayerGetReleaser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastUsed, err = is.GetLastUsed(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lastUsed.Equal(used) {
+		t.Fatalf("expected last use %v, got %v", used, lastUsed)
+	}
+
+	if err := is.SetLastUsed(ID("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), used); err == nil {
+		t.Fatal("expected error setting last use of unknown image")
+	}
+}
+
 type mockLayerGetReleaser struct{}
 
 func (ls *mockLayerGetReleaser) Get(layer.ChainID) (layer.Layer, error) {
@@ -42,7 +42,7 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	if err := chrootarchive.Untar(inTar, tmpDir, nil); err != nil {
 		return err
 	}
-	// read manifest, if no file then load in legacy mode
+	// read manifest, if no file then load an OCI image layout or in legacy mode
 	manifestPath, err := safePath(tmpDir, manifestFileName)
 	if err != nil {
 		return err
@@ -50,6 +50,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	manifestFile, err := os.Open(manifestPath)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if _, err := os.Stat(filepath.Join(tmpDir, ociIndexFileName)); err == nil {
+				return l.ociLoad(tmpDir, outStream, progressOutput)
+			}
 			return l.legacyLoad(tmpDir, outStream, progressOutput)
 		}
 		return manifestFile.Close()
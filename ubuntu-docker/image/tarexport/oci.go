@@ -0,0 +1,447 @@
+package tarexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/system"
+	"github.com/docker/docker/reference"
+)
+
+const (
+	ociLayoutFileName = "oci-layout"
+	ociIndexFileName  = "index.json"
+	ociBlobsDirName   = "blobs"
+	ociLayoutVersion  = "1.0.0"
+
+	mediaTypeOCIManifest = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex    = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIConfig   = "application/vnd.oci.image.config.v1+json"
+	mediaTypeOCILayer    = "application/vnd.oci.image.layer.v1.tar"
+
+	// ociRefNameAnnotation holds the reference of the image a manifest of
+	// the index belongs to.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// ociLayout is the content of the oci-layout file, marking the root of an
+// OCI image layout.
+type ociLayout struct {
+	ImageLayoutVersion string `json:"imageLayoutVersion"`
+}
+
+// ociDescriptor references a blob of an OCI image layout.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest references the configuration and the layers of an image.
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// ociIndex lists the manifests of an OCI image layout.
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type ociSaveSession struct {
+	*tarexporter
+	outDir     string
+	images     map[image.ID]*imageDescriptor
+	savedBlobs map[digest.Digest]int64
+}
+
+// SaveOCI writes the images as an OCI image layout. The image configurations
+// are written as they are, and the layers uncompressed, so the digest of a
+// configuration is the image ID and the digest of a layer its DiffID.
+func (l *tarexporter) SaveOCI(names []string, outStream io.Writer) error {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return err
+	}
+
+	return (&ociSaveSession{tarexporter: l, images: images}).save(outStream)
+}
+
+func (s *ociSaveSession) save(outStream io.Writer) error {
+	s.savedBlobs = make(map[digest.Digest]int64)
+
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s.outDir = tempDir
+	if err := os.MkdirAll(filepath.Join(tempDir, ociBlobsDirName, string(digest.Canonical)), 0755); err != nil {
+		return err
+	}
+
+	// Sort the images and their references so that saving the same
+	// images twice gives the same index.
+	var ids []string
+	for id := range s.images {
+		ids = append(ids, id.String())
+	}
+	sort.Strings(ids)
+
+	index := ociIndex{SchemaVersion: 2, Manifests: []ociDescriptor{}}
+	for _, idStr := range ids {
+		id := image.ID(idStr)
+		desc, err := s.saveImage(id)
+		if err != nil {
+			return err
+		}
+
+		var refs []string
+		for _, ref := range s.images[id].refs {
+			refs = append(refs, ref.String())
+		}
+		sort.Strings(refs)
+
+		if len(refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, ref := range refs {
+			refDesc := desc
+			refDesc.Annotations = map[string]string{ociRefNameAnnotation: ref}
+			index.Manifests = append(index.Manifests, refDesc)
+		}
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	if err := writeJSONFile(filepath.Join(tempDir, ociLayoutFileName), ociLayout{ImageLayoutVersion: ociLayoutVersion}); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(tempDir, ociIndexFileName), index); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+func (s *ociSaveSession) saveImage(id image.ID) (ociDescriptor, error) {
+	img, err := s.is.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ociDescriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	config, err := s.saveBlob(mediaTypeOCIConfig, img.RawJSON())
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	manifest := ociManifest{SchemaVersion: 2, Config: config}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		desc, err := s.saveLayer(rootFS.ChainID())
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	return s.saveBlob(mediaTypeOCIManifest, manifestJSON)
+}
+
+func (s *ociSaveSession) blobPath(dgst digest.Digest) string {
+	return filepath.Join(s.outDir, ociBlobsDirName, dgst.Algorithm().String(), dgst.Hex())
+}
+
+func (s *ociSaveSession) saveBlob(mediaType string, content []byte) (ociDescriptor, error) {
+	desc := ociDescriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	if _, exists := s.savedBlobs[desc.Digest]; exists {
+		return desc, nil
+	}
+
+	blobPath := s.blobPath(desc.Digest)
+	if err := ioutil.WriteFile(blobPath, content, 0644); err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ociDescriptor{}, err
+	}
+	s.savedBlobs[desc.Digest] = desc.Size
+	return desc, nil
+}
+
+func (s *ociSaveSession) saveLayer(id layer.ChainID) (ociDescriptor, error) {
+	l, err := s.ls.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.ls, l)
+
+	desc := ociDescriptor{
+		MediaType: mediaTypeOCILayer,
+		Digest:    digest.Digest(l.DiffID()),
+	}
+	if size, exists := s.savedBlobs[desc.Digest]; exists {
+		desc.Size = size
+		return desc, nil
+	}
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer arch.Close()
+
+	blobPath := s.blobPath(desc.Digest)
+	tarFile, err := os.Create(blobPath)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer tarFile.Close()
+
+	// The digest of the uncompressed layer is its DiffID, check that the
+	// tar stream was reassembled byte for byte.
+	verifier, err := digest.NewDigestVerifier(desc.Digest)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	desc.Size, err = io.Copy(io.MultiWriter(tarFile, verifier), arch)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	if !verifier.Verified() {
+		return ociDescriptor{}, fmt.Errorf("layer %s does not match its DiffID", desc.Digest)
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ociDescriptor{}, err
+	}
+
+	s.savedBlobs[desc.Digest] = desc.Size
+	return desc, nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	return system.Chtimes(path, time.Unix(0, 0), time.Unix(0, 0))
+}
+
+// ociLoad loads the images of the OCI image layout extracted to tmpDir. The
+// images are tagged with the references annotating their manifest in the
+// index.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	indexPath, err := safePath(tmpDir, ociIndexFileName)
+	if err != nil {
+		return err
+	}
+	indexJSON, err := ioutil.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+	var index ociIndex
+	if err := json.Unmarshal(indexJSON, &index); err != nil {
+		return err
+	}
+
+	var (
+		imageIDsStr   string
+		imageRefCount int
+		loaded        = make(map[digest.Digest]image.ID)
+	)
+
+	manifests := index.Manifests
+	for i := 0; i < len(manifests); i++ {
+		desc := manifests[i]
+		switch desc.MediaType {
+		case mediaTypeOCIIndex:
+			// Nested indexes list the manifests of the same image
+			// for several platforms.
+			nestedJSON, err := readOCIBlob(tmpDir, desc)
+			if err != nil {
+				return err
+			}
+			var nested ociIndex
+			if err := json.Unmarshal(nestedJSON, &nested); err != nil {
+				return err
+			}
+			manifests = append(manifests, nested.Manifests...)
+			continue
+		case mediaTypeOCIManifest, schema2.MediaTypeManifest:
+		default:
+			logrus.Debugf("Skipping %s with unsupported media type %s", desc.Digest, desc.MediaType)
+			continue
+		}
+
+		imgID, ok := loaded[desc.Digest]
+		if !ok {
+			imgID, err = l.ociLoadImage(tmpDir, desc, progressOutput)
+			if err != nil {
+				return err
+			}
+			loaded[desc.Digest] = imgID
+			imageIDsStr += fmt.Sprintf("Loaded image ID: %s\n", imgID)
+			l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+		}
+
+		// Only references with a tag can be used to tag the image,
+		// a bare tag doesn't say which repository it belongs to.
+		if name, ok := desc.Annotations[ociRefNameAnnotation]; ok {
+			named, err := reference.ParseNamed(name)
+			if err != nil {
+				logrus.Debugf("Not tagging %s with invalid reference %q: %v", imgID, name, err)
+				continue
+			}
+			if ref, ok := named.(reference.NamedTagged); ok {
+				l.setLoadedTag(ref, imgID, outStream)
+				outStream.Write([]byte(fmt.Sprintf("Loaded image: %s\n", ref)))
+				imageRefCount++
+			}
+		}
+	}
+
+	if imageRefCount == 0 {
+		outStream.Write([]byte(imageIDsStr))
+	}
+
+	return nil
+}
+
+func (l *tarexporter) ociLoadImage(tmpDir string, desc ociDescriptor, progressOutput progress.Output) (image.ID, error) {
+	manifestJSON, err := readOCIBlob(tmpDir, desc)
+	if err != nil {
+		return "", err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return "", err
+	}
+
+	config, err := readOCIBlob(tmpDir, manifest.Config)
+	if err != nil {
+		return "", err
+	}
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+
+	if expected, actual := len(manifest.Layers), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	for i, diffID := range img.RootFS.DiffIDs {
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.ls.Get(r.ChainID())
+		if err != nil {
+			layerPath, err := ociBlobPath(tmpDir, manifest.Layers[i])
+			if err != nil {
+				return "", err
+			}
+			if err := verifyOCIBlob(layerPath, manifest.Layers[i]); err != nil {
+				return "", err
+			}
+			newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), distribution.Descriptor{}, progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.ls, newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
+// ociBlobPath returns the path of the blob desc in the OCI image layout
+// extracted to tmpDir.
+func ociBlobPath(tmpDir string, desc ociDescriptor) (string, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return "", err
+	}
+	return safePath(tmpDir, filepath.Join(ociBlobsDirName, desc.Digest.Algorithm().String(), desc.Digest.Hex()))
+}
+
+// readOCIBlob reads the blob desc and checks it against its digest.
+func readOCIBlob(tmpDir string, desc ociDescriptor) ([]byte, error) {
+	blobPath, err := ociBlobPath(tmpDir, desc)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(blobPath)
+	if err != nil {
+		return nil, err
+	}
+	if digest.FromBytes(content) != desc.Digest {
+		return nil, fmt.Errorf("blob %s does not match its digest", desc.Digest)
+	}
+	return content, nil
+}
+
+// verifyOCIBlob checks the blob file at blobPath against the digest of desc.
+func verifyOCIBlob(blobPath string, desc ociDescriptor) error {
+	f, err := os.Open(blobPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	verifier, err := digest.NewDigestVerifier(desc.Digest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(verifier, f); err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("blob %s does not match its digest", desc.Digest)
+	}
+	return nil
+}
@@ -170,6 +170,44 @@ func (s *DockerSuite) TestApiNetworkConnectDisconnect(c *check.C) {
 	deleteNetwork(c, nr.ID, true)
 }
 
+func (s *DockerSuite) TestApiNetworkStats(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	id := createNetwork(c, types.NetworkCreateRequest{Name: "statsnet"}, true)
+
+	dockerCmd(c, "run", "-d", "--net", "statsnet", "--name", "first", "busybox", "top")
+	dockerCmd(c, "run", "-d", "--net", "statsnet", "--name", "second", "busybox", "top")
+	dockerCmd(c, "exec", "first", "ping", "-c", "3", "second")
+
+	status, body, err := sockRequest("GET", "/networks/statsnet/stats", nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusOK)
+
+	var stats types.NetworkTrafficStats
+	c.Assert(json.Unmarshal(body, &stats), checker.IsNil)
+	c.Assert(stats.ID, checker.Equals, id)
+	c.Assert(stats.Name, checker.Equals, "statsnet")
+	c.Assert(stats.Endpoints, checker.HasLen, 2)
+
+	nr := getNetworkResource(c, id)
+	var total types.NetworkStats
+	for _, name := range []string{"first", "second"} {
+		cid := inspectField(c, name, "Id")
+		ep, ok := stats.Endpoints[nr.Containers[cid].EndpointID]
+		c.Assert(ok, checker.True, check.Commentf("no stats for the endpoint of %s: %s", name, body))
+		c.Assert(ep.ContainerID, checker.Equals, cid)
+		c.Assert(ep.TxPackets, checker.GreaterOrEqualThan, uint64(3))
+		c.Assert(ep.RxPackets, checker.GreaterOrEqualThan, uint64(3))
+		total.RxBytes += ep.RxBytes
+		total.TxBytes += ep.TxBytes
+	}
+	c.Assert(stats.Totals.RxBytes, checker.Equals, total.RxBytes)
+	c.Assert(stats.Totals.TxBytes, checker.Equals, total.TxBytes)
+
+	status, _, err = sockRequest("GET", "/networks/nosuchnetwork/stats", nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusNotFound)
+}
+
 func (s *DockerSuite) TestApiNetworkIpamMultipleBridgeNetworks(c *check.C) {
 	testRequires(c, DaemonIsLinux)
 	// test0 bridge network
@@ -6960,3 +6960,41 @@ func (s *DockerSuite) TestBuildShellWindowsPowershell(c *check.C) {
 		c.Fatalf("Line with 'John' not found in output %q", out)
 	}
 }
+
+func (s *DockerSuite) TestBuildSquashParent(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildsquashparent"
+	dockerfile := `FROM busybox
+		RUN echo hello > /hello
+		RUN echo world >> /hello
+		RUN touch /remove_me && rm /remove_me
+		ENV HELLO world
+		RUN rm /etc/passwd`
+
+	origID, err := buildImage(name+"-orig", dockerfile, true)
+	c.Assert(err, checker.IsNil)
+	id, err := buildImage(name, dockerfile, true, "--squash")
+	c.Assert(err, checker.IsNil)
+	c.Assert(id, checker.Not(checker.Equals), origID)
+
+	// The layers added by the build are merged into one layer on top of
+	// the layers of busybox.
+	baseLayers, err := inspectFilter("busybox", "len .RootFS.Layers")
+	c.Assert(err, checker.IsNil)
+	layers, err := inspectFilter(name, "len .RootFS.Layers")
+	c.Assert(err, checker.IsNil)
+	expected, err := strconv.Atoi(baseLayers)
+	c.Assert(err, checker.IsNil)
+	c.Assert(layers, checker.Equals, strconv.Itoa(expected+1))
+
+	out, _ := dockerCmd(c, "run", "--rm", name, "cat", "/hello")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "hello\nworld")
+	dockerCmd(c, "run", "--rm", name, "sh", "-c", "[ ! -f /remove_me ]")
+	dockerCmd(c, "run", "--rm", name, "sh", "-c", "[ ! -f /etc/passwd ]")
+	c.Assert(inspectField(c, name, "Config.Env"), checker.Contains, "HELLO=world")
+
+	// The history of the steps is kept, with a new entry for the squash.
+	origHistory, _ := dockerCmd(c, "history", "-q", "--no-trunc", origID)
+	history, _ := dockerCmd(c, "history", "-q", "--no-trunc", name)
+	c.Assert(strings.Count(history, "\n"), checker.Equals, strings.Count(origHistory, "\n")+1)
+}
@@ -187,3 +187,19 @@ func (s *DockerSuite) TestCommitMergeConfigRun(c *check.C) {
 		c.Fatalf("expected envs to match: %v - %v", config1.Env, config2.Env)
 	}
 }
+
+func (s *DockerSuite) TestCommitSquash(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "run", "--name", "squash", "busybox", "sh", "-c", "echo squashed > /squashed && rm /etc/passwd")
+
+	out, _ := dockerCmd(c, "commit", "--squash", "squash", "squashed")
+	imageID := strings.TrimSpace(out)
+
+	layers, err := inspectFilter(imageID, "len .RootFS.Layers")
+	c.Assert(err, checker.IsNil)
+	c.Assert(layers, checker.Equals, "1")
+
+	out, _ = dockerCmd(c, "run", "--rm", "squashed", "cat", "/squashed")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "squashed")
+	dockerCmd(c, "run", "--rm", "squashed", "sh", "-c", "[ ! -f /etc/passwd ]")
+}
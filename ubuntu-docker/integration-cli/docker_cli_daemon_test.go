@@ -470,6 +470,45 @@ func (s *DockerDaemonSuite) TestDaemonIPv6FixedCIDRAndMac(c *check.C) {
 	c.Assert(err, checker.IsNil)
 }
 
+func (s *DockerDaemonSuite) TestDaemonIP6TablesWithoutIPv6(c *check.C) {
+	c.Assert(s.d.Start("--ip6tables"), check.NotNil, check.Commentf("Daemon shouldn't start with --ip6tables and without --ipv6"))
+}
+
+// TestDaemonIP6Tables checks that when the daemon is started with ip6tables
+// the IPv6 subnet of the bridge is masqueraded, and the published ports are
+// forwarded to the IPv6 address of the containers.
+func (s *DockerDaemonSuite) TestDaemonIP6Tables(c *check.C) {
+	// IPv6 setup is messing with local bridge address.
+	testRequires(c, SameHostDaemon)
+	err := setupV6()
+	c.Assert(err, checker.IsNil)
+
+	err = s.d.StartWithBusybox("--ipv6", "--fixed-cidr-v6='2001:db8:3::/64'", "--ip6tables")
+	c.Assert(err, checker.IsNil)
+
+	out, err := exec.Command("ip6tables", "-t", "nat", "-S", "POSTROUTING").CombinedOutput()
+	c.Assert(err, checker.IsNil, check.Commentf(string(out)))
+	c.Assert(string(out), checker.Contains, "-A POSTROUTING -s 2001:db8:3::/64 ! -o docker0 -j MASQUERADE")
+
+	out2, err := s.d.Cmd("run", "-d", "--name=ip6tablestest", "-p", "8081:80", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out2))
+
+	out2, err = s.d.Cmd("port", "ip6tablestest", "80")
+	c.Assert(err, checker.IsNil, check.Commentf(out2))
+	c.Assert(out2, checker.Contains, "0.0.0.0:8081")
+	c.Assert(out2, checker.Contains, ":::8081")
+
+	ip, err := s.d.Cmd("inspect", "--format", "{{.NetworkSettings.Networks.bridge.GlobalIPv6Address}}", "ip6tablestest")
+	c.Assert(err, checker.IsNil, check.Commentf(ip))
+
+	out, err = exec.Command("ip6tables", "-t", "nat", "-S", "DOCKER").CombinedOutput()
+	c.Assert(err, checker.IsNil, check.Commentf(string(out)))
+	c.Assert(string(out), checker.Contains, "--to-destination ["+strings.TrimSpace(ip)+"]:80")
+
+	err = teardownV6()
+	c.Assert(err, checker.IsNil)
+}
+
 func (s *DockerDaemonSuite) TestDaemonLogLevelWrong(c *check.C) {
 	c.Assert(s.d.Start("--log-level=bogus"), check.NotNil, check.Commentf("Daemon shouldn't start with wrong log level"))
 }
@@ -61,9 +61,10 @@ func (s *DockerExternalVolumeSuite) SetUpSuite(c *check.C) {
 	s.server = httptest.NewServer(mux)
 
 	type pluginRequest struct {
-		Name string
-		Opts map[string]string
-		ID   string
+		Name     string
+		Opts     map[string]string
+		ID       string
+		Snapshot string
 	}
 
 	type pluginResp struct {
@@ -78,6 +79,7 @@ func (s *DockerExternalVolumeSuite) SetUpSuite(c *check.C) {
 		Status     map[string]interface{}
 	}
 	var volList []vol
+	snapshots := make(map[string][]string)
 
 	read := func(b io.ReadCloser) (pluginRequest, error) {
 		defer b.Close()
@@ -236,7 +238,71 @@ func (s *DockerExternalVolumeSuite) SetUpSuite(c *check.C) {
 			return
 		}
 
-		send(w, `{"Capabilities": { "Scope": "global" }}`)
+		send(w, `{"Capabilities": { "Scope": "global", "Snapshots": true }}`)
+	})
+
+	mux.HandleFunc("/VolumeDriver.Snapshot", func(w http.ResponseWriter, r *http.Request) {
+		pr, err := read(r.Body)
+		if err != nil {
+			send(w, err)
+			return
+		}
+		snapshots[pr.Name] = append(snapshots[pr.Name], pr.Snapshot)
+		send(w, nil)
+	})
+
+	mux.HandleFunc("/VolumeDriver.ListSnapshots", func(w http.ResponseWriter, r *http.Request) {
+		pr, err := read(r.Body)
+		if err != nil {
+			send(w, err)
+			return
+		}
+		type snapshot struct {
+			Name      string
+			CreatedAt string
+		}
+		var ls []snapshot
+		for _, name := range snapshots[pr.Name] {
+			ls = append(ls, snapshot{Name: name, CreatedAt: time.Now().UTC().Format(time.RFC3339Nano)})
+		}
+		send(w, map[string][]snapshot{"Snapshots": ls})
+	})
+
+	hasSnapshot := func(name, snapshot string) (int, bool) {
+		for i, s := range snapshots[name] {
+			if s == snapshot {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	mux.HandleFunc("/VolumeDriver.RestoreSnapshot", func(w http.ResponseWriter, r *http.Request) {
+		pr, err := read(r.Body)
+		if err != nil {
+			send(w, err)
+			return
+		}
+		if _, ok := hasSnapshot(pr.Name, pr.Snapshot); !ok {
+			send(w, `{"Err": "no such snapshot"}`)
+			return
+		}
+		send(w, nil)
+	})
+
+	mux.HandleFunc("/VolumeDriver.DeleteSnapshot", func(w http.ResponseWriter, r *http.Request) {
+		pr, err := read(r.Body)
+		if err != nil {
+			send(w, err)
+			return
+		}
+		i, ok := hasSnapshot(pr.Name, pr.Snapshot)
+		if !ok {
+			send(w, `{"Err": "no such snapshot"}`)
+			return
+		}
+		snapshots[pr.Name] = append(snapshots[pr.Name][:i], snapshots[pr.Name][i+1:]...)
+		send(w, nil)
 	})
 
 	err := os.MkdirAll("/etc/docker/plugins", 0755)
@@ -520,3 +586,39 @@ func (s *DockerExternalVolumeSuite) TestExternalVolumeDriverCapabilities(c *chec
 		c.Assert(strings.TrimSpace(out), checker.Equals, volume.GlobalScope)
 	}
 }
+
+func (s *DockerExternalVolumeSuite) TestExternalVolumeDriverSnapshots(c *check.C) {
+	c.Assert(s.d.StartWithBusybox(), checker.IsNil)
+
+	out, err := s.d.Cmd("volume", "create", "-d", "test-external-volume-driver", "--name", "snapvol")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, err = s.d.Cmd("volume", "snapshot", "create", "snapvol", "snap1")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "snap1")
+
+	out, err = s.d.Cmd("volume", "snapshot", "ls", "-q", "snapvol")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "snap1")
+
+	out, err = s.d.Cmd("volume", "snapshot", "restore", "snapvol", "snap1")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, err = s.d.Cmd("volume", "snapshot", "restore", "snapvol", "nosuchsnap")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "no such snapshot")
+
+	out, err = s.d.Cmd("run", "-d", "--name", "snaptest", "-v", "snapvol:/foo", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	out, err = s.d.Cmd("volume", "snapshot", "restore", "snapvol", "snap1")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "volume still in use")
+	out, err = s.d.Cmd("rm", "-f", "snaptest")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, err = s.d.Cmd("volume", "snapshot", "rm", "snapvol", "snap1")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	out, err = s.d.Cmd("volume", "snapshot", "ls", "-q", "snapvol")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "")
+}
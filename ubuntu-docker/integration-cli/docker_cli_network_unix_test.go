@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"runtime"
 	"strings"
 	"time"
 
@@ -24,7 +25,9 @@ import (
 	remoteipam "github.com/docker/libnetwork/ipams/remote/api"
 	"github.com/docker/libnetwork/netlabel"
 	"github.com/go-check/check"
+	"github.com/miekg/dns"
 	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
 )
 
 const dummyNetworkDriver = "dummy-network-driver"
@@ -387,6 +390,117 @@ func (s *DockerNetworkSuite) TestDockerNetworkCreateLabel(c *check.C) {
 	assertNwNotAvailable(c, testNet)
 }
 
+func (s *DockerNetworkSuite) TestDockerNetworkCreateDNSServers(c *check.C) {
+	out, _, err := dockerCmdWithError("network", "create", "-o", "com.docker.network.dns.servers=10.0.0.2,foo", "testdnsservers")
+	c.Assert(err, checker.NotNil, check.Commentf("%v", out))
+	c.Assert(out, checker.Contains, "invalid IPv4 DNS server")
+	assertNwNotAvailable(c, "testdnsservers")
+
+	dockerCmd(c, "network", "create", "-o", "com.docker.network.dns.servers=10.0.0.2,10.0.0.3", "testdnsservers")
+	assertNwIsAvailable(c, "testdnsservers")
+
+	out, _ = dockerCmd(c, "network", "inspect", "--format={{ index .Options \"com.docker.network.dns.servers\" }}", "testdnsservers")
+	c.Assert(strings.TrimSpace(out), check.Equals, "10.0.0.2,10.0.0.3")
+
+	dockerCmd(c, "network", "rm", "testdnsservers")
+	assertNwNotAvailable(c, "testdnsservers")
+}
+
+// queryEmbeddedDNS sends a query for name of type qtype to the embedded DNS
+// server of the network sandbox at sandboxKey.
+func queryEmbeddedDNS(c *check.C, sandboxKey, name string, qtype uint16) *dns.Msg {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origns, err := netns.Get()
+	c.Assert(err, check.IsNil)
+	defer origns.Close()
+
+	ns, err := netns.GetFromPath(sandboxKey)
+	c.Assert(err, check.IsNil)
+	defer ns.Close()
+
+	c.Assert(netns.Set(ns), check.IsNil)
+	defer netns.Set(origns)
+
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(name), qtype)
+	resp, err := dns.Exchange(query, "127.0.0.11:53")
+	c.Assert(err, check.IsNil)
+	return resp
+}
+
+func (s *DockerSuite) TestDockerNetworkEmbeddedDNSSRV(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, NotUserNamespace)
+	dockerCmd(c, "network", "create", "dnssrv")
+	assertNwIsAvailable(c, "dnssrv")
+
+	dockerCmd(c, "run", "-d", "--net=dnssrv", "--name=web", "--expose=80", "busybox", "top")
+	c.Assert(waitRun("web"), check.IsNil)
+	dockerCmd(c, "run", "-d", "--net=dnssrv", "--name=client", "busybox", "top")
+	c.Assert(waitRun("client"), check.IsNil)
+
+	webIP := inspectField(c, "web", "NetworkSettings.Networks.dnssrv.IPAddress")
+	sandboxKey := inspectField(c, "client", "NetworkSettings.SandboxKey")
+
+	resp := queryEmbeddedDNS(c, sandboxKey, "_80._tcp.web", dns.TypeSRV)
+	c.Assert(resp.Rcode, check.Equals, dns.RcodeSuccess)
+	c.Assert(resp.Answer, checker.HasLen, 1)
+	srv, ok := resp.Answer[0].(*dns.SRV)
+	c.Assert(ok, checker.True, check.Commentf("%v", resp.Answer[0]))
+	c.Assert(srv.Port, check.Equals, uint16(80))
+	c.Assert(srv.Target, check.Equals, "web.")
+	c.Assert(resp.Extra, checker.HasLen, 1)
+	a, ok := resp.Extra[0].(*dns.A)
+	c.Assert(ok, checker.True, check.Commentf("%v", resp.Extra[0]))
+	c.Assert(a.Hdr.Name, check.Equals, "web.")
+	c.Assert(a.A.String(), check.Equals, webIP)
+
+	// A port which is not exposed has no SRV record
+	resp = queryEmbeddedDNS(c, sandboxKey, "_81._tcp.web", dns.TypeSRV)
+	c.Assert(resp.Answer, checker.HasLen, 0)
+}
+
+func (s *DockerSuite) TestDockerNetworkDNSServersForward(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, NotUserNamespace)
+	dockerCmd(c, "network", "create", "--subnet=172.28.0.0/16", "--gateway=172.28.0.1",
+		"-o", "com.docker.network.dns.servers=172.28.0.1", "dnsforward")
+	assertNwIsAvailable(c, "dnsforward")
+
+	// The upstream DNS server of the network listens on its gateway
+	pc, err := net.ListenPacket("udp", "172.28.0.1:53")
+	c.Assert(err, check.IsNil)
+	server := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, query *dns.Msg) {
+			resp := new(dns.Msg)
+			resp.SetReply(query)
+			if query.Question[0].Name == "upstream.dnstest." && query.Question[0].Qtype == dns.TypeA {
+				rr := new(dns.A)
+				rr.Hdr = dns.RR_Header{Name: "upstream.dnstest.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 600}
+				rr.A = net.ParseIP("10.11.12.13")
+				resp.Answer = append(resp.Answer, rr)
+			} else {
+				resp.SetRcode(query, dns.RcodeNameError)
+			}
+			w.WriteMsg(resp)
+		}),
+	}
+	go server.ActivateAndServe()
+	defer server.Shutdown()
+
+	dockerCmd(c, "run", "-d", "--net=dnsforward", "--name=client", "busybox", "top")
+	c.Assert(waitRun("client"), check.IsNil)
+	sandboxKey := inspectField(c, "client", "NetworkSettings.SandboxKey")
+
+	resp := queryEmbeddedDNS(c, sandboxKey, "upstream.dnstest", dns.TypeA)
+	c.Assert(resp.Rcode, check.Equals, dns.RcodeSuccess)
+	c.Assert(resp.Answer, checker.HasLen, 1)
+	a, ok := resp.Answer[0].(*dns.A)
+	c.Assert(ok, checker.True, check.Commentf("%v", resp.Answer[0]))
+	c.Assert(a.A.String(), check.Equals, "10.11.12.13")
+}
+
 func (s *DockerSuite) TestDockerNetworkDeleteNotExists(c *check.C) {
 	out, _, err := dockerCmdWithError("network", "rm", "test")
 	c.Assert(err, checker.NotNil, check.Commentf("%v", out))
@@ -503,6 +617,76 @@ func (s *DockerSuite) TestDockerInspectNetworkWithContainerName(c *check.C) {
 
 }
 
+func (s *DockerNetworkSuite) TestDockerNetworkPolicyCreateListRemove(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace)
+	dockerCmd(c, "network", "create", "-o", "com.docker.network.bridge.enable_icc=false", "policynet")
+	assertNwIsAvailable(c, "policynet")
+
+	dockerCmd(c, "network", "policy", "create", "--from", "app=web", "--to", "app=db", "--port", "5432/tcp", "policynet", "web-db")
+	out, _ := dockerCmd(c, "network", "policy", "ls", "policynet")
+	c.Assert(out, checker.Contains, "web-db")
+	c.Assert(out, checker.Contains, "app=web")
+	c.Assert(out, checker.Contains, "5432/tcp")
+
+	out, _, err := dockerCmdWithError("network", "policy", "create", "policynet", "web-db")
+	c.Assert(err, checker.NotNil, check.Commentf("%s", out))
+	c.Assert(out, checker.Contains, "already exists")
+
+	dockerCmd(c, "network", "policy", "rm", "policynet", "web-db")
+	out, _ = dockerCmd(c, "network", "policy", "ls", "-q", "policynet")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "")
+
+	out, _, err = dockerCmdWithError("network", "policy", "rm", "policynet", "web-db")
+	c.Assert(err, checker.NotNil, check.Commentf("%s", out))
+
+	dockerCmd(c, "network", "rm", "policynet")
+}
+
+func (s *DockerNetworkSuite) TestDockerNetworkPolicyRequiresIccDisabled(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "network", "create", "iccnet")
+	assertNwIsAvailable(c, "iccnet")
+
+	out, _, err := dockerCmdWithError("network", "policy", "create", "iccnet", "all")
+	c.Assert(err, checker.NotNil, check.Commentf("%s", out))
+	c.Assert(out, checker.Contains, "inter container communication")
+
+	dockerCmd(c, "network", "rm", "iccnet")
+}
+
+func (s *DockerNetworkSuite) TestDockerNetworkPolicyEnforced(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace)
+	dockerCmd(c, "network", "create", "-o", "com.docker.network.bridge.enable_icc=false", "policynet")
+	assertNwIsAvailable(c, "policynet")
+
+	dockerCmd(c, "run", "-d", "--net=policynet", "--name=db", "--label", "app=db", "busybox", "top")
+	c.Assert(waitRun("db"), check.IsNil)
+	dockerCmd(c, "run", "-d", "--net=policynet", "--name=web", "--label", "app=web", "busybox", "top")
+	c.Assert(waitRun("web"), check.IsNil)
+	dbIP := inspectField(c, "db", "NetworkSettings.Networks.policynet.IPAddress")
+	webIP := inspectField(c, "web", "NetworkSettings.Networks.policynet.IPAddress")
+
+	_, _, err := dockerCmdWithError("exec", "web", "ping", "-c", "1", "-W", "1", dbIP)
+	c.Assert(err, check.NotNil)
+
+	dockerCmd(c, "network", "policy", "create", "--from", "app=web", "--to", "app=db", "--port", "icmp", "policynet", "web-db")
+	dockerCmd(c, "exec", "web", "ping", "-c", "1", "-W", "1", dbIP)
+
+	// the policy only allows the traffic from web to db
+	_, _, err = dockerCmdWithError("exec", "db", "ping", "-c", "1", "-W", "1", webIP)
+	c.Assert(err, check.NotNil)
+
+	// the policy is programmed again when the container is restarted
+	dockerCmd(c, "restart", "db")
+	c.Assert(waitRun("db"), check.IsNil)
+	dbIP = inspectField(c, "db", "NetworkSettings.Networks.policynet.IPAddress")
+	dockerCmd(c, "exec", "web", "ping", "-c", "1", "-W", "1", dbIP)
+
+	dockerCmd(c, "network", "policy", "rm", "policynet", "web-db")
+	_, _, err = dockerCmdWithError("exec", "web", "ping", "-c", "1", "-W", "1", dbIP)
+	c.Assert(err, check.NotNil)
+}
+
 func (s *DockerNetworkSuite) TestDockerNetworkConnectDisconnect(c *check.C) {
 	dockerCmd(c, "network", "create", "test")
 	assertNwIsAvailable(c, "test")
@@ -384,6 +384,17 @@ func (s *DockerRegistrySuite) TestPullManifestList(c *check.C) {
 	// Was the image actually created?
 	dockerCmd(c, "inspect", repoName)
 
+	// The platform of the daemon is also the one pulled by --platform.
+	dockerCmd(c, "pull", "--platform", runtime.GOOS+"/"+runtime.GOARCH, repoName)
+
+	out, _, err = dockerCmdWithError("pull", "--platform", runtime.GOOS+"/bogus_arch", repoName)
+	c.Assert(err, checker.NotNil, check.Commentf("pulling a platform missing from the manifest list should fail"))
+	c.Assert(out, checker.Contains, "no manifest for platform "+runtime.GOOS+"/bogus_arch found in manifest list")
+
+	out, _, err = dockerCmdWithError("pull", "--platform", runtime.GOOS, repoName)
+	c.Assert(err, checker.NotNil, check.Commentf("pulling an invalid platform should fail"))
+	c.Assert(out, checker.Contains, "invalid platform")
+
 	dockerCmd(c, "rmi", repoName)
 }
 
@@ -828,6 +828,28 @@ func (s *DockerSuite) TestRunTmpfsMounts(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestRunMountVolumeSubpath(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "run", "-v", "subpathvol:/data", "busybox", "sh", "-c", "mkdir /data/config && echo hello > /data/config/app.conf && echo secret > /data/secret && ln -s / /data/config/root")
+
+	out, _ := dockerCmd(c, "run", "--mount", "type=volume,src=subpathvol,dst=/etc/app,volume-subpath=config,readonly", "busybox", "sh", "-c", "cat /etc/app/app.conf; ls /etc/app/root/")
+	c.Assert(out, checker.Contains, "hello")
+	c.Assert(out, checker.Not(checker.Contains), "secret")
+
+	out, _, err := dockerCmdWithError("run", "--mount", "type=volume,src=subpathvol,dst=/etc/app,volume-subpath=config,readonly", "busybox", "touch", "/etc/app/new")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Read-only file system")
+
+	// the symlink cannot be used to mount a directory out of the volume
+	out, _, err = dockerCmdWithError("run", "--mount", "type=volume,src=subpathvol,dst=/etc/app,volume-subpath=config/root/etc", "busybox", "true")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "cannot access volume subpath")
+
+	out, _, err = dockerCmdWithError("run", "--mount", "type=volume,src=subpathvol,dst=/etc/app,volume-subpath=../subpathvol", "busybox", "true")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "invalid volume subpath")
+}
+
 func (s *DockerSuite) TestRunTmpfsMountsOverrideImageVolumes(c *check.C) {
 	name := "img-with-volumes"
 	_, err := buildImage(
@@ -380,3 +380,38 @@ func (s *DockerSuite) TestSaveLoadNoTag(c *check.C) {
 	c.Assert(out, checker.Contains, "Loaded image: "+name+":latest")
 	c.Assert(out, checker.Not(checker.Contains), "Loaded image ID:")
 }
+
+func (s *DockerSuite) TestSaveOCIAndLoad(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	name := "saveloadoci"
+	_, err := buildImage(name, "FROM busybox\nENV foo=bar", true)
+	c.Assert(err, checker.IsNil, check.Commentf("%v", err))
+
+	id := inspectField(c, name, "Id")
+	diffIDs := inspectField(c, name, "RootFS.Layers")
+
+	tmpDir, err := ioutil.TempDir("", "save-oci-test")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+
+	tarPath := filepath.Join(tmpDir, "image.tar")
+	dockerCmd(c, "save", "--format", "oci", "-o", tarPath, name)
+
+	out, _, err := runCommandWithOutput(exec.Command("tar", "-tf", tarPath))
+	c.Assert(err, checker.IsNil, check.Commentf("%s", out))
+	c.Assert(out, checker.Contains, "oci-layout")
+	c.Assert(out, checker.Contains, "index.json")
+	c.Assert(out, checker.Not(checker.Contains), "manifest.json")
+	// The configuration is stored under the image ID and the layers under
+	// their DiffID.
+	c.Assert(out, checker.Contains, "blobs/sha256/"+strings.TrimPrefix(id, "sha256:"))
+	for _, diffID := range strings.Fields(strings.Trim(diffIDs, "[]")) {
+		c.Assert(out, checker.Contains, "blobs/sha256/"+strings.TrimPrefix(diffID, "sha256:"))
+	}
+
+	deleteImages(name)
+	out, _ = dockerCmd(c, "load", "-i", tarPath)
+	c.Assert(out, checker.Contains, "Loaded image: "+name+":latest")
+	c.Assert(inspectField(c, name, "Id"), checker.Equals, id)
+}
@@ -215,3 +215,20 @@ func (s *DockerSuite) TestUpdateStats(c *check.C) {
 	c.Assert(preMemLimit, checker.Equals, curMemLimit)
 
 }
+
+func (s *DockerSuite) TestUpdateNetworkRates(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace)
+
+	name := "test-update-container"
+	dockerCmd(c, "run", "-d", "--name", name, "--network-egress-rate", "1m", "busybox", "top")
+	c.Assert(waitRun(name), checker.IsNil)
+	c.Assert(inspectField(c, name, "HostConfig.NetworkEgressRate"), checker.Equals, "1048576")
+
+	dockerCmd(c, "update", "--network-ingress-rate", "512k", "--network-egress-rate", "-1", name)
+	c.Assert(inspectField(c, name, "HostConfig.NetworkIngressRate"), checker.Equals, "524288")
+	c.Assert(inspectField(c, name, "HostConfig.NetworkEgressRate"), checker.Equals, "0")
+
+	out, _, err := dockerCmdWithError("update", "--network-ingress-rate", "100", name)
+	c.Assert(err, check.NotNil)
+	c.Assert(out, checker.Contains, "Minimum network ingress rate allowed is 1KB per second")
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/pkg/integration/checker"
 	"github.com/go-check/check"
@@ -158,6 +159,42 @@ func (s *DockerSuite) TestVolumeCliLsFilterDangling(c *check.C) {
 
 }
 
+func (s *DockerSuite) TestVolumeCliLsFilterSinceAndUnusedFor(c *check.C) {
+	prefix, _ := getPrefixAndSlashFromDaemonPlatform()
+	dockerCmd(c, "volume", "create", "--name", "testold")
+	time.Sleep(2 * time.Second)
+	dockerCmd(c, "volume", "create", "--name", "testnew")
+	dockerCmd(c, "run", "-d", "--name", "volume-test-unused", "-v", "testnew:"+prefix+"/foo", "busybox", "top")
+
+	out, _ := dockerCmd(c, "volume", "ls", "--filter", "since=1s")
+	c.Assert(out, checker.Contains, "testnew\n")
+	c.Assert(out, check.Not(checker.Contains), "testold\n")
+
+	// testnew is mounted by a running container, it is in use
+	out, _ = dockerCmd(c, "volume", "ls", "--filter", "unused-for=1s")
+	c.Assert(out, checker.Contains, "testold\n")
+	c.Assert(out, check.Not(checker.Contains), "testnew\n")
+
+	out, _ = dockerCmd(c, "volume", "inspect", "--format", "{{ .CreatedAt }}", "testold")
+	c.Assert(strings.TrimSpace(out), checker.Not(checker.Equals), "")
+
+	out, _, err := dockerCmdWithError("volume", "ls", "-f", "unused-for=invalid")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Invalid filter")
+}
+
+func (s *DockerSuite) TestVolumeCliInspectSize(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "volume", "create", "--name", "testsize")
+	dockerCmd(c, "run", "--rm", "-v", "testsize:/foo", "busybox", "dd", "if=/dev/zero", "of=/foo/bar", "bs=1k", "count=100")
+
+	out, _ := dockerCmd(c, "volume", "inspect", "--format", "{{ .UsageData }}", "testsize")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "<nil>")
+
+	out, _ = dockerCmd(c, "volume", "inspect", "--size", "--format", "{{ .UsageData.Size }} {{ .UsageData.RefCount }}", "testsize")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "102400 0")
+}
+
 func (s *DockerSuite) TestVolumeCliLsErrorWithInvalidFilterName(c *check.C) {
 	out, _, err := dockerCmdWithError("volume", "ls", "-f", "FOO=123")
 	c.Assert(err, checker.NotNil)
@@ -299,3 +336,36 @@ func (s *DockerSuite) TestVolumeCliCreateLabelMultiple(c *check.C) {
 		c.Assert(strings.TrimSpace(out), check.Equals, v)
 	}
 }
+
+func (s *DockerSuite) TestVolumeCliExportImport(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "volume", "create", "--name", "testexport")
+	dockerCmd(c, "run", "--rm", "-v", "testexport:/foo", "busybox", "sh", "-c", "echo hello > /foo/bar")
+	dockerCmd(c, "volume", "create", "--name", "testimport")
+
+	exportCmd := exec.Command(dockerBinary, "volume", "export", "testexport")
+	importCmd := exec.Command(dockerBinary, "volume", "import", "testimport")
+	out, _, err := runCommandPipelineWithOutput(exportCmd, importCmd)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, _ = dockerCmd(c, "run", "--rm", "-v", "testimport:/foo", "busybox", "cat", "/foo/bar")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "hello")
+}
+
+func (s *DockerSuite) TestVolumeCliClone(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "volume", "create", "--name", "testclone", "--label", "foo=bar")
+	dockerCmd(c, "run", "--rm", "-v", "testclone:/foo", "busybox", "sh", "-c", "echo hello > /foo/bar")
+
+	out, _ := dockerCmd(c, "volume", "clone", "testclone", "testclone2")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "testclone2")
+
+	out, _ = dockerCmd(c, "volume", "inspect", "--format={{ .Driver }} {{ .Labels.foo }}", "testclone2")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "local bar")
+
+	out, _ = dockerCmd(c, "run", "--rm", "-v", "testclone2:/foo", "busybox", "cat", "/foo/bar")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "hello")
+
+	_, _, err := dockerCmdWithError("volume", "clone", "testclone", "testclone2")
+	c.Assert(err, checker.NotNil)
+}
@@ -92,6 +92,11 @@ type Layer interface {
 	// tar stream used to create this layer.
 	DiffID() DiffID
 
+	// TarStreamFrom returns a tar archive stream of the changes of the
+	// layer chain since the layer parent, which must be in the chain. An
+	// empty parent returns the content of the whole chain.
+	TarStreamFrom(parent ChainID) (io.ReadCloser, error)
+
 	// Parent returns the next layer in the layer chain.
 	Parent() Layer
 
@@ -766,3 +766,80 @@ func TestTarStreamVerification(t *testing.T) {
 		t.Fatalf("wrong error returned from tarstream: %q", err)
 	}
 }
+
+func TestTarStreamFrom(t *testing.T) {
+	// TODO Windows: the windows graph driver only produces diffs to the
+	// direct parent of a layer.
+	if runtime.GOOS == "windows" {
+		t.Skip("Needs diffs to arbitrary parents")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(
+		newTestFile("/etc/hostname", []byte("base"), 0644),
+		newTestFile("/etc/removed", []byte("removed"), 0644),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer2, err := createLayer(ls, layer1.ChainID(), initWithFiles(
+		newTestFile("/etc/hostname", []byte("changed"), 0644),
+		newTestFile("/tmp/build", []byte("build"), 0644),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer3, err := createLayer(ls, layer2.ChainID(), func(root string) error {
+		if err := os.Remove(filepath.Join(root, "tmp/build")); err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(root, "etc/removed")); err != nil {
+			return err
+		}
+		return newTestFile("/usr/bin/app", []byte("app"), 0755).ApplyFile(root)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := layer3.TarStreamFrom(layer1.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	squashed, err := ls.Register(ts, layer1.ChainID())
+	ts.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := ls.CreateRWLayer(stringid.GenerateRandomID(), squashed.ChainID(), "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := m.Mount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ls.ReleaseRWLayer(m)
+	defer m.Unmount()
+
+	for name, expected := range map[string]string{"etc/hostname": "changed", "usr/bin/app": "app"} {
+		content, err := ioutil.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != expected {
+			t.Fatalf("expected %q in %s, got %q", expected, name, content)
+		}
+	}
+	for _, name := range []string{"etc/removed", "tmp/build"} {
+		if _, err := os.Stat(filepath.Join(root, name)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, got %v", name, err)
+		}
+	}
+
+	if _, err := layer3.TarStreamFrom(squashed.ChainID()); err == nil {
+		t.Fatal("expected an error for a layer outside of the chain")
+	}
+}
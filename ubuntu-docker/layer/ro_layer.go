@@ -43,6 +43,24 @@ func (rl *roLayer) TarStream() (io.ReadCloser, error) {
 	return rc, nil
 }
 
+// TarStreamFrom is computed by the graph driver, the changes are not
+// verified against a digest like those of TarStream.
+func (rl *roLayer) TarStreamFrom(parent ChainID) (io.ReadCloser, error) {
+	var parentCacheID string
+	if parent != "" {
+		for pl := rl.parent; pl != nil; pl = pl.parent {
+			if pl.chainID == parent {
+				parentCacheID = pl.cacheID
+				break
+			}
+		}
+		if parentCacheID == "" {
+			return nil, fmt.Errorf("layer %s is not a parent of layer %s", parent, rl.chainID)
+		}
+	}
+	return rl.layerStore.driver.Diff(rl.cacheID, parentCacheID)
+}
+
 func (rl *roLayer) ChainID() ChainID {
 	return rl.chainID
 }
@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/Sirupsen/logrus"
@@ -161,6 +162,29 @@ func xzDecompress(archive io.Reader) (io.ReadCloser, <-chan struct{}, error) {
 	return cmdStream(exec.Command(args[0], args[1:]...), archive)
 }
 
+var (
+	unpigzOnce sync.Once
+	unpigz     string
+)
+
+// unpigzPath returns the path of the unpigz binary, or an empty string if it
+// is not installed or its use is disabled with DOCKER_DISABLE_PIGZ. unpigz
+// decompresses gzip streams faster than compress/gzip, as it reads, writes
+// and checks the stream in separate threads.
+func unpigzPath() string {
+	unpigzOnce.Do(func() {
+		if os.Getenv("DOCKER_DISABLE_PIGZ") != "" {
+			return
+		}
+		if path, err := exec.LookPath("unpigz"); err == nil {
+			unpigz = path
+		} else {
+			logrus.Debugf("unpigz binary not found, falling back to compress/gzip: %v", err)
+		}
+	})
+	return unpigz
+}
+
 // DecompressStream decompresses the archive and returns a ReaderCloser with the decompressed archive.
 func DecompressStream(archive io.Reader) (io.ReadCloser, error) {
 	p := pools.BufioReader32KPool
@@ -182,6 +206,19 @@ func DecompressStream(archive io.Reader) (io.ReadCloser, error) {
 		readBufWrapper := p.NewReadCloserWrapper(buf, buf)
 		return readBufWrapper, nil
 	case Gzip:
+		if path := unpigzPath(); path != "" {
+			gzReader, chdone, err := cmdStream(exec.Command(path, "-d", "-c"), buf)
+			if err != nil {
+				return nil, err
+			}
+			readBufWrapper := p.NewReadCloserWrapper(buf, gzReader)
+			return ioutils.NewReadCloserWrapper(readBufWrapper, func() error {
+				// Stop unpigz if the stream wasn't read to the end.
+				gzReader.Close()
+				<-chdone
+				return readBufWrapper.Close()
+			}), nil
+		}
 		gzReader, err := gzip.NewReader(buf)
 		if err != nil {
 			return nil, err
@@ -199,6 +236,8 @@ func DecompressStream(archive io.Reader) (io.ReadCloser, error) {
 		}
 		readBufWrapper := p.NewReadCloserWrapper(buf, xzReader)
 		return ioutils.NewReadCloserWrapper(readBufWrapper, func() error {
+			// Stop xz if the stream wasn't read to the end.
+			xzReader.Close()
 			<-chdone
 			return readBufWrapper.Close()
 		}), nil
@@ -3,6 +3,7 @@ package archive
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -107,6 +109,62 @@ func TestDecompressStreamGzip(t *testing.T) {
 	}
 }
 
+func TestDecompressStreamGzipUnpigz(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unpigz not present in msys2")
+	}
+	// Stand in for unpigz with gzip, which takes the same arguments.
+	binDir, err := ioutil.TempDir("", "docker-test-unpigz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(binDir)
+	script := "#!/bin/sh\nexec gzip \"$@\"\n"
+	if err := ioutil.WriteFile(filepath.Join(binDir, "unpigz"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	unpigzOnce, unpigz = sync.Once{}, ""
+	defer func() { unpigzOnce, unpigz = sync.Once{}, "" }()
+	if path := unpigzPath(); path != filepath.Join(binDir, "unpigz") {
+		t.Fatalf("expected unpigz to be found in %s, got %q", binDir, path)
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	content := bytes.Repeat([]byte("layer data "), 10000)
+	gz.Write(content)
+	gz.Close()
+
+	rc, err := DecompressStream(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decompressed, content) {
+		t.Fatal("decompressed content doesn't match")
+	}
+
+	// A corrupted stream must fail the decompression.
+	corrupted := compressed.Bytes()
+	corrupted[len(corrupted)-5] ^= 0xff
+	rc, err = DecompressStream(bytes.NewReader(corrupted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadAll(rc); err == nil {
+		t.Fatal("expected the decompression of a corrupted stream to fail")
+	}
+	rc.Close()
+}
+
 func TestDecompressStreamBzip2(t *testing.T) {
 	cmd := exec.Command("sh", "-c", "touch /tmp/archive && bzip2 -f /tmp/archive")
 	output, err := cmd.CombinedOutput()
@@ -136,6 +194,38 @@ func TestDecompressStreamXz(t *testing.T) {
 	}
 }
 
+// TestDecompressStreamXzEarlyClose checks that closing a stream which wasn't
+// read to the end stops xz instead of waiting for it to exit.
+func TestDecompressStreamXzEarlyClose(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Xz not present in msys2")
+	}
+	cmd := exec.Command("xz", "-c")
+	cmd.Stdin = bytes.NewReader(bytes.Repeat([]byte("layer data "), 1<<20))
+	compressed, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Fail to create an xz stream for test: %v", err)
+	}
+
+	rc, err := DecompressStream(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rc.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	closed := make(chan error)
+	go func() {
+		closed <- rc.Close()
+	}()
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("closing the stream didn't stop xz")
+	}
+}
+
 func TestCompressStreamXzUnsuported(t *testing.T) {
 	dest, err := os.Create(tmp + "dest")
 	if err != nil {
@@ -81,6 +81,12 @@ func Pull(name string, rs registry.Service, metaheader http.Header, authConfig *
 		return nil, err
 	}
 
+	authConfig, err = rs.LookupAuthConfig(authConfig, repoInfo.Index)
+	if err != nil {
+		logrus.Debugf("pull.go: error in LookupAuthConfig: %v", err)
+		return nil, err
+	}
+
 	endpoints, err := rs.LookupPullEndpoints(repoInfo.Hostname())
 	if err != nil {
 		logrus.Debugf("pull.go: error in LookupPullEndpoints: %v", err)
@@ -34,6 +34,11 @@ func Push(name string, rs registry.Service, metaHeader http.Header, authConfig *
 		return "", err
 	}
 
+	authConfig, err = rs.LookupAuthConfig(authConfig, repoInfo.Index)
+	if err != nil {
+		return "", err
+	}
+
 	endpoints, err := rs.LookupPushEndpoints(repoInfo.Hostname())
 	if err != nil {
 		return "", err
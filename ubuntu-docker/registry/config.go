@@ -15,8 +15,13 @@ import (
 
 // ServiceOptions holds command line options.
 type ServiceOptions struct {
-	Mirrors            []string `json:"registry-mirrors,omitempty"`
-	InsecureRegistries []string `json:"insecure-registries,omitempty"`
+	Mirrors            []string            `json:"registry-mirrors,omitempty"`
+	MirrorsMap         map[string][]string `json:"registry-mirrors-map,omitempty"`
+	InsecureRegistries []string            `json:"insecure-registries,omitempty"`
+
+	// CredentialHelpers holds the name of the credential helper to get the
+	// credentials of a registry from, by registry.
+	CredentialHelpers map[string]string `json:"credential-helpers,omitempty"`
 
 	// V2Only controls access to legacy registries.  If it is set to true via the
 	// command line flag the daemon will not attempt to contact v1 legacy registries
@@ -27,6 +32,11 @@ type ServiceOptions struct {
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+	// MirrorsMap holds the mirrors of the registries other than the
+	// official one, by index name.
+	MirrorsMap map[string][]string
+	// CredentialHelpers holds the credential helpers by index name.
+	CredentialHelpers map[string]string
 }
 
 var (
@@ -74,9 +84,15 @@ func (options *ServiceOptions) InstallCliFlags(cmd *flag.FlagSet, usageFn func(s
 	mirrors := opts.NewNamedListOptsRef("registry-mirrors", &options.Mirrors, ValidateMirror)
 	cmd.Var(mirrors, []string{"-registry-mirror"}, usageFn("Preferred Docker registry mirror"))
 
+	mirrorsMap := &mirrorsMapOpts{values: &options.MirrorsMap}
+	cmd.Var(mirrorsMap, []string{"-registry-mirror-map"}, usageFn("Preferred mirror for a registry (registry=mirror)"))
+
 	insecureRegistries := opts.NewNamedListOptsRef("insecure-registries", &options.InsecureRegistries, ValidateIndexName)
 	cmd.Var(insecureRegistries, []string{"-insecure-registry"}, usageFn("Enable insecure registry communication"))
 
+	credentialHelpers := &credentialHelpersOpts{values: &options.CredentialHelpers}
+	cmd.Var(credentialHelpers, []string{"-credential-helper"}, usageFn("Credential helper for a registry (registry=helper)"))
+
 	cmd.BoolVar(&options.V2Only, []string{"-disable-legacy-registry"}, false, usageFn("Disable contacting legacy registries"))
 }
 
@@ -97,7 +113,19 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 			// and Mirrors are only for the official registry anyways.
 			Mirrors: options.Mirrors,
 		},
-		V2Only: options.V2Only,
+		V2Only:            options.V2Only,
+		MirrorsMap:        make(map[string][]string),
+		CredentialHelpers: make(map[string]string),
+	}
+	for indexName, mirrors := range options.MirrorsMap {
+		if indexName, err := ValidateIndexName(indexName); err == nil && indexName != IndexName {
+			config.MirrorsMap[indexName] = append(config.MirrorsMap[indexName], mirrors...)
+		}
+	}
+	for indexName, helper := range options.CredentialHelpers {
+		if indexName, err := ValidateIndexName(indexName); err == nil {
+			config.CredentialHelpers[indexName] = helper
+		}
 	}
 	// Split --insecure-registry into CIDR and registry-specific settings.
 	for _, r := range options.InsecureRegistries {
@@ -110,7 +138,7 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 			// Assume `host:port` if not CIDR.
 			config.IndexConfigs[r] = &registrytypes.IndexInfo{
 				Name:     r,
-				Mirrors:  make([]string, 0),
+				Mirrors:  indexMirrors(config, r),
 				Secure:   false,
 				Official: false,
 			}
@@ -195,6 +223,72 @@ func ValidateMirror(val string) (string, error) {
 	return fmt.Sprintf("%s://%s/", uri.Scheme, uri.Host), nil
 }
 
+// ValidateMirrorsMap validates the mirrors configured for each registry.
+// The mirrors of the official registry are set with --registry-mirror.
+func ValidateMirrorsMap(mirrorsMap map[string][]string) error {
+	for indexName, mirrors := range mirrorsMap {
+		indexName, err := ValidateIndexName(indexName)
+		if err != nil {
+			return err
+		}
+		if indexName == IndexName {
+			return fmt.Errorf("mirrors of %s must be set with registry-mirrors", IndexName)
+		}
+		for _, mirror := range mirrors {
+			if _, err := ValidateMirror(mirror); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// indexMirrors returns the mirrors configured for the non-official registry
+// indexName.
+func indexMirrors(config *serviceConfig, indexName string) []string {
+	mirrors := make([]string, 0)
+	return append(mirrors, config.MirrorsMap[indexName]...)
+}
+
+// mirrorsMapOpts is a flag value adding registry=mirror pairs to a map of
+// mirrors by registry.
+type mirrorsMapOpts struct {
+	values *map[string][]string
+}
+
+// Set validates a registry=mirror pair and adds it to the map.
+func (o *mirrorsMapOpts) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid registry mirror %q, expected registry=mirror", value)
+	}
+	indexName, err := ValidateIndexName(parts[0])
+	if err != nil {
+		return err
+	}
+	if indexName == IndexName {
+		return fmt.Errorf("mirrors of %s must be set with --registry-mirror", IndexName)
+	}
+	mirror, err := ValidateMirror(parts[1])
+	if err != nil {
+		return err
+	}
+	if *o.values == nil {
+		*o.values = make(map[string][]string)
+	}
+	(*o.values)[indexName] = append((*o.values)[indexName], mirror)
+	return nil
+}
+
+func (o *mirrorsMapOpts) String() string {
+	return fmt.Sprintf("%v", *o.values)
+}
+
+// Name returns the name of the option in the configuration file.
+func (o *mirrorsMapOpts) Name() string {
+	return "registry-mirrors-map"
+}
+
 // ValidateIndexName validates an index name.
 func ValidateIndexName(val string) (string, error) {
 	if val == reference.LegacyDefaultHostname {
@@ -230,7 +324,7 @@ func newIndexInfo(config *serviceConfig, indexName string) (*registrytypes.Index
 	// Construct a non-configured index info.
 	index := &registrytypes.IndexInfo{
 		Name:     indexName,
-		Mirrors:  make([]string, 0),
+		Mirrors:  indexMirrors(config, indexName),
 		Official: false,
 	}
 	index.Secure = isSecureIndex(config, indexName)
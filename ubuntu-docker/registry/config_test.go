@@ -47,3 +47,24 @@ func TestValidateMirror(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateMirrorsMap(t *testing.T) {
+	valid := map[string][]string{
+		"registry.corp:5000": {"https://mirror.corp", "http://mirror.corp:5001"},
+	}
+	if err := ValidateMirrorsMap(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []map[string][]string{
+		{"registry.corp": {"ftp://mirror.corp"}},
+		{"registry.corp": {"https://mirror.corp/path"}},
+		{"-registry.corp": {"https://mirror.corp"}},
+		{"docker.io": {"https://mirror.corp"}},
+	}
+	for _, mirrorsMap := range invalid {
+		if err := ValidateMirrorsMap(mirrorsMap); err == nil {
+			t.Errorf("expected %v to be invalid", mirrorsMap)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+	"github.com/docker/engine-api/types"
+	registrytypes "github.com/docker/engine-api/types/registry"
+)
+
+const (
+	// credentialHelperPrefix is the prefix of the names of the credential
+	// helper binaries, looked up in the PATH of the daemon.
+	credentialHelperPrefix = "docker-credential-"
+
+	// tokenUsername is the username credential helpers return with an
+	// identity token as secret.
+	tokenUsername = "<token>"
+)
+
+// LookupAuthConfig returns the credentials to use with the registry index.
+// When a credential helper is configured for the registry, the credentials
+// are asked to the helper on each call, so that expiring tokens are
+// refreshed, and take precedence over authConfig, the credentials of the
+// request. authConfig is returned otherwise, or when the helper has no
+// credentials for the registry.
+func (s *DefaultService) LookupAuthConfig(authConfig *types.AuthConfig, index *registrytypes.IndexInfo) (*types.AuthConfig, error) {
+	helper, ok := s.config.CredentialHelpers[index.Name]
+	if !ok {
+		return authConfig, nil
+	}
+
+	serverAddress := GetAuthConfigKey(index)
+	creds, err := client.Get(client.NewShellProgramFunc(credentialHelperPrefix+helper), serverAddress)
+	if err != nil {
+		if credentials.IsErrCredentialsNotFound(err) {
+			return authConfig, nil
+		}
+		return nil, fmt.Errorf("credential helper %s failed for %s: %v", helper, index.Name, err)
+	}
+
+	resolved := &types.AuthConfig{ServerAddress: serverAddress}
+	if creds.Username == tokenUsername {
+		resolved.IdentityToken = creds.Secret
+	} else {
+		resolved.Username = creds.Username
+		resolved.Password = creds.Secret
+	}
+	return resolved, nil
+}
+
+// ValidateCredentialHelpers validates the credential helpers of the
+// configuration file, keyed by registry.
+func ValidateCredentialHelpers(helpers map[string]string) error {
+	for indexName, helper := range helpers {
+		if _, err := ValidateIndexName(indexName); err != nil {
+			return err
+		}
+		if err := validateCredentialHelper(helper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCredentialHelper checks that helper is the name of a credential
+// helper without the docker-credential- prefix, like "ecr-login".
+func validateCredentialHelper(helper string) error {
+	if helper == "" || strings.ContainsAny(helper, "/\\") {
+		return fmt.Errorf("invalid credential helper %q", helper)
+	}
+	return nil
+}
+
+// credentialHelpersOpts is a flag value adding registry=helper pairs to a
+// map of credential helpers by registry.
+type credentialHelpersOpts struct {
+	values *map[string]string
+}
+
+// Set validates a registry=helper pair and adds it to the map.
+func (o *credentialHelpersOpts) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid credential helper %q, expected registry=helper", value)
+	}
+	indexName, err := ValidateIndexName(parts[0])
+	if err != nil {
+		return err
+	}
+	if err := validateCredentialHelper(parts[1]); err != nil {
+		return err
+	}
+	if *o.values == nil {
+		*o.values = make(map[string]string)
+	}
+	(*o.values)[indexName] = parts[1]
+	return nil
+}
+
+func (o *credentialHelpersOpts) String() string {
+	return fmt.Sprintf("%v", *o.values)
+}
+
+// Name returns the name of the option in the configuration file.
+func (o *credentialHelpersOpts) Name() string {
+	return "credential-helpers"
+}
@@ -0,0 +1,135 @@
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+// stubCredentialHelper answers the get requests of the credential helper
+// protocol with fixed credentials for two registries.
+const stubCredentialHelper = `#!/bin/sh
+[ "$1" = get ] || exit 1
+read server
+case "$server" in
+registry.corp:5000)
+	echo '{"ServerURL": "registry.corp:5000", "Username": "helper", "Secret": "fresh"}'
+	;;
+https://index.docker.io/v1/)
+	echo '{"ServerURL": "https://index.docker.io/v1/", "Username": "<token>", "Secret": "token"}'
+	;;
+*)
+	echo "credentials not found in native keychain"
+	exit 1
+	;;
+esac
+`
+
+func TestLookupAuthConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub credential helper is a shell script")
+	}
+	tmpDir, err := ioutil.TempDir("", "docker-credential-helper-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, "docker-credential-stub"), []byte(stubCredentialHelper), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	s := NewService(ServiceOptions{
+		CredentialHelpers: map[string]string{
+			"registry.corp:5000": "stub",
+			"index.docker.io":    "stub",
+			"other.corp":         "stub",
+			"broken.corp":        "missing",
+		},
+	})
+	requestAuth := &types.AuthConfig{Username: "request", Password: "expired"}
+
+	index, err := s.ResolveIndex("registry.corp:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authConfig, err := s.LookupAuthConfig(requestAuth, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authConfig.Username != "helper" || authConfig.Password != "fresh" || authConfig.ServerAddress != "registry.corp:5000" {
+		t.Fatalf("expected the credentials of the helper, got %+v", authConfig)
+	}
+
+	index, err = s.ResolveIndex("docker.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authConfig, err = s.LookupAuthConfig(requestAuth, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authConfig.IdentityToken != "token" || authConfig.Username != "" {
+		t.Fatalf("expected the identity token of the helper, got %+v", authConfig)
+	}
+
+	// The helper has no credentials for other.corp.
+	index, err = s.ResolveIndex("other.corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authConfig, err = s.LookupAuthConfig(requestAuth, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authConfig != requestAuth {
+		t.Fatalf("expected the credentials of the request, got %+v", authConfig)
+	}
+
+	// No helper is configured for unknown.corp.
+	index, err = s.ResolveIndex("unknown.corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authConfig, err = s.LookupAuthConfig(requestAuth, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authConfig != requestAuth {
+		t.Fatalf("expected the credentials of the request, got %+v", authConfig)
+	}
+
+	index, err = s.ResolveIndex("broken.corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LookupAuthConfig(requestAuth, index); err == nil {
+		t.Fatal("expected an error from a missing credential helper")
+	}
+}
+
+func TestValidateCredentialHelpers(t *testing.T) {
+	valid := map[string]string{
+		"registry.corp:5000": "ecr-login",
+		"docker.io":          "secretservice",
+	}
+	if err := ValidateCredentialHelpers(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []map[string]string{
+		{"registry.corp": ""},
+		{"registry.corp": "../bin/sh"},
+		{"-registry.corp": "ecr-login"},
+	}
+	for _, helpers := range invalid {
+		if err := ValidateCredentialHelpers(helpers); err == nil {
+			t.Errorf("expected %v to be invalid", helpers)
+		}
+	}
+}
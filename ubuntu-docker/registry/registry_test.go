@@ -684,6 +684,46 @@ func TestMirrorEndpointLookup(t *testing.T) {
 	}
 }
 
+func TestRegistryMirrorEndpointLookup(t *testing.T) {
+	s := DefaultService{config: newServiceConfig(ServiceOptions{
+		MirrorsMap: map[string][]string{"registry.corp:5000": {"https://my.mirror"}},
+	})}
+
+	pullAPIEndpoints, err := s.LookupPullEndpoints("registry.corp:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pullAPIEndpoints) < 2 {
+		t.Fatalf("expected the mirror and the registry, got %d endpoints", len(pullAPIEndpoints))
+	}
+	if pe := pullAPIEndpoints[0]; !pe.Mirror || pe.URL.Host != "my.mirror" {
+		t.Fatalf("expected the mirror to be tried first, got %s", pe.URL)
+	}
+	if pe := pullAPIEndpoints[1]; pe.Mirror || pe.URL.Host != "registry.corp:5000" {
+		t.Fatalf("expected the registry to be tried after the mirror, got %s", pe.URL)
+	}
+
+	pushAPIEndpoints, err := s.LookupPushEndpoints("registry.corp:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pe := range pushAPIEndpoints {
+		if pe.Mirror {
+			t.Fatal("Push endpoint should not contain mirror")
+		}
+	}
+
+	pullAPIEndpoints, err = s.LookupPullEndpoints("other.corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pe := range pullAPIEndpoints {
+		if pe.Mirror {
+			t.Fatalf("Pull endpoint of other.corp should not contain mirror, got %s", pe.URL)
+		}
+	}
+}
+
 func TestPushRegistryTag(t *testing.T) {
 	r := spawnTestRegistrySession(t)
 	repoRef, err := reference.ParseNamed(REPO)
@@ -0,0 +1,59 @@
+package opts
+
+import (
+	"strings"
+	"testing"
+
+	mounttypes "github.com/docker/engine-api/types/mount"
+)
+
+func TestMountOptVolumeSubpath(t *testing.T) {
+	var m MountOpt
+	if err := m.Set("type=volume,src=data,dst=/etc/app,volume-subpath=config,readonly"); err != nil {
+		t.Fatal(err)
+	}
+	mounts := m.Value()
+	if len(mounts) != 1 {
+		t.Fatalf("Expected 1 mount, got %d", len(mounts))
+	}
+	mount := mounts[0]
+	if mount.Type != mounttypes.TypeVolume || mount.Source != "data" || mount.Target != "/etc/app" || !mount.ReadOnly {
+		t.Fatalf("Unexpected mount %+v", mount)
+	}
+	if mount.VolumeOptions == nil || mount.VolumeOptions.Subpath != "config" {
+		t.Fatalf("Expected the subpath config, got %+v", mount.VolumeOptions)
+	}
+}
+
+func TestMountOptBind(t *testing.T) {
+	var m MountOpt
+	if err := m.Set("type=bind,source=/source,target=/target,bind-propagation=rshared"); err != nil {
+		t.Fatal(err)
+	}
+	mount := m.Value()[0]
+	if mount.Type != mounttypes.TypeBind || mount.Source != "/source" || mount.Target != "/target" || mount.ReadOnly {
+		t.Fatalf("Unexpected mount %+v", mount)
+	}
+	if mount.BindOptions == nil || mount.BindOptions.Propagation != mounttypes.PropagationRShared {
+		t.Fatalf("Expected the rshared propagation, got %+v", mount.BindOptions)
+	}
+}
+
+func TestMountOptErrors(t *testing.T) {
+	cases := map[string]string{
+		"type=volume,src=data":                                  "target is required",
+		"type=tmpfs,dst=/foo":                                   "invalid mount type",
+		"type=bind,dst=/foo":                                    "source is required",
+		"type=volume,dst=/foo,volume-subpath=config":            "source is required",
+		"type=bind,src=/foo,dst=/foo,volume-subpath=config":     "cannot mix",
+		"type=volume,src=data,dst=/foo,bind-propagation=shared": "cannot mix",
+		"type=volume,src=data,dst=/foo,readonly=no":             "invalid value for readonly",
+		"type=volume,src=data,dst=/foo,bogus=foo":               "unexpected key 'bogus'",
+	}
+	for value, expected := range cases {
+		var m MountOpt
+		if err := m.Set(value); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected an error with %q for %q, got %v", expected, value, err)
+		}
+	}
+}
@@ -27,6 +27,7 @@ type ContainerOptions struct {
 	flAttach            opts.ListOpts
 	flVolumes           opts.ListOpts
 	flTmpfs             opts.ListOpts
+	flMounts            MountOpt
 	flBlkioWeightDevice WeightdeviceOpt
 	flDeviceReadBps     ThrottledeviceOpt
 	flDeviceWriteBps    ThrottledeviceOpt
@@ -71,6 +72,8 @@ type ContainerOptions struct {
 	flMemoryReservation string
 	flMemorySwap        string
 	flKernelMemory      string
+	flNetworkIngress    string
+	flNetworkEgress     string
 	flUser              string
 	flWorkingDir        string
 	flCPUShares         int64
@@ -199,6 +202,7 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.Var(&copts.flLoggingOpts, "log-opt", "Log driver options")
 	flags.Var(&copts.flStorageOpt, "storage-opt", "Storage driver options for the container")
 	flags.Var(&copts.flTmpfs, "tmpfs", "Mount a tmpfs directory")
+	flags.Var(&copts.flMounts, "mount", "Attach a filesystem mount to the container")
 	flags.Var(&copts.flVolumesFrom, "volumes-from", "Mount volumes from the specified container(s)")
 	flags.VarP(&copts.flVolumes, "volume", "v", "Bind mount a volume")
 
@@ -230,6 +234,8 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.StringVar(&copts.flMemoryReservation, "memory-reservation", "", "Memory soft limit")
 	flags.StringVar(&copts.flMemorySwap, "memory-swap", "", "Swap limit equal to memory plus swap: '-1' to enable unlimited swap")
 	flags.Int64Var(&copts.flSwappiness, "memory-swappiness", -1, "Tune container memory swappiness (0 to 100)")
+	flags.StringVar(&copts.flNetworkEgress, "network-egress-rate", "", "Limit the rate (bytes per second) of the network traffic sent by the container")
+	flags.StringVar(&copts.flNetworkIngress, "network-ingress-rate", "", "Limit the rate (bytes per second) of the network traffic received by the container")
 	flags.BoolVar(&copts.flOomKillDisable, "oom-kill-disable", false, "Disable OOM Killer")
 	flags.IntVar(&copts.flOomScoreAdj, "oom-score-adj", 0, "Tune host's OOM preferences (-1000 to 1000)")
 	flags.Int64Var(&copts.flPidsLimit, "pids-limit", 0, "Tune container pids limit (set -1 for unlimited)")
@@ -308,6 +314,22 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		}
 	}
 
+	var networkIngressRate int64
+	if copts.flNetworkIngress != "" {
+		networkIngressRate, err = units.RAMInBytes(copts.flNetworkIngress)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var networkEgressRate int64
+	if copts.flNetworkEgress != "" {
+		networkEgressRate, err = units.RAMInBytes(copts.flNetworkEgress)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
 	swappiness := copts.flSwappiness
 	if swappiness != -1 && (swappiness < 0 || swappiness > 100) {
 		return nil, nil, nil, fmt.Errorf("invalid value: %d. Valid memory swappiness range is 0-100", swappiness)
@@ -499,6 +521,8 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		MemorySwap:           memorySwap,
 		MemorySwappiness:     &copts.flSwappiness,
 		KernelMemory:         KernelMemory,
+		NetworkIngressRate:   networkIngressRate,
+		NetworkEgressRate:    networkEgressRate,
 		OomKillDisable:       &copts.flOomKillDisable,
 		CPUPercent:           copts.flCPUPercent,
 		CPUShares:            copts.flCPUShares,
@@ -564,6 +588,7 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		DNSOptions:     copts.flDNSOptions.GetAllOrEmpty(),
 		ExtraHosts:     copts.flExtraHosts.GetAll(),
 		VolumesFrom:    copts.flVolumesFrom.GetAll(),
+		Mounts:         copts.flMounts.Value(),
 		NetworkMode:    container.NetworkMode(copts.flNetMode),
 		IpcMode:        ipcMode,
 		PidMode:        pidMode,
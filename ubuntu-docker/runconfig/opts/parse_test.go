@@ -389,6 +389,22 @@ func TestParseWithMemorySwap(t *testing.T) {
 	}
 }
 
+func TestParseWithNetworkRates(t *testing.T) {
+	if _, _, _, err := parseRun([]string{"--network-ingress-rate=invalid", "img", "cmd"}); err == nil || err.Error() != "invalid size: 'invalid'" {
+		t.Fatalf("Expected an error with an invalid NetworkIngressRate, got '%v'", err)
+	}
+	if _, _, _, err := parseRun([]string{"--network-egress-rate=-1", "img", "cmd"}); err == nil {
+		t.Fatalf("Expected an error with a negative NetworkEgressRate")
+	}
+	_, hostconfig := mustParse(t, "--network-ingress-rate=10m --network-egress-rate=512k")
+	if hostconfig.NetworkIngressRate != 10485760 {
+		t.Fatalf("Expected the config to have '10485760' as NetworkIngressRate, got '%v'", hostconfig.NetworkIngressRate)
+	}
+	if hostconfig.NetworkEgressRate != 524288 {
+		t.Fatalf("Expected the config to have '524288' as NetworkEgressRate, got '%v'", hostconfig.NetworkEgressRate)
+	}
+}
+
 func TestParseHostname(t *testing.T) {
 	validHostnames := map[string]string{
 		"hostname":    "hostname",
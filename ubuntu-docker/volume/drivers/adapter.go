@@ -3,6 +3,7 @@ package volumedrivers
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/volume"
@@ -81,6 +82,52 @@ func (a *volumeDriverAdapter) Scope() string {
 	return cap.Scope
 }
 
+func (a *volumeDriverAdapter) Snapshot(v volume.Volume, name string) error {
+	if !a.getCapabilities().Snapshots {
+		return volume.ErrSnapshotsNotSupported
+	}
+	return a.proxy.Snapshot(v.Name(), name)
+}
+
+func (a *volumeDriverAdapter) ListSnapshots(v volume.Volume) ([]volume.Snapshot, error) {
+	if !a.getCapabilities().Snapshots {
+		return nil, volume.ErrSnapshotsNotSupported
+	}
+	ls, err := a.proxy.ListSnapshots(v.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var out []volume.Snapshot
+	for _, sp := range ls {
+		s := volume.Snapshot{Name: sp.Name}
+		if sp.CreatedAt != "" {
+			// CreatedAt is optional, an invalid value is reported as unknown.
+			createdAt, err := time.Parse(time.RFC3339Nano, sp.CreatedAt)
+			if err != nil {
+				logrus.Warnf("Volume driver %s returned an invalid creation time for snapshot %s of volume %s: %q", a.name, sp.Name, v.Name(), sp.CreatedAt)
+			}
+			s.CreatedAt = createdAt
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (a *volumeDriverAdapter) RestoreSnapshot(v volume.Volume, name string) error {
+	if !a.getCapabilities().Snapshots {
+		return volume.ErrSnapshotsNotSupported
+	}
+	return a.proxy.RestoreSnapshot(v.Name(), name)
+}
+
+func (a *volumeDriverAdapter) DeleteSnapshot(v volume.Volume, name string) error {
+	if !a.getCapabilities().Snapshots {
+		return volume.ErrSnapshotsNotSupported
+	}
+	return a.proxy.DeleteSnapshot(v.Name(), name)
+}
+
 func (a *volumeDriverAdapter) getCapabilities() volume.Capability {
 	if a.capabilities != nil {
 		return *a.capabilities
@@ -122,6 +169,11 @@ type proxyVolume struct {
 	Status     map[string]interface{}
 }
 
+type proxySnapshot struct {
+	Name      string
+	CreatedAt string // RFC 3339 time the snapshot was taken at, optional
+}
+
 func (a *volumeAdapter) Name() string {
 	return a.name
 }
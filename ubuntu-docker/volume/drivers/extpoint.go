@@ -44,6 +44,14 @@ type volumeDriver interface {
 	Get(name string) (volume *proxyVolume, err error)
 	// Capabilities gets the list of capabilities of the driver
 	Capabilities() (capabilities volume.Capability, err error)
+	// Snapshot takes a snapshot of the given volume
+	Snapshot(name, snapshot string) (err error)
+	// ListSnapshots lists the snapshots of the given volume
+	ListSnapshots(name string) (snapshots []*proxySnapshot, err error)
+	// RestoreSnapshot reverts the given volume to the given snapshot
+	RestoreSnapshot(name, snapshot string) (err error)
+	// DeleteSnapshot deletes the given snapshot of the given volume
+	DeleteSnapshot(name, snapshot string) (err error)
 }
 
 type driverExtpoint struct {
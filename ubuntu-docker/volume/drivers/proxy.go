@@ -239,3 +239,116 @@ func (pp *volumeDriverProxy) Capabilities() (capabilities volume.Capability, err
 
 	return
 }
+
+type volumeDriverProxySnapshotRequest struct {
+	Name     string
+	Snapshot string
+}
+
+type volumeDriverProxySnapshotResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) Snapshot(name string, snapshot string) (err error) {
+	var (
+		req volumeDriverProxySnapshotRequest
+		ret volumeDriverProxySnapshotResponse
+	)
+
+	req.Name = name
+	req.Snapshot = snapshot
+	if err = pp.Call("VolumeDriver.Snapshot", req, &ret); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyListSnapshotsRequest struct {
+	Name string
+}
+
+type volumeDriverProxyListSnapshotsResponse struct {
+	Snapshots []*proxySnapshot
+	Err       string
+}
+
+func (pp *volumeDriverProxy) ListSnapshots(name string) (snapshots []*proxySnapshot, err error) {
+	var (
+		req volumeDriverProxyListSnapshotsRequest
+		ret volumeDriverProxyListSnapshotsResponse
+	)
+
+	req.Name = name
+	if err = pp.Call("VolumeDriver.ListSnapshots", req, &ret); err != nil {
+		return
+	}
+
+	snapshots = ret.Snapshots
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyRestoreSnapshotRequest struct {
+	Name     string
+	Snapshot string
+}
+
+type volumeDriverProxyRestoreSnapshotResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) RestoreSnapshot(name string, snapshot string) (err error) {
+	var (
+		req volumeDriverProxyRestoreSnapshotRequest
+		ret volumeDriverProxyRestoreSnapshotResponse
+	)
+
+	req.Name = name
+	req.Snapshot = snapshot
+	if err = pp.Call("VolumeDriver.RestoreSnapshot", req, &ret); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyDeleteSnapshotRequest struct {
+	Name     string
+	Snapshot string
+}
+
+type volumeDriverProxyDeleteSnapshotResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) DeleteSnapshot(name string, snapshot string) (err error) {
+	var (
+		req volumeDriverProxyDeleteSnapshotRequest
+		ret volumeDriverProxyDeleteSnapshotResponse
+	)
+
+	req.Name = name
+	req.Snapshot = snapshot
+	if err = pp.Call("VolumeDriver.DeleteSnapshot", req, &ret); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
@@ -57,6 +57,26 @@ func TestVolumeRequestError(t *testing.T) {
 		http.Error(w, "error", 500)
 	})
 
+	mux.HandleFunc("/VolumeDriver.Snapshot", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		fmt.Fprintln(w, `{"Err": "Cannot snapshot volume"}`)
+	})
+
+	mux.HandleFunc("/VolumeDriver.ListSnapshots", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		fmt.Fprintln(w, `{"Err": "Cannot list snapshots"}`)
+	})
+
+	mux.HandleFunc("/VolumeDriver.RestoreSnapshot", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		fmt.Fprintln(w, `{"Err": "Cannot restore snapshot"}`)
+	})
+
+	mux.HandleFunc("/VolumeDriver.DeleteSnapshot", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		fmt.Fprintln(w, `{"Err": "Cannot delete snapshot"}`)
+	})
+
 	u, _ := url.Parse(server.URL)
 	client, err := plugins.NewClient("tcp://"+u.Host, &tlsconfig.Options{InsecureSkipVerify: true})
 	if err != nil {
@@ -129,4 +149,36 @@ func TestVolumeRequestError(t *testing.T) {
 	if err == nil {
 		t.Fatal(err)
 	}
+
+	err = driver.Snapshot("volume", "snap")
+	if err == nil {
+		t.Fatal("Expected error, was nil")
+	}
+	if !strings.Contains(err.Error(), "Cannot snapshot volume") {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	_, err = driver.ListSnapshots("volume")
+	if err == nil {
+		t.Fatal("Expected error, was nil")
+	}
+	if !strings.Contains(err.Error(), "Cannot list snapshots") {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	err = driver.RestoreSnapshot("volume", "snap")
+	if err == nil {
+		t.Fatal("Expected error, was nil")
+	}
+	if !strings.Contains(err.Error(), "Cannot restore snapshot") {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	err = driver.DeleteSnapshot("volume", "snap")
+	if err == nil {
+		t.Fatal("Expected error, was nil")
+	}
+	if !strings.Contains(err.Error(), "Cannot delete snapshot") {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
 }
@@ -0,0 +1,267 @@
+package local
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/volume"
+)
+
+// The subvolumes are created and removed with the ioctls of linux/btrfs.h,
+// which only need the kernel module, not the btrfs headers or tools.
+const (
+	btrfsSuperMagic = 0x9123683E
+
+	btrfsIocSubvolCreate = 0x5000940e
+	btrfsIocSnapDestroy  = 0x5000940f
+	btrfsIocSnapCreateV2 = 0x50009417
+	btrfsSubvolRdonly    = 1 << 1
+
+	btrfsPathNameMax   = 4087
+	btrfsSubvolNameMax = 4039
+
+	// btrfsFirstFreeObjectID is the inode number of the top directory of
+	// a subvolume.
+	btrfsFirstFreeObjectID = 256
+)
+
+// snapshotsPathName is the name of the directory next to the data directory
+// of a volume where its snapshots are stored.
+const snapshotsPathName = "_snapshots"
+
+// btrfsVolArgs is struct btrfs_ioctl_vol_args of linux/btrfs.h.
+type btrfsVolArgs struct {
+	fd   int64
+	name [btrfsPathNameMax + 1]byte
+}
+
+// btrfsVolArgsV2 is struct btrfs_ioctl_vol_args_v2 of linux/btrfs.h.
+type btrfsVolArgsV2 struct {
+	fd      int64
+	transid uint64
+	flags   uint64
+	unused  [4]uint64
+	name    [btrfsSubvolNameMax + 1]byte
+}
+
+// btrfsSnapshotter creates the data directory of a volume as a btrfs
+// subvolume, and takes its snapshots as read-only subvolumes stored in
+// _snapshots/<name>/_data. The modification time of _snapshots/<name> is the
+// time the snapshot was taken.
+type btrfsSnapshotter struct{}
+
+func btrfsIoctl(dir string, cmd uintptr, args unsafe.Pointer) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), cmd, uintptr(args)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func btrfsSubvolumeCreate(path string) error {
+	var args btrfsVolArgs
+	copy(args.name[:btrfsPathNameMax], filepath.Base(path))
+	if err := btrfsIoctl(filepath.Dir(path), btrfsIocSubvolCreate, unsafe.Pointer(&args)); err != nil {
+		return fmt.Errorf("failed to create btrfs subvolume %s: %v", path, err)
+	}
+	return nil
+}
+
+func btrfsSubvolumeSnapshot(src, dst string, readOnly bool) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var args btrfsVolArgsV2
+	args.fd = int64(f.Fd())
+	if readOnly {
+		args.flags = btrfsSubvolRdonly
+	}
+	copy(args.name[:btrfsSubvolNameMax], filepath.Base(dst))
+	if err := btrfsIoctl(filepath.Dir(dst), btrfsIocSnapCreateV2, unsafe.Pointer(&args)); err != nil {
+		return fmt.Errorf("failed to snapshot btrfs subvolume %s to %s: %v", src, dst, err)
+	}
+	return nil
+}
+
+func btrfsSubvolumeDelete(path string) error {
+	var args btrfsVolArgs
+	copy(args.name[:btrfsPathNameMax], filepath.Base(path))
+	if err := btrfsIoctl(filepath.Dir(path), btrfsIocSnapDestroy, unsafe.Pointer(&args)); err != nil {
+		return fmt.Errorf("failed to delete btrfs subvolume %s: %v", path, err)
+	}
+	return nil
+}
+
+// isBtrfsSubvolume returns whether path is the top directory of a subvolume.
+func isBtrfsSubvolume(path string) (bool, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return false, err
+	}
+	return st.Ino == btrfsFirstFreeObjectID, nil
+}
+
+func btrfsSnapshotsPath(v *localVolume) string {
+	return filepath.Join(filepath.Dir(v.path), snapshotsPathName)
+}
+
+func (btrfsSnapshotter) checkData(v *localVolume) error {
+	ok, err := isBtrfsSubvolume(v.path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errNoSnapshotData(v)
+	}
+	return nil
+}
+
+func (btrfsSnapshotter) createData(v *localVolume, rootUID, rootGID int) error {
+	if err := btrfsSubvolumeCreate(v.path); err != nil {
+		return err
+	}
+	return os.Chown(v.path, rootUID, rootGID)
+}
+
+func (btrfsSnapshotter) removeData(v *localVolume) error {
+	snapshotsPath := btrfsSnapshotsPath(v)
+	dirs, err := ioutil.ReadDir(snapshotsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, d := range dirs {
+		path := filepath.Join(snapshotsPath, d.Name(), VolumeDataPathName)
+		if ok, err := isBtrfsSubvolume(path); err == nil && ok {
+			if err := btrfsSubvolumeDelete(path); err != nil {
+				return err
+			}
+		}
+	}
+	if err := removePath(snapshotsPath); err != nil {
+		return err
+	}
+
+	if ok, err := isBtrfsSubvolume(v.path); err == nil && ok {
+		return btrfsSubvolumeDelete(v.path)
+	}
+	return nil
+}
+
+func (s btrfsSnapshotter) snapshot(v *localVolume, name string) error {
+	if err := s.checkData(v); err != nil {
+		return err
+	}
+
+	snapshotsPath := btrfsSnapshotsPath(v)
+	if err := os.MkdirAll(snapshotsPath, 0700); err != nil {
+		return err
+	}
+	dir := filepath.Join(snapshotsPath, name)
+	if err := os.Mkdir(dir, 0700); err != nil {
+		if os.IsExist(err) {
+			return errSnapshotExists(v, name)
+		}
+		return err
+	}
+
+	if err := btrfsSubvolumeSnapshot(v.path, filepath.Join(dir, VolumeDataPathName), true); err != nil {
+		os.Remove(dir)
+		return err
+	}
+	return nil
+}
+
+func (btrfsSnapshotter) listSnapshots(v *localVolume) ([]volume.Snapshot, error) {
+	dirs, err := ioutil.ReadDir(btrfsSnapshotsPath(v))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ls []volume.Snapshot
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		ls = append(ls, volume.Snapshot{Name: d.Name(), CreatedAt: d.ModTime().UTC()})
+	}
+	return ls, nil
+}
+
+// restoreSnapshot creates a writable snapshot of the snapshot next to the
+// data directory, and swaps them.
+func (s btrfsSnapshotter) restoreSnapshot(v *localVolume, name string) error {
+	if err := s.checkData(v); err != nil {
+		return err
+	}
+
+	src := filepath.Join(btrfsSnapshotsPath(v), name, VolumeDataPathName)
+	if _, err := os.Stat(src); err != nil {
+		if os.IsNotExist(err) {
+			return errNoSuchSnapshot(v, name)
+		}
+		return err
+	}
+
+	restorePath := v.path + ".restore"
+	oldPath := v.path + ".old"
+	for _, path := range []string{restorePath, oldPath} {
+		// left over by an interrupted restore
+		if ok, err := isBtrfsSubvolume(path); err == nil && ok {
+			if err := btrfsSubvolumeDelete(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := btrfsSubvolumeSnapshot(src, restorePath, false); err != nil {
+		return err
+	}
+	if err := os.Rename(v.path, oldPath); err != nil {
+		btrfsSubvolumeDelete(restorePath)
+		return err
+	}
+	if err := os.Rename(restorePath, v.path); err != nil {
+		os.Rename(oldPath, v.path)
+		btrfsSubvolumeDelete(restorePath)
+		return err
+	}
+
+	if err := btrfsSubvolumeDelete(oldPath); err != nil {
+		logrus.Warnf("Unable to remove the data of volume %s replaced by snapshot %s: %v", v.name, name, err)
+	}
+	return nil
+}
+
+func (btrfsSnapshotter) deleteSnapshot(v *localVolume, name string) error {
+	dir := filepath.Join(btrfsSnapshotsPath(v), name)
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return errNoSuchSnapshot(v, name)
+		}
+		return err
+	}
+
+	path := filepath.Join(dir, VolumeDataPathName)
+	if ok, err := isBtrfsSubvolume(path); err == nil && ok {
+		if err := btrfsSubvolumeDelete(path); err != nil {
+			return err
+		}
+	}
+	return removePath(dir)
+}
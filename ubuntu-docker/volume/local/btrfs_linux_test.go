@@ -0,0 +1,17 @@
+package local
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestBtrfsVolArgsSize(t *testing.T) {
+	// both structs are 4096 bytes in linux/btrfs.h, the size is part of the
+	// ioctl numbers.
+	if size := unsafe.Sizeof(btrfsVolArgs{}); size != 4096 {
+		t.Fatalf("expected btrfsVolArgs to be 4096 bytes, got %d", size)
+	}
+	if size := unsafe.Sizeof(btrfsVolArgsV2{}); size != 4096 {
+		t.Fatalf("expected btrfsVolArgsV2 to be 4096 bytes, got %d", size)
+	}
+}
@@ -73,6 +73,12 @@ func New(scope string, rootUID, rootGID int) (*Root, error) {
 		rootGID: rootGID,
 	}
 
+	snapshots, err := newSnapshotter(rootDirectory)
+	if err != nil {
+		logrus.Warnf("Snapshots of local volumes are disabled: %v", err)
+	}
+	r.snapshots = snapshots
+
 	dirs, err := ioutil.ReadDir(rootDirectory)
 	if err != nil {
 		return nil, err
@@ -128,6 +134,9 @@ type Root struct {
 	volumes map[string]*localVolume
 	rootUID int
 	rootGID int
+	// snapshots takes the snapshots of the volumes, nil if the filesystem
+	// of the volumes root does not support them.
+	snapshots snapshotter
 }
 
 // List lists all the volumes
@@ -168,7 +177,13 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 	}
 
 	path := r.DataPath(name)
-	if err := idtools.MkdirAllAs(path, 0755, r.rootUID, r.rootGID); err != nil {
+	v = &localVolume{
+		driverName: r.Name(),
+		name:       name,
+		path:       path,
+	}
+
+	if err := r.createDataPath(v, opts); err != nil {
 		if os.IsExist(err) {
 			return nil, fmt.Errorf("volume already exists under %s", filepath.Dir(path))
 		}
@@ -182,16 +197,13 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 		}
 	}()
 
-	v = &localVolume{
-		driverName: r.Name(),
-		name:       name,
-		path:       path,
-	}
-
 	if len(opts) != 0 {
 		if err = setOpts(v, opts); err != nil {
 			return nil, err
 		}
+		if err = r.setupVolume(v); err != nil {
+			return nil, err
+		}
 		var b []byte
 		b, err = json.Marshal(v.opts)
 		if err != nil {
@@ -231,6 +243,12 @@ func (r *Root) Remove(v volume.Volume) error {
 		return fmt.Errorf("Unable to remove a directory of out the Docker root %s: %s", r.scope, realPath)
 	}
 
+	if r.snapshots != nil {
+		if err := r.snapshots.removeData(lv); err != nil {
+			return err
+		}
+	}
+
 	if err := removePath(realPath); err != nil {
 		return err
 	}
@@ -430,7 +448,3 @@ func validateOpts(opts map[string]string) error {
 	}
 	return nil
 }
-
-func (v *localVolume) Status() map[string]interface{} {
-	return nil
-}
@@ -0,0 +1,70 @@
+// +build linux,cgo
+
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCreateWithSize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping privileged test in short mode")
+	}
+
+	rootDir, err := ioutil.TempDir("", "local-volume-test-size")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vol, err := r.Create("test", map[string]string{"size": "10m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := vol.(*localVolume)
+	if v.opts.Size != 10*1024*1024 {
+		t.Fatalf("expected a size of 10m, got %d", v.opts.Size)
+	}
+
+	dir, err := v.Mount("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Unmount("1234")
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "small"), make([]byte, 1024*1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "large"), make([]byte, 20*1024*1024), 0644); err == nil {
+		t.Fatal("expected writing more than the size of the volume to fail")
+	}
+
+	status := v.Status()
+	if status["Quota"] != v.opts.Quota || status["Size"] != v.opts.Size {
+		t.Fatalf("unexpected status %v", status)
+	}
+	if usage, ok := status["Usage"].(uint64); !ok || usage < 1024*1024 || usage > v.opts.Size {
+		t.Fatalf("unexpected usage in status %v", status)
+	}
+
+	r, err = New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, exists := r.volumes["test"]
+	if !exists {
+		t.Fatal("missing volume on restart")
+	}
+	if !reflect.DeepEqual(v.opts, v2.opts) {
+		t.Fatal("missing volume options on restart")
+	}
+}
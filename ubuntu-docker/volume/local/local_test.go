@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/volume"
 )
 
 func TestRemove(t *testing.T) {
@@ -346,3 +347,89 @@ func TestRealodNoOpts(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateWithInvalidSize(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	rootDir, err := ioutil.TempDir("", "local-volume-test-invalid-size")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []map[string]string{
+		{"size": "notasize"},
+		{"size": "0"},
+		{"size": "10m", "device": "tmpfs", "type": "tmpfs"},
+	} {
+		if _, err := r.Create("test", opts); err == nil {
+			t.Fatalf("expected %v to cause error", opts)
+		}
+	}
+}
+
+func TestSnapshotsNotSupported(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "local-volume-test-snapshots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.snapshots != nil {
+		t.Skip("the filesystem of the volumes root supports snapshots")
+	}
+
+	v, err := r.Create("test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Snapshot(v, "snap"); err != volume.ErrSnapshotsNotSupported {
+		t.Fatalf("expected snapshots not supported error, got %v", err)
+	}
+	if _, err := r.ListSnapshots(v); err != volume.ErrSnapshotsNotSupported {
+		t.Fatalf("expected snapshots not supported error, got %v", err)
+	}
+}
+
+func TestSnapshotName(t *testing.T) {
+	r := &Root{snapshots: fakeSnapshotter{}}
+	v := &localVolume{name: "test"}
+
+	for _, name := range []string{"snap1", "snap.2", "snap_3"} {
+		if err := r.Snapshot(v, name); err != nil {
+			t.Fatalf("expected snapshot %s to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"../snap", "snap/1", ".snap", "-snap"} {
+		if err := r.Snapshot(v, name); err == nil {
+			t.Fatalf("expected snapshot %s to be invalid", name)
+		}
+	}
+
+	v.opts = &optsConfig{}
+	if err := r.Snapshot(v, "snap1"); err == nil {
+		t.Fatal("expected snapshots of a volume with options to fail")
+	}
+}
+
+type fakeSnapshotter struct{}
+
+func (fakeSnapshotter) createData(v *localVolume, rootUID, rootGID int) error { return nil }
+func (fakeSnapshotter) removeData(v *localVolume) error                       { return nil }
+func (fakeSnapshotter) snapshot(v *localVolume, name string) error            { return nil }
+func (fakeSnapshotter) listSnapshots(v *localVolume) ([]volume.Snapshot, error) {
+	return nil, nil
+}
+func (fakeSnapshotter) restoreSnapshot(v *localVolume, name string) error { return nil }
+func (fakeSnapshotter) deleteSnapshot(v *localVolume, name string) error  { return nil }
@@ -9,8 +9,11 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"syscall"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/go-units"
 )
 
 var (
@@ -20,13 +23,27 @@ var (
 		"type":   true, // specify the filesystem type for mount, e.g. nfs
 		"o":      true, // generic mount options
 		"device": true, // device to mount from
+		"size":   true, // maximum size of the volume, e.g. 10G
 	}
 )
 
+const (
+	// quotaProject limits the size of a volume with a project quota of the
+	// filesystem of the volumes root.
+	quotaProject = "project"
+	// quotaLoopback limits the size of a volume with a filesystem image
+	// mounted through a loopback device.
+	quotaLoopback = "loopback"
+)
+
 type optsConfig struct {
 	MountType   string
 	MountOpts   string
 	MountDevice string
+	// Size is the maximum size of the volume in bytes, enforced as set in
+	// Quota.
+	Size  uint64 `json:",omitempty"`
+	Quota string `json:",omitempty"`
 }
 
 // scopedPath verifies that the path where the volume is located
@@ -58,12 +75,105 @@ func setOpts(v *localVolume, opts map[string]string) error {
 		MountOpts:   opts["o"],
 		MountDevice: opts["device"],
 	}
+
+	if size, ok := opts["size"]; ok {
+		if v.opts.MountType != "" || v.opts.MountOpts != "" || v.opts.MountDevice != "" {
+			return validationError{fmt.Errorf("the size option cannot be combined with the type, o and device options")}
+		}
+		sizeBytes, err := units.RAMInBytes(size)
+		if err != nil || sizeBytes <= 0 {
+			return validationError{fmt.Errorf("invalid size %q for volume %s", size, v.name)}
+		}
+		v.opts.Size = uint64(sizeBytes)
+	}
 	return nil
 }
 
+// setupVolume sets up the size quota of a new volume. A project quota is
+// used when the filesystem of the volumes root supports it, a filesystem
+// image mounted through a loopback device otherwise.
+func (r *Root) setupVolume(v *localVolume) error {
+	if v.opts == nil || v.opts.Size == 0 {
+		return nil
+	}
+
+	err := setProjectQuota(r.path, v.path, v.opts.Size)
+	if err == nil {
+		v.opts.Quota = quotaProject
+		return nil
+	}
+	logrus.Debugf("Project quotas unavailable for volume %s, falling back to a loopback device: %v", v.name, err)
+
+	if err := createLoopbackImage(loopbackImagePath(v), v.opts.Size, r.rootUID, r.rootGID); err != nil {
+		return fmt.Errorf("unable to limit the size of volume %s: %v", v.name, err)
+	}
+	v.opts.Quota = quotaLoopback
+	return nil
+}
+
+// loopbackImagePath returns the path of the filesystem image of a volume
+// limited with a loopback device, next to its data directory.
+func loopbackImagePath(v *localVolume) string {
+	return filepath.Join(filepath.Dir(v.path), "disk.img")
+}
+
 func (v *localVolume) mount() error {
+	if v.opts.Size > 0 {
+		if v.opts.Quota == quotaLoopback {
+			return mountLoopbackImage(loopbackImagePath(v), v.path)
+		}
+		// The project quota applies without mounting anything.
+		return nil
+	}
 	if v.opts.MountDevice == "" {
 		return fmt.Errorf("missing device in volume options")
 	}
 	return mount.Mount(v.opts.MountDevice, v.path, v.opts.MountType, v.opts.MountOpts)
 }
+
+// Status returns the size limit of the volume and the space used in bytes,
+// for the volumes created with the size option.
+func (v *localVolume) Status() map[string]interface{} {
+	if v.opts == nil || v.opts.Size == 0 {
+		return nil
+	}
+
+	status := map[string]interface{}{
+		"Quota": v.opts.Quota,
+		"Size":  v.opts.Size,
+	}
+	usage, err := v.usage()
+	if err != nil {
+		logrus.Debugf("Unable to get the usage of volume %s: %v", v.name, err)
+		return status
+	}
+	status["Usage"] = usage
+	return status
+}
+
+// usage returns the space used by a volume with a size quota. The space
+// allocated on the host for the filesystem image is returned for the
+// volumes limited with a loopback device which are not mounted.
+func (v *localVolume) usage() (uint64, error) {
+	if v.opts.Quota == quotaProject {
+		return getProjectQuotaUsage(filepath.Dir(filepath.Dir(v.path)), v.path)
+	}
+
+	v.m.Lock()
+	mounted := v.active.mounted
+	v.m.Unlock()
+
+	if mounted {
+		var buf syscall.Statfs_t
+		if err := syscall.Statfs(v.path, &buf); err != nil {
+			return 0, err
+		}
+		return (buf.Blocks - buf.Bfree) * uint64(buf.Bsize), nil
+	}
+
+	var st syscall.Stat_t
+	if err := syscall.Stat(loopbackImagePath(v), &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Blocks) * 512, nil
+}
@@ -29,6 +29,14 @@ func setOpts(v *localVolume, opts map[string]string) error {
 	return nil
 }
 
+func (r *Root) setupVolume(v *localVolume) error {
+	return nil
+}
+
 func (v *localVolume) mount() error {
 	return nil
 }
+
+func (v *localVolume) Status() map[string]interface{} {
+	return nil
+}
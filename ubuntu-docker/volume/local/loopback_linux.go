@@ -0,0 +1,50 @@
+// +build linux,cgo
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/docker/docker/pkg/loopback"
+	"github.com/docker/docker/pkg/mount"
+)
+
+// createLoopbackImage creates a sparse ext4 filesystem image of size bytes
+// at path, with its root directory owned by rootUID and rootGID.
+func createLoopbackImage(path string, size uint64, rootUID, rootGID int) error {
+	if _, err := exec.LookPath("mkfs.ext4"); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	err = f.Truncate(int64(size))
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	args := []string{"-q", "-F", "-E", fmt.Sprintf("nodiscard,root_owner=%d:%d", rootUID, rootGID), path}
+	if out, err := exec.Command("mkfs.ext4", args...).CombinedOutput(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("mkfs.ext4 failed: %v (%s)", err, out)
+	}
+	return nil
+}
+
+// mountLoopbackImage mounts the filesystem image at path on target through
+// a loopback device, detached automatically when target is unmounted.
+func mountLoopbackImage(path, target string) error {
+	loopFile, err := loopback.AttachLoopDevice(path)
+	if err != nil {
+		return err
+	}
+	defer loopFile.Close()
+
+	return mount.Mount(loopFile.Name(), target, "ext4", "")
+}
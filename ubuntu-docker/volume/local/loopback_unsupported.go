@@ -0,0 +1,14 @@
+// +build !linux !cgo
+// +build !windows
+
+package local
+
+import "fmt"
+
+func createLoopbackImage(path string, size uint64, rootUID, rootGID int) error {
+	return fmt.Errorf("loopback devices are not supported by this binary")
+}
+
+func mountLoopbackImage(path, target string) error {
+	return fmt.Errorf("loopback devices are not supported by this binary")
+}
@@ -0,0 +1,209 @@
+package local
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// The project quotas are set through the generic quota interface of the
+// kernel, supported by xfs and by ext4 with the project and quota features.
+const (
+	qGetQuota = 0x800007
+	qSetQuota = 0x800008
+	prjQuota  = 2
+
+	qifBLimits   = 1
+	qifDqBlkSize = 1024
+
+	fsIOCFSGetXAttr    = 0x801c581f
+	fsIOCFSSetXAttr    = 0x401c5820
+	fsXFlagProjInherit = 0x200
+)
+
+// backingFsBlockDevName is the name of the block device node created under
+// the volumes root for quotactl.
+const backingFsBlockDevName = "backingFsBlockDev"
+
+// ifDqblk is struct if_dqblk of linux/quota.h.
+type ifDqblk struct {
+	bHardLimit uint64
+	bSoftLimit uint64
+	curSpace   uint64
+	iHardLimit uint64
+	iSoftLimit uint64
+	curInodes  uint64
+	bTime      uint64
+	iTime      uint64
+	valid      uint32
+}
+
+// fsXAttr is struct fsxattr of linux/fs.h.
+type fsXAttr struct {
+	xFlags     uint32
+	extSize    uint32
+	nExtents   uint32
+	projID     uint32
+	cowExtSize uint32
+	pad        [8]byte
+}
+
+// setProjectQuota limits the size of the directory path under root to size
+// bytes, with a project quota for a project ID not used by the other
+// directories of root. It fails if the filesystem of root does not support
+// project quotas.
+func setProjectQuota(root, path string, size uint64) error {
+	dev, err := makeBackingFsBlockDev(root)
+	if err != nil {
+		return err
+	}
+
+	baseID, err := getProjectID(root)
+	if err != nil {
+		return err
+	}
+	// Check that project quotas are enabled before changing anything.
+	if _, err := getQuota(dev, baseID); err != nil {
+		return err
+	}
+
+	projectID, err := nextProjectID(root, baseID)
+	if err != nil {
+		return err
+	}
+	if err := setProjectID(path, projectID); err != nil {
+		return err
+	}
+
+	d := ifDqblk{
+		bHardLimit: (size + qifDqBlkSize - 1) / qifDqBlkSize,
+		valid:      qifBLimits,
+	}
+	d.bSoftLimit = d.bHardLimit
+	if err := quotactl(qSetQuota, dev, projectID, &d); err != nil {
+		return fmt.Errorf("failed to set the quota of project %d on %s: %v", projectID, path, err)
+	}
+	return nil
+}
+
+// getProjectQuotaUsage returns the space used by the directory path under
+// root, limited with setProjectQuota.
+func getProjectQuotaUsage(root, path string) (uint64, error) {
+	projectID, err := getProjectID(path)
+	if err != nil {
+		return 0, err
+	}
+	d, err := getQuota(filepath.Join(root, backingFsBlockDevName), projectID)
+	if err != nil {
+		return 0, err
+	}
+	return d.curSpace, nil
+}
+
+// nextProjectID returns a project ID above baseID and the project IDs of
+// the data directories of the volumes under root.
+func nextProjectID(root string, baseID uint32) (uint32, error) {
+	dirs, err := ioutil.ReadDir(root)
+	if err != nil {
+		return 0, err
+	}
+	maxID := baseID
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		id, err := getProjectID(filepath.Join(root, d.Name(), VolumeDataPathName))
+		if err != nil {
+			continue
+		}
+		if id > maxID {
+			maxID = id
+		}
+	}
+	return maxID + 1, nil
+}
+
+func getQuota(dev string, projectID uint32) (*ifDqblk, error) {
+	var d ifDqblk
+	if err := quotactl(qGetQuota, dev, projectID, &d); err != nil {
+		return nil, fmt.Errorf("failed to get the quota of project %d: %v", projectID, err)
+	}
+	return &d, nil
+}
+
+func quotactl(cmd int, dev string, id uint32, d *ifDqblk) error {
+	devPtr, err := syscall.BytePtrFromString(dev)
+	if err != nil {
+		return err
+	}
+	qcmd := cmd<<8 | prjQuota
+	_, _, errno := syscall.Syscall6(syscall.SYS_QUOTACTL, uintptr(qcmd), uintptr(unsafe.Pointer(devPtr)), uintptr(id), uintptr(unsafe.Pointer(d)), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func getProjectID(path string) (uint32, error) {
+	attr, err := getFsXAttr(path)
+	if err != nil {
+		return 0, err
+	}
+	return attr.projID, nil
+}
+
+// setProjectID sets the project ID of the directory path, inherited by the
+// files created under it.
+func setProjectID(path string, projectID uint32) error {
+	attr, err := getFsXAttr(path)
+	if err != nil {
+		return err
+	}
+	attr.projID = projectID
+	attr.xFlags |= fsXFlagProjInherit
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dir.Fd(), fsIOCFSSetXAttr, uintptr(unsafe.Pointer(attr))); errno != 0 {
+		return fmt.Errorf("failed to set the project ID of %s: %v", path, errno)
+	}
+	return nil
+}
+
+func getFsXAttr(path string) (*fsXAttr, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	var attr fsXAttr
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dir.Fd(), fsIOCFSGetXAttr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return nil, fmt.Errorf("failed to get the project ID of %s: %v", path, errno)
+	}
+	return &attr, nil
+}
+
+// makeBackingFsBlockDev creates a block device node under root for the
+// device of the filesystem of root, which quotactl needs to be given.
+func makeBackingFsBlockDev(root string) (string, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(root, &st); err != nil {
+		return "", err
+	}
+
+	dev := filepath.Join(root, backingFsBlockDevName)
+	if err := syscall.Unlink(dev); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := syscall.Mknod(dev, syscall.S_IFBLK|0600, int(st.Dev)); err != nil {
+		return "", fmt.Errorf("failed to create the block device node of %s: %v", root, err)
+	}
+	return dev, nil
+}
@@ -0,0 +1,13 @@
+// +build !linux,!windows
+
+package local
+
+import "fmt"
+
+func setProjectQuota(root, path string, size uint64) error {
+	return fmt.Errorf("project quotas are not supported on this platform")
+}
+
+func getProjectQuotaUsage(root, path string) (uint64, error) {
+	return 0, fmt.Errorf("project quotas are not supported on this platform")
+}
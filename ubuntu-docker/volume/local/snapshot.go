@@ -0,0 +1,108 @@
+package local
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/utils"
+	"github.com/docker/docker/volume"
+)
+
+// snapshotter takes the snapshots of the volumes of a root whose filesystem
+// supports them. The data directory of a volume created without options is
+// created by the snapshotter, as a btrfs subvolume or a zfs dataset.
+type snapshotter interface {
+	// createData creates the data directory of a new volume.
+	createData(v *localVolume, rootUID, rootGID int) error
+	// removeData removes the data directory of a volume created by the
+	// snapshotter, along with its snapshots.
+	removeData(v *localVolume) error
+	snapshot(v *localVolume, name string) error
+	listSnapshots(v *localVolume) ([]volume.Snapshot, error)
+	// restoreSnapshot replaces the data of the volume with the snapshot.
+	restoreSnapshot(v *localVolume, name string) error
+	deleteSnapshot(v *localVolume, name string) error
+}
+
+func errNoSuchSnapshot(v *localVolume, name string) error {
+	return fmt.Errorf("no such snapshot %s of volume %s", name, v.name)
+}
+
+func errSnapshotExists(v *localVolume, name string) error {
+	return fmt.Errorf("conflict: snapshot %s of volume %s already exists", name, v.name)
+}
+
+// errNoSnapshotData is returned for the volumes whose data was not created
+// by the snapshotter, like the volumes created before it was available.
+func errNoSnapshotData(v *localVolume) error {
+	return fmt.Errorf("volume %s does not support snapshots, its data is not on a btrfs subvolume or zfs dataset", v.name)
+}
+
+// createDataPath creates the data directory of a new volume, through the
+// snapshotter when the volume has no options.
+func (r *Root) createDataPath(v *localVolume, opts map[string]string) error {
+	if r.snapshots == nil || len(opts) != 0 {
+		return idtools.MkdirAllAs(v.path, 0755, r.rootUID, r.rootGID)
+	}
+	if err := idtools.MkdirAllAs(filepath.Dir(v.path), 0755, r.rootUID, r.rootGID); err != nil {
+		return err
+	}
+	return r.snapshots.createData(v, r.rootUID, r.rootGID)
+}
+
+// snapshotVolume returns the local volume of v if its snapshots can be
+// taken, and validates the snapshot name unless it is empty.
+func (r *Root) snapshotVolume(v volume.Volume, name string) (*localVolume, error) {
+	if r.snapshots == nil {
+		return nil, volume.ErrSnapshotsNotSupported
+	}
+	lv, ok := v.(*localVolume)
+	if !ok {
+		return nil, fmt.Errorf("unknown volume type %T", v)
+	}
+	if lv.opts != nil {
+		return nil, validationError{fmt.Errorf("snapshots are not supported for volume %s created with options", lv.name)}
+	}
+	if name != "" && !volumeNameRegex.MatchString(name) {
+		return nil, validationError{fmt.Errorf("%q includes invalid characters for a snapshot name, only %q are allowed", name, utils.RestrictedNameChars)}
+	}
+	return lv, nil
+}
+
+// Snapshot takes a read-only snapshot of the data of the volume.
+func (r *Root) Snapshot(v volume.Volume, name string) error {
+	lv, err := r.snapshotVolume(v, name)
+	if err != nil {
+		return err
+	}
+	return r.snapshots.snapshot(lv, name)
+}
+
+// ListSnapshots returns the snapshots of the volume.
+func (r *Root) ListSnapshots(v volume.Volume) ([]volume.Snapshot, error) {
+	lv, err := r.snapshotVolume(v, "")
+	if err != nil {
+		return nil, err
+	}
+	return r.snapshots.listSnapshots(lv)
+}
+
+// RestoreSnapshot reverts the data of the volume to the snapshot. On zfs,
+// the snapshots taken after it are removed.
+func (r *Root) RestoreSnapshot(v volume.Volume, name string) error {
+	lv, err := r.snapshotVolume(v, name)
+	if err != nil {
+		return err
+	}
+	return r.snapshots.restoreSnapshot(lv, name)
+}
+
+// DeleteSnapshot removes the snapshot of the volume.
+func (r *Root) DeleteSnapshot(v volume.Volume, name string) error {
+	lv, err := r.snapshotVolume(v, name)
+	if err != nil {
+		return err
+	}
+	return r.snapshots.deleteSnapshot(lv, name)
+}
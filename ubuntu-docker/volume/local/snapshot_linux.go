@@ -0,0 +1,24 @@
+package local
+
+import "syscall"
+
+// newSnapshotter returns the snapshotter for the filesystem of the volumes
+// root, nil if it does not support snapshots.
+func newSnapshotter(root string) (snapshotter, error) {
+	var buf syscall.Statfs_t
+	if err := syscall.Statfs(root, &buf); err != nil {
+		return nil, err
+	}
+
+	switch uint32(buf.Type) {
+	case btrfsSuperMagic:
+		return btrfsSnapshotter{}, nil
+	case zfsSuperMagic:
+		z, err := newZfsSnapshotter(root)
+		if err != nil {
+			return nil, err
+		}
+		return z, nil
+	}
+	return nil, nil
+}
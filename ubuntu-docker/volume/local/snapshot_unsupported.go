@@ -0,0 +1,7 @@
+// +build !linux
+
+package local
+
+func newSnapshotter(root string) (snapshotter, error) {
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/volume"
+	zfs "github.com/mistifyio/go-zfs"
+)
+
+const zfsSuperMagic = 0x2fc12fc1
+
+// zfsSnapshotter creates the data directory of a volume as the mountpoint of
+// a zfs filesystem named volume-<name>, child of the dataset of the volumes
+// root, and takes its snapshots as zfs snapshots.
+type zfsSnapshotter struct {
+	// dataset is the name of the dataset mounted on the volumes root.
+	dataset string
+}
+
+func newZfsSnapshotter(root string) (*zfsSnapshotter, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(root, &st); err != nil {
+		return nil, err
+	}
+	dev := st.Dev
+
+	mounts, err := mount.GetMounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mounts {
+		if m.Fstype != "zfs" {
+			continue
+		}
+		if err := syscall.Stat(m.Mountpoint, &st); err == nil && st.Dev == dev {
+			return &zfsSnapshotter{dataset: m.Source}, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find the zfs dataset mounted on %s", root)
+}
+
+func (z *zfsSnapshotter) datasetName(v *localVolume) string {
+	return z.dataset + "/volume-" + v.name
+}
+
+func isZfsErr(err error, msg string) bool {
+	zerr, ok := err.(*zfs.Error)
+	return ok && strings.Contains(zerr.Stderr, msg)
+}
+
+// getDataset returns the dataset of the volume, nil if it has none.
+func (z *zfsSnapshotter) getDataset(v *localVolume) (*zfs.Dataset, error) {
+	ds, err := zfs.GetDataset(z.datasetName(v))
+	if err != nil {
+		if isZfsErr(err, "does not exist") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ds, nil
+}
+
+// getSnapshot returns the zfs snapshot name of the volume.
+func (z *zfsSnapshotter) getSnapshot(v *localVolume, name string) (*zfs.Dataset, error) {
+	ds, err := zfs.GetDataset(z.datasetName(v) + "@" + name)
+	if err != nil {
+		if isZfsErr(err, "does not exist") {
+			return nil, errNoSuchSnapshot(v, name)
+		}
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (z *zfsSnapshotter) createData(v *localVolume, rootUID, rootGID int) error {
+	if err := os.Mkdir(v.path, 0755); err != nil {
+		return err
+	}
+	if _, err := zfs.CreateFilesystem(z.datasetName(v), map[string]string{"mountpoint": v.path}); err != nil {
+		return err
+	}
+	return os.Chown(v.path, rootUID, rootGID)
+}
+
+func (z *zfsSnapshotter) removeData(v *localVolume) error {
+	ds, err := z.getDataset(v)
+	if err != nil || ds == nil {
+		return err
+	}
+	return ds.Destroy(zfs.DestroyRecursive)
+}
+
+func (z *zfsSnapshotter) snapshot(v *localVolume, name string) error {
+	ds, err := z.getDataset(v)
+	if err != nil {
+		return err
+	}
+	if ds == nil {
+		return errNoSnapshotData(v)
+	}
+
+	if _, err := ds.Snapshot(name, false); err != nil {
+		if isZfsErr(err, "already exists") {
+			return errSnapshotExists(v, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// listSnapshots runs zfs list directly, as go-zfs does not return the
+// creation time of the snapshots.
+func (z *zfsSnapshotter) listSnapshots(v *localVolume) ([]volume.Snapshot, error) {
+	ds, err := z.getDataset(v)
+	if err != nil {
+		return nil, err
+	}
+	if ds == nil {
+		return nil, errNoSnapshotData(v)
+	}
+
+	out, err := exec.Command("zfs", "list", "-Hp", "-t", "snapshot", "-d", "1", "-o", "name,creation", ds.Name).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the snapshots of %s: %v", ds.Name, err)
+	}
+
+	var ls []volume.Snapshot
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		parts := strings.SplitN(fields[0], "@", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		s := volume.Snapshot{Name: parts[1]}
+		if sec, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			s.CreatedAt = time.Unix(sec, 0).UTC()
+		}
+		ls = append(ls, s)
+	}
+	return ls, nil
+}
+
+// restoreSnapshot rolls the dataset of the volume back to the snapshot,
+// which destroys the snapshots taken after it.
+func (z *zfsSnapshotter) restoreSnapshot(v *localVolume, name string) error {
+	snap, err := z.getSnapshot(v, name)
+	if err != nil {
+		return err
+	}
+	return snap.Rollback(true)
+}
+
+func (z *zfsSnapshotter) deleteSnapshot(v *localVolume, name string) error {
+	snap, err := z.getSnapshot(v, name)
+	if err != nil {
+		return err
+	}
+	return snap.Destroy(zfs.DestroyDefault)
+}
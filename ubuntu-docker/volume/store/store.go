@@ -21,14 +21,25 @@ const (
 )
 
 type volumeMetadata struct {
-	Name   string
-	Labels map[string]string
+	Name      string
+	Labels    map[string]string
+	CreatedAt time.Time
+	LastUsed  time.Time
+}
+
+// volumeActivity is the creation time of a volume, the time it was last
+// mounted or unmounted, and the number of active mounts of the volume.
+type volumeActivity struct {
+	createdAt time.Time
+	lastUsed  time.Time
+	mounts    int
 }
 
 type volumeWrapper struct {
 	volume.Volume
 	labels map[string]string
 	scope  string
+	store  *VolumeStore
 }
 
 func (v volumeWrapper) Labels() map[string]string {
@@ -39,6 +50,37 @@ func (v volumeWrapper) Scope() string {
 	return v.scope
 }
 
+// Mount mounts the volume and records its use in the store.
+func (v volumeWrapper) Mount(id string) (string, error) {
+	path, err := v.Volume.Mount(id)
+	if err != nil {
+		return "", err
+	}
+	v.store.setMounted(v.Name(), 1)
+	return path, nil
+}
+
+// Unmount unmounts the volume and records its use in the store.
+func (v volumeWrapper) Unmount(id string) error {
+	if err := v.Volume.Unmount(id); err != nil {
+		return err
+	}
+	v.store.setMounted(v.Name(), -1)
+	return nil
+}
+
+// CreatedAt returns the time the volume was created, zero if unknown.
+func (v volumeWrapper) CreatedAt() time.Time {
+	a := v.store.getActivity(v.Name())
+	return a.createdAt
+}
+
+// LastUsed returns the time the volume was last mounted or unmounted.
+func (v volumeWrapper) LastUsed() time.Time {
+	a := v.store.getActivity(v.Name())
+	return a.lastUsed
+}
+
 func (v volumeWrapper) CachedPath() string {
 	if vv, ok := v.Volume.(interface {
 		CachedPath() string
@@ -52,10 +94,11 @@ func (v volumeWrapper) CachedPath() string {
 // reference counting of volumes in the system.
 func New(rootPath string) (*VolumeStore, error) {
 	vs := &VolumeStore{
-		locks:  &locker.Locker{},
-		names:  make(map[string]volume.Volume),
-		refs:   make(map[string][]string),
-		labels: make(map[string]map[string]string),
+		locks:    &locker.Locker{},
+		names:    make(map[string]volume.Volume),
+		refs:     make(map[string][]string),
+		labels:   make(map[string]map[string]string),
+		activity: make(map[string]*volumeActivity),
 	}
 
 	if rootPath != "" {
@@ -83,11 +126,135 @@ func New(rootPath string) (*VolumeStore, error) {
 		}); err != nil {
 			return nil, err
 		}
+
+		if err := vs.restore(); err != nil {
+			return nil, err
+		}
 	}
 
 	return vs, nil
 }
 
+// restore loads the labels and the activity of the volumes from the
+// metadata store. The volumes recorded by older versions have no creation
+// time, they are considered last used now.
+func (s *VolumeStore) restore() error {
+	now := time.Now().UTC()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(volumeBucketName))
+		return b.ForEach(func(k, data []byte) error {
+			var meta volumeMetadata
+			if err := json.Unmarshal(data, &meta); err != nil {
+				logrus.Warnf("Error reading metadata of volume %s: %v", k, err)
+				return nil
+			}
+			name := string(k)
+			if meta.LastUsed.IsZero() {
+				meta.LastUsed = now
+				data, err := json.Marshal(&meta)
+				if err != nil {
+					return err
+				}
+				if err := b.Put(k, data); err != nil {
+					return err
+				}
+			}
+			s.labels[name] = meta.Labels
+			s.activity[name] = &volumeActivity{createdAt: meta.CreatedAt, lastUsed: meta.LastUsed}
+			return nil
+		})
+	})
+}
+
+// wrap returns the volume v of the driver with the given scope, wrapped
+// with its labels.
+func (s *VolumeStore) wrap(v volume.Volume, scope string) volume.Volume {
+	if _, ok := v.(volumeWrapper); ok {
+		return v
+	}
+	s.globalLock.RLock()
+	labels := s.labels[v.Name()]
+	s.globalLock.RUnlock()
+	return volumeWrapper{v, labels, scope, s}
+}
+
+// getActivity returns the activity of the volume name. A volume unknown to
+// the store, like a volume created directly through a plugin, is recorded
+// as last used now. It is only recorded in memory, the metadata store is
+// updated when the volume is next mounted.
+func (s *VolumeStore) getActivity(name string) volumeActivity {
+	s.globalLock.Lock()
+	defer s.globalLock.Unlock()
+	a, exists := s.activity[name]
+	if !exists {
+		a = &volumeActivity{lastUsed: time.Now().UTC()}
+		s.activity[name] = a
+	}
+	return *a
+}
+
+// RestoreMount records an active mount of the volume v made before the
+// daemon restarted. The active mounts are only counted in memory, the
+// daemon counts those of the containers kept running by live-restore again.
+func (s *VolumeStore) RestoreMount(v volume.Volume) {
+	s.globalLock.Lock()
+	defer s.globalLock.Unlock()
+	a, exists := s.activity[v.Name()]
+	if !exists {
+		a = &volumeActivity{lastUsed: time.Now().UTC()}
+		s.activity[v.Name()] = a
+	}
+	a.mounts++
+}
+
+// setMounted adds delta to the active mounts of the volume name, and records
+// it as last used now.
+func (s *VolumeStore) setMounted(name string, delta int) {
+	now := time.Now().UTC()
+	s.globalLock.Lock()
+	a, exists := s.activity[name]
+	if !exists {
+		a = &volumeActivity{}
+		s.activity[name] = a
+	}
+	a.mounts += delta
+	if a.mounts < 0 {
+		a.mounts = 0
+	}
+	a.lastUsed = now
+	s.globalLock.Unlock()
+
+	s.updateMetadata(name, func(meta *volumeMetadata) {
+		meta.LastUsed = now
+	})
+}
+
+// updateMetadata applies f to the metadata of the volume name, and saves it
+// in the metadata store.
+func (s *VolumeStore) updateMetadata(name string, f func(*volumeMetadata)) {
+	if s.db == nil {
+		return
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(volumeBucketName))
+		meta := volumeMetadata{Name: name}
+		if data := b.Get([]byte(name)); len(data) > 0 {
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return err
+			}
+		}
+		f(&meta)
+		data, err := json.Marshal(&meta)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(name), data)
+	})
+	if err != nil {
+		logrus.Errorf("Error updating metadata of volume %s: %v", name, err)
+	}
+}
+
 func (s *VolumeStore) getNamed(name string) (volume.Volume, bool) {
 	s.globalLock.RLock()
 	v, exists := s.names[name]
@@ -118,6 +285,7 @@ func (s *VolumeStore) purge(name string) {
 	delete(s.names, name)
 	delete(s.refs, name)
 	delete(s.labels, name)
+	delete(s.activity, name)
 	err := s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(volumeBucketName))
 		return b.Delete([]byte(name))
@@ -142,7 +310,9 @@ type VolumeStore struct {
 	refs map[string][]string
 	// labels stores volume labels for each volume
 	labels map[string]map[string]string
-	db     *bolt.DB
+	// activity stores the creation and usage times of each volume
+	activity map[string]*volumeActivity
+	db       *bolt.DB
 }
 
 // List proxies to all registered volume drivers to get the full list of volumes
@@ -202,7 +372,7 @@ func (s *VolumeStore) list() ([]volume.Volume, []string, error) {
 			}
 			for i, v := range vs {
 				s.globalLock.RLock()
-				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope()}
+				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope(), s}
 				s.globalLock.RUnlock()
 			}
 
@@ -283,7 +453,11 @@ func (s *VolumeStore) create(name, driverName string, opts, labels map[string]st
 		if v.DriverName() != driverName && driverName != "" && driverName != volume.DefaultDriverName {
 			return nil, errNameConflict
 		}
-		return v, nil
+		vd, err := volumedrivers.GetDriver(v.DriverName())
+		if err != nil {
+			return nil, err
+		}
+		return s.wrap(v, vd.Scope()), nil
 	}
 
 	// Since there isn't a specified driver name, let's see if any of the existing drivers have this volume name
@@ -303,20 +477,24 @@ func (s *VolumeStore) create(name, driverName string, opts, labels map[string]st
 	logrus.Debugf("Registering new volume reference: driver %q, name %q", vd.Name(), name)
 
 	if v, _ := vd.Get(name); v != nil {
-		return v, nil
+		return s.wrap(v, vd.Scope()), nil
 	}
 	v, err := vd.Create(name, opts)
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now().UTC()
 	s.globalLock.Lock()
 	s.labels[name] = labels
+	s.activity[name] = &volumeActivity{createdAt: now, lastUsed: now}
 	s.globalLock.Unlock()
 
 	if s.db != nil {
 		metadata := &volumeMetadata{
-			Name:   name,
-			Labels: labels,
+			Name:      name,
+			Labels:    labels,
+			CreatedAt: now,
+			LastUsed:  now,
 		}
 
 		volData, err := json.Marshal(metadata)
@@ -333,7 +511,7 @@ func (s *VolumeStore) create(name, driverName string, opts, labels map[string]st
 		}
 	}
 
-	return volumeWrapper{v, labels, vd.Scope()}, nil
+	return volumeWrapper{v, labels, vd.Scope(), s}, nil
 }
 
 // GetWithRef gets a volume with the given name from the passed in driver and stores the ref
@@ -354,11 +532,9 @@ func (s *VolumeStore) GetWithRef(name, driverName, ref string) (volume.Volume, e
 		return nil, &OpErr{Err: err, Name: name, Op: "get"}
 	}
 
+	v = s.wrap(v, vd.Scope())
 	s.setNamed(v, ref)
-
-	s.globalLock.RLock()
-	defer s.globalLock.RUnlock()
-	return volumeWrapper{v, s.labels[name], vd.Scope()}, nil
+	return v, nil
 }
 
 // Get looks if a volume with the given name exists and returns it if so
@@ -418,7 +594,7 @@ func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
 		if err != nil {
 			return nil, err
 		}
-		return volumeWrapper{vol, labels, vd.Scope()}, nil
+		return volumeWrapper{vol, labels, vd.Scope(), s}, nil
 	}
 
 	logrus.Debugf("Probing all drivers for volume with name: %s", name)
@@ -433,7 +609,7 @@ func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
 			continue
 		}
 
-		return volumeWrapper{v, labels, d.Scope()}, nil
+		return volumeWrapper{v, labels, d.Scope(), s}, nil
 	}
 	return nil, errNoSuchVolume
 }
@@ -464,6 +640,89 @@ func (s *VolumeStore) Remove(v volume.Volume) error {
 	return nil
 }
 
+// snapshotDriver returns the driver of the volume if it supports snapshots.
+func (s *VolumeStore) snapshotDriver(v volume.Volume, op string) (volume.SnapshotDriver, error) {
+	vd, err := volumedrivers.GetDriver(v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: v.Name(), Op: op}
+	}
+	sd, ok := vd.(volume.SnapshotDriver)
+	if !ok {
+		return nil, &OpErr{Err: volume.ErrSnapshotsNotSupported, Name: v.Name(), Op: op}
+	}
+	return sd, nil
+}
+
+// Snapshot takes a snapshot of the volume with the given name.
+func (s *VolumeStore) Snapshot(v volume.Volume, snapshot string) error {
+	name := normaliseVolumeName(v.Name())
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	sd, err := s.snapshotDriver(v, "snapshot")
+	if err != nil {
+		return err
+	}
+	if err := sd.Snapshot(unwrapVolume(v), snapshot); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "snapshot"}
+	}
+	return nil
+}
+
+// ListSnapshots returns the snapshots of the volume.
+func (s *VolumeStore) ListSnapshots(v volume.Volume) ([]volume.Snapshot, error) {
+	name := normaliseVolumeName(v.Name())
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	sd, err := s.snapshotDriver(v, "list snapshots")
+	if err != nil {
+		return nil, err
+	}
+	ls, err := sd.ListSnapshots(unwrapVolume(v))
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "list snapshots"}
+	}
+	return ls, nil
+}
+
+// RestoreSnapshot reverts the data of the volume to the given snapshot. A
+// volume is not restored while it is mounted.
+func (s *VolumeStore) RestoreSnapshot(v volume.Volume, snapshot string) error {
+	name := normaliseVolumeName(v.Name())
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	if s.getActivity(name).mounts > 0 {
+		return &OpErr{Err: errVolumeInUse, Name: name, Op: "restore", Refs: s.getRefs(name)}
+	}
+
+	sd, err := s.snapshotDriver(v, "restore")
+	if err != nil {
+		return err
+	}
+	if err := sd.RestoreSnapshot(unwrapVolume(v), snapshot); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "restore"}
+	}
+	return nil
+}
+
+// DeleteSnapshot removes the given snapshot of the volume.
+func (s *VolumeStore) DeleteSnapshot(v volume.Volume, snapshot string) error {
+	name := normaliseVolumeName(v.Name())
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	sd, err := s.snapshotDriver(v, "remove snapshot")
+	if err != nil {
+		return err
+	}
+	if err := sd.DeleteSnapshot(unwrapVolume(v), snapshot); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "remove snapshot"}
+	}
+	return nil
+}
+
 // Dereference removes the specified reference to the volume
 func (s *VolumeStore) Dereference(v volume.Volume, ref string) {
 	s.locks.Lock(v.Name())
@@ -504,7 +763,7 @@ func (s *VolumeStore) FilterByDriver(name string) ([]volume.Volume, error) {
 	}
 	s.globalLock.RLock()
 	for i, v := range ls {
-		ls[i] = volumeWrapper{v, s.labels[v.Name()], vd.Scope()}
+		ls[i] = volumeWrapper{v, s.labels[v.Name()], vd.Scope(), s}
 	}
 	s.globalLock.RUnlock()
 	return ls, nil
@@ -525,6 +784,23 @@ func (s *VolumeStore) FilterByUsed(vols []volume.Volume, used bool) []volume.Vol
 	})
 }
 
+// FilterByCreatedSince returns the volumes created after t. The volumes
+// without creation time are not returned.
+func (s *VolumeStore) FilterByCreatedSince(vols []volume.Volume, t time.Time) []volume.Volume {
+	return s.filter(vols, func(v volume.Volume) bool {
+		return s.getActivity(v.Name()).createdAt.After(t)
+	})
+}
+
+// FilterByUnusedSince returns the volumes which are not mounted, and were
+// last mounted or unmounted before t.
+func (s *VolumeStore) FilterByUnusedSince(vols []volume.Volume, t time.Time) []volume.Volume {
+	return s.filter(vols, func(v volume.Volume) bool {
+		a := s.getActivity(v.Name())
+		return a.mounts == 0 && a.lastUsed.Before(t)
+	})
+}
+
 // filterFunc defines a function to allow filter volumes in the store
 type filterFunc func(vol volume.Volume) bool
 
@@ -2,11 +2,15 @@ package store
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/boltdb/bolt"
+	"github.com/docker/docker/volume"
 	"github.com/docker/docker/volume/drivers"
 	vt "github.com/docker/docker/volume/testutils"
 )
@@ -213,3 +217,250 @@ func TestDerefMultipleOfSameRef(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestActivity(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	defer volumedrivers.Unregister("fake")
+	dir, err := ioutil.TempDir("", "test-activity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	v1, err := s.Create("activity1", "fake", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := s.Create("activity2", "fake", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tv := v1.(volume.TimestampedVolume)
+	if tv.CreatedAt().Before(before) || !tv.LastUsed().Equal(tv.CreatedAt()) {
+		t.Fatalf("unexpected times for a new volume: created at %v, last used %v", tv.CreatedAt(), tv.LastUsed())
+	}
+
+	vols := []volume.Volume{v1, v2}
+	if created := s.FilterByCreatedSince(vols, before); len(created) != 2 {
+		t.Fatalf("expected 2 volumes created since %v, got %v", before, created)
+	}
+	if created := s.FilterByCreatedSince(vols, time.Now()); len(created) != 0 {
+		t.Fatalf("expected no volumes created since now, got %v", created)
+	}
+
+	if _, err := v1.Mount("ref"); err != nil {
+		t.Fatal(err)
+	}
+	if tv.LastUsed().Equal(tv.CreatedAt()) {
+		t.Fatal("expected the last use of the volume to be updated on mount")
+	}
+	unused := s.FilterByUnusedSince(vols, time.Now().Add(time.Second))
+	if len(unused) != 1 || unused[0].Name() != "activity2" {
+		t.Fatalf("expected activity2 to be the only unused volume, got %v", unused)
+	}
+	if unused := s.FilterByUnusedSince(vols, before); len(unused) != 0 {
+		t.Fatalf("expected no volumes unused since %v, got %v", before, unused)
+	}
+
+	if err := v1.Unmount("ref"); err != nil {
+		t.Fatal(err)
+	}
+	lastUsed := tv.LastUsed()
+	if unused := s.FilterByUnusedSince(vols, time.Now().Add(time.Second)); len(unused) != 2 {
+		t.Fatalf("expected 2 unused volumes, got %v", unused)
+	}
+
+	s.db.Close()
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := s.Get("activity1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tv2 := v.(volume.TimestampedVolume)
+	if !tv2.CreatedAt().Equal(tv.CreatedAt()) || !tv2.LastUsed().Equal(lastUsed) {
+		t.Fatalf("expected the times of the volume to be restored, got created at %v, last used %v", tv2.CreatedAt(), tv2.LastUsed())
+	}
+}
+
+func TestRestoreMount(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	defer volumedrivers.Unregister("fake")
+	dir, err := ioutil.TempDir("", "test-restore-mount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := s.Create("mounted", "fake", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Mount("ref"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The active mounts are lost when the daemon restarts, and counted
+	// again for the running containers.
+	s.db.Close()
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = s.Get("mounted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vols := []volume.Volume{v}
+	if unused := s.FilterByUnusedSince(vols, time.Now().Add(time.Second)); len(unused) != 1 {
+		t.Fatalf("expected the volume to be unused before its mounts are restored, got %v", unused)
+	}
+	s.RestoreMount(v)
+	if unused := s.FilterByUnusedSince(vols, time.Now().Add(time.Second)); len(unused) != 0 {
+		t.Fatalf("expected the restored mount to be active, got %v", unused)
+	}
+	if err := s.RestoreSnapshot(v, "snap1"); !IsInUse(err) {
+		t.Fatalf("expected volume in use error, got %v", err)
+	}
+
+	if err := v.Unmount("ref"); err != nil {
+		t.Fatal(err)
+	}
+	if unused := s.FilterByUnusedSince(vols, time.Now().Add(time.Second)); len(unused) != 1 {
+		t.Fatalf("expected the volume to be unused after its unmount, got %v", unused)
+	}
+}
+
+func TestActivityUnknownVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-activity-unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now()
+	a := s.getActivity("plugin-volume")
+	if a.lastUsed.Before(before) {
+		t.Fatalf("expected an unknown volume to be last used now, got %v", a.lastUsed)
+	}
+	if again := s.getActivity("plugin-volume"); !again.lastUsed.Equal(a.lastUsed) {
+		t.Fatalf("expected the last use to be kept, got %v then %v", a.lastUsed, again.lastUsed)
+	}
+
+	// Reading the activity doesn't write to the metadata store.
+	err = s.db.View(func(tx *bolt.Tx) error {
+		if data := tx.Bucket([]byte(volumeBucketName)).Get([]byte("plugin-volume")); data != nil {
+			return fmt.Errorf("unexpected metadata %s", data)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+type fakeSnapshotDriver struct {
+	volume.Driver
+	snapshots map[string][]volume.Snapshot
+}
+
+func (d *fakeSnapshotDriver) Snapshot(v volume.Volume, name string) error {
+	d.snapshots[v.Name()] = append(d.snapshots[v.Name()], volume.Snapshot{Name: name})
+	return nil
+}
+
+func (d *fakeSnapshotDriver) ListSnapshots(v volume.Volume) ([]volume.Snapshot, error) {
+	return d.snapshots[v.Name()], nil
+}
+
+func (d *fakeSnapshotDriver) RestoreSnapshot(v volume.Volume, name string) error {
+	return nil
+}
+
+func (d *fakeSnapshotDriver) DeleteSnapshot(v volume.Volume, name string) error {
+	var ls []volume.Snapshot
+	for _, s := range d.snapshots[v.Name()] {
+		if s.Name != name {
+			ls = append(ls, s)
+		}
+	}
+	d.snapshots[v.Name()] = ls
+	return nil
+}
+
+func TestSnapshots(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	volumedrivers.Register(&fakeSnapshotDriver{vt.NewFakeDriver("snap"), make(map[string][]volume.Snapshot)}, "snap")
+	defer volumedrivers.Unregister("fake")
+	defer volumedrivers.Unregister("snap")
+	dir, err := ioutil.TempDir("", "test-snapshots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := s.Create("fake1", "fake", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Snapshot(v, "snap1"); !IsSnapshotsNotSupported(err) {
+		t.Fatalf("Expected snapshots not supported error, got %v", err)
+	}
+
+	v, err = s.Create("snap1", "snap", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Snapshot(v, "snap1"); err != nil {
+		t.Fatal(err)
+	}
+	ls, err := s.ListSnapshots(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls) != 1 || ls[0].Name != "snap1" {
+		t.Fatalf("Expected snapshot snap1, got %v", ls)
+	}
+
+	if _, err := v.Mount("test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RestoreSnapshot(v, "snap1"); !IsInUse(err) {
+		t.Fatalf("Expected volume in use error, got %v", err)
+	}
+	if err := v.Unmount("test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RestoreSnapshot(v, "snap1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteSnapshot(v, "snap1"); err != nil {
+		t.Fatal(err)
+	}
+	if ls, _ := s.ListSnapshots(v); len(ls) != 0 {
+		t.Fatalf("Expected no snapshots, got %v", ls)
+	}
+}
@@ -1,12 +1,16 @@
 package volume
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/symlink"
 	"github.com/docker/docker/pkg/system"
 	"github.com/opencontainers/runc/libcontainer/label"
 )
@@ -15,6 +19,10 @@ import (
 // implemented in the local package.
 const DefaultDriverName = "local"
 
+// ErrSnapshotsNotSupported is returned by the snapshot operations of the
+// drivers which cannot take snapshots of their volumes.
+var ErrSnapshotsNotSupported = errors.New("snapshots are not supported by the volume driver")
+
 // Scopes define if a volume has is cluster-wide (global) or local only.
 // Scopes are returned by the volume driver when it is queried for capabilities and then set on a volume
 const (
@@ -46,6 +54,31 @@ type Capability struct {
 	// A `local` scope indicates that the driver only manages volumes resources local to the host
 	// Scope is declared by the driver
 	Scope string
+	// Snapshots indicates that the driver can take snapshots of its volumes
+	Snapshots bool
+}
+
+// Snapshot is a point-in-time copy of a volume.
+type Snapshot struct {
+	// Name is the name of the snapshot, unique for its volume
+	Name string
+	// CreatedAt is the time the snapshot was taken. A zero time is unknown.
+	CreatedAt time.Time
+}
+
+// SnapshotDriver is implemented by the drivers which can take snapshots of
+// their volumes. The drivers return ErrSnapshotsNotSupported when snapshots
+// are not available.
+type SnapshotDriver interface {
+	Driver
+	// Snapshot takes the snapshot name of the volume.
+	Snapshot(vol Volume, name string) error
+	// ListSnapshots lists the snapshots of the volume.
+	ListSnapshots(vol Volume) ([]Snapshot, error)
+	// RestoreSnapshot reverts the content of the volume to the snapshot name.
+	RestoreSnapshot(vol Volume, name string) error
+	// DeleteSnapshot deletes the snapshot name of the volume.
+	DeleteSnapshot(vol Volume, name string) error
 }
 
 // Volume is a place to store data. It is backed by a specific driver, and can be mounted.
@@ -77,6 +110,14 @@ type ScopedVolume interface {
 	Volume
 }
 
+// TimestampedVolume wraps a volume with the time it was created and the
+// time it was last mounted or unmounted. A zero time is unknown.
+type TimestampedVolume interface {
+	CreatedAt() time.Time
+	LastUsed() time.Time
+	Volume
+}
+
 // MountPoint is the intersection point between a volume and a container. It
 // specifies which volume is to be used and where inside a container it should
 // be mounted.
@@ -102,16 +143,33 @@ type MountPoint struct {
 	// ID is the opaque ID used to pass to the volume driver.
 	// This should be set by calls to `Mount` and unset by calls to `Unmount`
 	ID string
+
+	// Subpath is the directory of the volume to mount, relative to the root
+	// of the volume. The whole volume is mounted if it is empty.
+	Subpath string `json:",omitempty"`
 }
 
 // Setup sets up a mount point by either mounting the volume if it is
-// configured, or creating the source directory if supplied.
-func (m *MountPoint) Setup(mountLabel string) (string, error) {
+// configured, or creating the source directory if supplied. When a subpath
+// is set, the subpath directory of the volume is bind-mounted in subpathDir
+// and the path of that mount is returned.
+func (m *MountPoint) Setup(mountLabel, subpathDir string) (string, error) {
 	if m.Volume != nil {
 		if m.ID == "" {
 			m.ID = stringid.GenerateNonCryptoID()
 		}
-		return m.Volume.Mount(m.ID)
+		path, err := m.Volume.Mount(m.ID)
+		if err != nil || m.Subpath == "" {
+			return path, err
+		}
+		target := filepath.Join(subpathDir, m.ID)
+		if err := mountSubpath(path, m.Subpath, target); err != nil {
+			if err := m.Volume.Unmount(m.ID); err == nil {
+				m.ID = ""
+			}
+			return "", err
+		}
+		return target, nil
 	}
 	if len(m.Source) == 0 {
 		return "", fmt.Errorf("Unable to setup mount point, neither source nor volume defined")
@@ -132,14 +190,57 @@ func (m *MountPoint) Setup(mountLabel string) (string, error) {
 	return m.Source, nil
 }
 
+// UnmountSubpath unmounts the subpath of the volume mounted in subpathDir by
+// Setup, if any.
+func (m *MountPoint) UnmountSubpath(subpathDir string) error {
+	if m.Volume == nil || m.Subpath == "" || m.ID == "" {
+		return nil
+	}
+	return unmountSubpath(filepath.Join(subpathDir, m.ID))
+}
+
 // Path returns the path of a volume in a mount point.
 func (m *MountPoint) Path() string {
 	if m.Volume != nil {
+		if m.Subpath != "" {
+			return filepath.Join(m.Volume.Path(), m.Subpath)
+		}
 		return m.Volume.Path()
 	}
 	return m.Source
 }
 
+// ValidateSubpath checks that subpath is a path relative to the root of a
+// volume which does not go above the root, like "config" or "app/config".
+func ValidateSubpath(subpath string) error {
+	clean := filepath.Clean(subpath)
+	if filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid volume subpath %q: must be a relative path in the volume", subpath)
+	}
+	return nil
+}
+
+// ResolveSubpath returns the path of the directory subpath of the volume
+// mounted at root. Symbolic links are resolved as if root was the root of
+// the filesystem, so that the subpath cannot escape the volume.
+func ResolveSubpath(root, subpath string) (string, error) {
+	if err := ValidateSubpath(subpath); err != nil {
+		return "", err
+	}
+	path, err := symlink.FollowSymlinkInScope(filepath.Join(root, subpath), root)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve volume subpath %q: %v", subpath, err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot access volume subpath %q: %v", subpath, err)
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("volume subpath %q is not a directory", subpath)
+	}
+	return path, nil
+}
+
 // Type returns the type of mount point
 func (m *MountPoint) Type() string {
 	if m.Name != "" {
@@ -0,0 +1,97 @@
+package volume
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/pkg/mount"
+)
+
+// oPath is O_PATH, which the syscall package doesn't define.
+const oPath = 010000000
+
+// openSubpath opens the directory path, relative to the root of the volume
+// mounted at root, one component at a time without following symbolic
+// links. A component replaced with a symbolic link after the subpath was
+// resolved makes the open fail, instead of leading out of the volume.
+func openSubpath(root, path string) (int, error) {
+	fd, err := syscall.Open(root, oPath|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+	if path == "." {
+		return fd, nil
+	}
+	for _, name := range strings.Split(path, string(filepath.Separator)) {
+		next, err := syscall.Openat(fd, name, oPath|syscall.O_DIRECTORY|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+		syscall.Close(fd)
+		if err != nil {
+			return -1, &os.PathError{Op: "open", Path: filepath.Join(root, path), Err: err}
+		}
+		fd = next
+	}
+	return fd, nil
+}
+
+// mountSubpath bind-mounts the directory subpath of the volume mounted at
+// root on target. The directory is mounted through the file descriptor it
+// was opened with, so that it cannot be swapped for another directory once
+// it is resolved.
+func mountSubpath(root, subpath, target string) error {
+	path, err := ResolveSubpath(root, subpath)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+	fd, err := openSubpath(root, rel)
+	if err != nil {
+		return fmt.Errorf("cannot open volume subpath %q: %v", subpath, err)
+	}
+	defer syscall.Close(fd)
+
+	if err := os.MkdirAll(target, 0700); err != nil {
+		return err
+	}
+	if err := syscall.Mount(fmt.Sprintf("/proc/self/fd/%d", fd), target, "", syscall.MS_BIND, ""); err != nil {
+		os.Remove(target)
+		return fmt.Errorf("cannot mount volume subpath %q: %v", subpath, err)
+	}
+	return nil
+}
+
+// unmountSubpath unmounts a subpath mounted by mountSubpath and removes its
+// mount point.
+func unmountSubpath(target string) error {
+	if err := mount.Unmount(target); err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// UnmountSubpaths unmounts the subpaths of volumes mounted in dir, like the
+// ones left by a container which was running when the daemon stopped.
+func UnmountSubpaths(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, fi := range files {
+		if err := unmountSubpath(filepath.Join(dir, fi.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
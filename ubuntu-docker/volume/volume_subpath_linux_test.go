@@ -0,0 +1,85 @@
+package volume
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/pkg/mount"
+)
+
+func TestOpenSubpath(t *testing.T) {
+	root, err := ioutil.TempDir("", "volume-subpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := os.MkdirAll(filepath.Join(root, "config", "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{".", "config", "config/app"} {
+		fd, err := openSubpath(root, path)
+		if err != nil {
+			t.Fatalf("openSubpath(%q) failed: %v", path, err)
+		}
+		syscall.Close(fd)
+	}
+
+	// A directory swapped for a symbolic link after the subpath was
+	// resolved is not followed.
+	if err := os.RemoveAll(filepath.Join(root, "config", "app")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc", filepath.Join(root, "config", "app")); err != nil {
+		t.Fatal(err)
+	}
+	if fd, err := openSubpath(root, "config/app"); err == nil {
+		syscall.Close(fd)
+		t.Fatal("expected openSubpath to fail on a symbolic link")
+	}
+}
+
+func TestMountSubpath(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping privileged test in short mode")
+	}
+	tmp, err := ioutil.TempDir("", "volume-subpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	root := filepath.Join(tmp, "volume")
+	if err := os.MkdirAll(filepath.Join(root, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "config", "app.conf"), []byte("conf"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	subpathDir := filepath.Join(tmp, "subpaths")
+	target := filepath.Join(subpathDir, "mount-id")
+	if err := mountSubpath(root, "config", target); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(target, "app.conf"))
+	if err != nil || string(data) != "conf" {
+		t.Fatalf("expected the subpath to be mounted on %s, got %q, %v", target, data, err)
+	}
+
+	if err := UnmountSubpaths(subpathDir); err != nil {
+		t.Fatal(err)
+	}
+	if mounted, _ := mount.Mounted(target); mounted {
+		t.Fatalf("expected %s to be unmounted", target)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, got %v", target, err)
+	}
+
+	if err := mountSubpath(root, "../config", target); err == nil {
+		t.Fatal("expected an error for a subpath out of the volume")
+	}
+}
@@ -0,0 +1,22 @@
+// +build !linux
+
+package volume
+
+import "fmt"
+
+// mountSubpath is not supported on this platform.
+func mountSubpath(root, subpath, target string) error {
+	return fmt.Errorf("volume subpaths are not supported on this platform")
+}
+
+// unmountSubpath is not supported on this platform, no subpath is ever
+// mounted.
+func unmountSubpath(target string) error {
+	return nil
+}
+
+// UnmountSubpaths is not supported on this platform, no subpath is ever
+// mounted.
+func UnmountSubpaths(dir string) error {
+	return nil
+}
@@ -1,6 +1,9 @@
 package volume
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -210,3 +213,58 @@ func TestParseMountSpecSplit(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveSubpath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks are not supported in unit tests on Windows")
+	}
+	root, err := ioutil.TempDir("", "volume-subpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := os.MkdirAll(filepath.Join(root, "config", "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the link is resolved in the volume, and not to /etc of the host
+	if err := os.Symlink("/etc", filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../config", filepath.Join(root, "config", "app", "up")); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]string{
+		"config":                 filepath.Join(root, "config"),
+		"config/app":             filepath.Join(root, "config", "app"),
+		"./config/../config/app": filepath.Join(root, "config", "app"),
+		"config/app/up":          filepath.Join(root, "config"),
+	}
+	for subpath, expected := range valid {
+		path, err := ResolveSubpath(root, subpath)
+		if err != nil {
+			t.Fatalf("ResolveSubpath(%q) failed: %v", subpath, err)
+		}
+		if path != expected {
+			t.Fatalf("Expected %s for %q, got %s", expected, subpath, path)
+		}
+	}
+
+	invalid := map[string]string{
+		"/config":      "must be a relative path",
+		"..":           "must be a relative path",
+		"../config":    "must be a relative path",
+		"config/../..": "must be a relative path",
+		"escape":       "cannot access volume subpath",
+		"missing":      "cannot access volume subpath",
+		"file":         "is not a directory",
+	}
+	for subpath, expected := range invalid {
+		if _, err := ResolveSubpath(root, subpath); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected an error with %q for %q, got %v", expected, subpath, err)
+		}
+	}
+}